@@ -41,6 +41,11 @@ func main() {
 	areaRepo := repository.NewAreaRepository(database)
 	webhookRepo := repository.NewWebhookRepository(database)
 	batchRepo := repository.NewBatchRepository(database)
+	batchJobRepo := repository.NewBatchJobRepository(database)
+	benchmarkRepo := repository.NewBenchmarkRepository(database)
+	teamRepo := repository.NewTeamRepository(database)
+	connoteRepo := repository.NewConnoteRepository(database)
+	searchRepo := repository.NewAddressSearchRepository(database)
 
 	sqlxDB := sqlx.NewDb(database, "postgres")
 	analyticsRepo := repository.NewAnalyticsRepository(sqlxDB)
@@ -50,10 +55,15 @@ func main() {
 	go hub.Run()
 
 	authSvc := service.NewAuthService(userRepo, cfg)
-	geoSvc := service.NewGeocodeService(geoRepo, settingsRepo)
+	geoSvc := service.NewGeocodeService(geoRepo, settingsRepo, cfg)
 	historySvc := service.NewHistoryService(historyRepo)
-	compSvc := service.NewComparisonService(geoSvc, historySvc)
-	batchSvc := service.NewBatchService(batchRepo, geoSvc, historySvc, analyticsRepo, hub)
+	areaSvc := service.NewAreaService(areaRepo)
+	compSvc := service.NewComparisonService(geoSvc, historySvc, areaSvc, cfg)
+	batchSvc := service.NewBatchService(batchRepo, batchJobRepo, geoSvc, historySvc, analyticsRepo, hub, areaSvc, settingsRepo, cfg)
+	benchmarkSvc := service.NewBenchmarkService(benchmarkRepo, geoSvc, cfg)
+	teamSvc := service.NewTeamService(teamRepo)
+	connoteSvc := service.NewConnoteService(connoteRepo)
+	searchSvc := service.NewAddressSearchService(searchRepo)
 	settingsSvc := service.NewSettingsService(settingsRepo)
 	dsSvc := service.NewDataSourceService(dsRepo, cfg)
 	etlSvc := service.NewETLService(dsRepo, cfg)
@@ -65,7 +75,6 @@ func main() {
 
 	// Now Scheduler can accept all dependencies including ERP
 	schedulerSvc := service.NewSchedulerService(dsSvc, etlSvc, compSvc, erpSvc)
-	areaSvc := service.NewAreaService(areaRepo)
 
 	// Start scheduler and load active jobs
 	schedulerSvc.Start()
@@ -75,8 +84,18 @@ func main() {
 	if err := schedulerSvc.ReloadErpIntegrations(context.Background()); err != nil {
 		log.Printf("Warning: Failed to load scheduled ERP Syncs: %v", err)
 	}
+	if err := schedulerSvc.AddRetentionJob(cfg.RetentionCron, batchSvc); err != nil {
+		log.Printf("Warning: Failed to schedule retention: %v", err)
+	}
 	defer schedulerSvc.Stop()
 
+	// Batch workers resume jobs left queued or interrupted by a previous run.
+	batchWorkers := service.NewBatchWorkerPool(batchJobRepo, batchSvc, cfg.BatchWorkers)
+	batchWorkers.Start()
+
+	// Reports left running by a previous process are failed.
+	benchmarkSvc.Start()
+
 	// 5. Setup Handlers
 	authHandler := handlers.NewAuthHandler(authSvc)
 	geoHandler := handlers.NewGeocodeHandler(geoSvc)
@@ -89,10 +108,14 @@ func main() {
 	analyticsHandler := handlers.NewAnalyticsHandler(analyticsRepo)
 	erpHandler := handlers.NewErpIntegrationHandler(erpSvc)
 	batchHandler := handlers.NewBatchHandler(batchSvc)
+	benchmarkHandler := handlers.NewBenchmarkHandler(benchmarkSvc)
+	teamHandler := handlers.NewTeamHandler(teamSvc)
+	connoteHandler := handlers.NewConnoteHandler(connoteSvc)
+	searchHandler := handlers.NewSearchHandler(searchSvc)
 	wsHandler := handlers.NewWSHandler(hub, cfg)
 
 	// 7. Setup Router
-	router := api.SetupRouter(cfg, authHandler, geoHandler, compHandler, settingsHandler, historyHandler, dsHandler, areaHandler, webhookHandler, analyticsHandler, erpHandler, batchHandler, wsHandler, webhookRepo)
+	router := api.SetupRouter(cfg, authHandler, geoHandler, compHandler, settingsHandler, historyHandler, dsHandler, areaHandler, webhookHandler, analyticsHandler, erpHandler, batchHandler, benchmarkHandler, teamHandler, connoteHandler, searchHandler, wsHandler, webhookRepo)
 
 	// 7. Start Server with Graceful Shutdown
 	srv := &http.Server{
@@ -121,6 +144,10 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatal("Server forced to shutdown:", err)
 	}
+	// Running jobs save their current chunk and return to the queue.
+	batchWorkers.Stop()
+	// Running benchmarks are recorded as failed.
+	benchmarkSvc.Stop()
 
 	log.Println("Server exiting")
 }
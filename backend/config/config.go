@@ -3,6 +3,8 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -21,6 +23,25 @@ type Config struct {
 	// AllowedOrigins is a comma-separated list of allowed CORS origins.
 	// Example: "https://geoverify.vercel.app,http://localhost:8080"
 	AllowedOrigins string
+	// MinVerifiablePrecision is the least specific geocode precision whose
+	// distance is still meaningful (rooftop, street, district, city, region).
+	// Coarser matches are labelled "unverifiable" instead of being scored.
+	MinVerifiablePrecision string
+	// ProviderCostPer1000 is the USD price per 1,000 requests for each geocoding
+	// provider, used for benchmark cost estimates.
+	// Example: "Nominatim=0,Geoapify=1,PositionStack=1,GoogleMaps=5"
+	ProviderCostPer1000 map[string]float64
+	// BatchWorkers is the number of batch jobs this instance processes at once.
+	BatchWorkers int
+	// GeocodeRPS and GeocodeConcurrency cap, per provider, the requests per
+	// second across the instance and the parallel requests of one batch chunk.
+	// Example: "Nominatim=1,Geoapify=5,PositionStack=5,GoogleMaps=25"
+	GeocodeRPS         map[string]float64
+	GeocodeConcurrency map[string]int
+	// RetentionCron is the cron schedule of the job that applies the users'
+	// retention policies; "off" disables it. Policies default to keeping
+	// everything, so the job is a no-op until a user sets one.
+	RetentionCron string
 }
 
 func LoadConfig() *Config {
@@ -39,6 +60,14 @@ func LoadConfig() *Config {
 		JWTSecret:        getEnv("JWT_SECRET", "super-secret-default-key-change-in-prod"),
 		AESEncryptionKey: getEnv("AES_ENCRYPTION_KEY", "0123456789abcdef0123456789abcdef"),
 		AllowedOrigins:   getEnv("ALLOWED_ORIGINS", "http://localhost:8080,http://localhost:5173"),
+
+		MinVerifiablePrecision: getEnv("GEOCODE_MIN_PRECISION", "street"),
+		ProviderCostPer1000:    parseNumberTable("GEOCODE_COST_PER_1000", getEnv("GEOCODE_COST_PER_1000", "Nominatim=0,Geoapify=1,PositionStack=1,GoogleMaps=5")),
+		BatchWorkers:           getEnvInt("BATCH_WORKERS", 2),
+		// Nominatim's usage policy allows one request per second, in total.
+		GeocodeRPS:         parseNumberTable("GEOCODE_RPS", getEnv("GEOCODE_RPS", "Nominatim=1,Geoapify=5,PositionStack=5,GoogleMaps=25")),
+		GeocodeConcurrency: parseIntTable("GEOCODE_CONCURRENCY", getEnv("GEOCODE_CONCURRENCY", "Nominatim=1,Geoapify=5,PositionStack=4,GoogleMaps=10")),
+		RetentionCron:      getEnv("RETENTION_CRON", "0 3 * * *"),
 	}
 
 	if cfg.AppEnv == "production" {
@@ -64,6 +93,15 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+// getEnvInt reads a positive integer, falling back on missing or invalid values.
+func getEnvInt(key string, fallback int) int {
+	v, err := strconv.Atoi(getEnv(key, ""))
+	if err != nil || v <= 0 {
+		return fallback
+	}
+	return v
+}
+
 // getEnvPriority tries multiple env var keys in order, returns first non-empty value.
 func getEnvPriority(keys []string, fallback string) string {
 	for _, key := range keys {
@@ -73,3 +111,32 @@ func getEnvPriority(keys []string, fallback string) string {
 	}
 	return fallback
 }
+
+// parseNumberTable parses "Name=number,Name=number" read from env var key.
+// Malformed pairs are skipped with a warning so a typo does not stop the server.
+func parseNumberTable(key, raw string) map[string]float64 {
+	return parseTable(key, raw, func(s string) (float64, error) { return strconv.ParseFloat(s, 64) })
+}
+
+// parseIntTable is parseNumberTable for counts; "2.5" is rejected rather
+// than truncated.
+func parseIntTable(key, raw string) map[string]int {
+	return parseTable(key, raw, strconv.Atoi)
+}
+
+func parseTable[T any](key, raw string, parse func(string) (T, error)) map[string]T {
+	table := make(map[string]T)
+	for _, pair := range strings.Split(raw, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		v, err := parse(strings.TrimSpace(value))
+		if err != nil {
+			log.Printf("[WARN] %s: invalid value for %q: %v", key, name, err)
+			continue
+		}
+		table[strings.TrimSpace(name)] = v
+	}
+	return table
+}
@@ -67,3 +67,27 @@ func (h *AnalyticsHandler) GetSLATrends(c *gin.Context) {
 
 	c.JSON(http.StatusOK, trends)
 }
+
+// GetFailureReasons groups failed batch items by reason code.
+// GET /api/advanced-analytics/failure-reasons?days=30
+func (h *AnalyticsHandler) GetFailureReasons(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "30"))
+
+	reasons, err := h.repo.GetFailureReasons(c.Request.Context(), int64(userID), days)
+	if err != nil {
+		log.Printf("[AnalyticsHandler] GetFailureReasons error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve failure reasons"})
+		return
+	}
+
+	if reasons == nil {
+		reasons = []domain.FailureReasonAgg{}
+	}
+
+	c.JSON(http.StatusOK, reasons)
+}
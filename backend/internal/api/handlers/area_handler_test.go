@@ -59,6 +59,11 @@ func (m *mockAreaService) GetAreasContainingPoint(ctx context.Context, lat float
 	return nil, args.Error(1)
 }
 
+func (m *mockAreaService) IsOutsideServiceAreas(ctx context.Context, lat float64, lng float64) (bool, error) {
+	args := m.Called(ctx, lat, lng)
+	return args.Bool(0), args.Error(1)
+}
+
 func setupAreaRouter(areaSvc service.AreaService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
@@ -1,14 +1,28 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
 	"geoaccuracy-backend/internal/domain"
+	"geoaccuracy-backend/internal/service"
 )
 
+// maxImportUploadBytes bounds multipart spreadsheet uploads.
+const maxImportUploadBytes = 200 << 20
+
 type BatchHandler struct {
 	batchService domain.BatchService
 }
@@ -19,6 +33,7 @@ func NewBatchHandler(batchService domain.BatchService) *BatchHandler {
 
 type createBatchRequest struct {
 	Name string `json:"name"`
+	domain.BatchMetadata
 }
 
 func (h *BatchHandler) CreateBatch(c *gin.Context) {
@@ -33,8 +48,12 @@ func (h *BatchHandler) CreateBatch(c *gin.Context) {
 		return
 	}
 
-	batch, err := h.batchService.CreateBatch(c.Request.Context(), int64(userID), req.Name)
+	batch, err := h.batchService.CreateBatch(c.Request.Context(), int64(userID), req.Name, req.BatchMetadata)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidBatchMetadata) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -42,14 +61,36 @@ func (h *BatchHandler) CreateBatch(c *gin.Context) {
 	c.JSON(http.StatusCreated, batch)
 }
 
+// ListBatches lists the batches the user owns or that are shared with them,
+// newest first, each with the user's access. Query parameters:
+//
+//	scope=owned|shared                   only owned or only shared batches
+//	archived=true                        list the archived batches instead
+//	q                                    substring of the name, client or region
+//	client, region                       exact match, ignoring case
+//	source, status                       comma-separated lists, any of
+//	tag                                  comma-separated list, all of
+//	delivery_from, delivery_to           YYYY-MM-DD, overlapping the delivery range
+//	created_from, created_to             YYYY-MM-DD, inclusive
+//	limit, offset
 func (h *BatchHandler) ListBatches(c *gin.Context) {
 	userID, ok := getUserID(c)
 	if !ok {
 		return
 	}
 
-	batches, err := h.batchService.ListUserBatches(c.Request.Context(), int64(userID))
+	filter, err := parseBatchFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	batches, err := h.batchService.ListUserBatches(c.Request.Context(), int64(userID), filter)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidBatchMetadata) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -57,6 +98,84 @@ func (h *BatchHandler) ListBatches(c *gin.Context) {
 	c.JSON(http.StatusOK, batches)
 }
 
+func parseBatchFilter(c *gin.Context) (domain.BatchFilter, error) {
+	f := domain.BatchFilter{
+		Scope:        c.Query("scope"),
+		Query:        c.Query("q"),
+		Client:       c.Query("client"),
+		Region:       c.Query("region"),
+		Sources:      parseQueryList(c.Query("source")),
+		Statuses:     parseQueryList(c.Query("status")),
+		Tags:         parseQueryList(c.Query("tag")),
+		DeliveryFrom: c.Query("delivery_from"),
+		DeliveryTo:   c.Query("delivery_to"),
+		CreatedFrom:  c.Query("created_from"),
+		CreatedTo:    c.Query("created_to"),
+	}
+
+	var err error
+	if f.Archived, err = strconv.ParseBool(c.DefaultQuery("archived", "false")); err != nil {
+		return f, errors.New("archived must be true or false")
+	}
+	f.Limit, f.Offset, err = parseLimitOffset(c)
+	return f, err
+}
+
+// parseLimitOffset reads the optional limit and offset query parameters;
+// absent ones are 0.
+func parseLimitOffset(c *gin.Context) (limit, offset int, err error) {
+	if raw := c.Query("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 1 {
+			return 0, 0, errors.New("limit must be a positive integer")
+		}
+	}
+	if raw := c.Query("offset"); raw != "" {
+		if offset, err = strconv.Atoi(raw); err != nil || offset < 0 {
+			return 0, 0, errors.New("offset must be a non-negative integer")
+		}
+	}
+	return limit, offset, nil
+}
+
+// UpdateBatchMetadata replaces the batch's name and metadata.
+func (h *BatchHandler) UpdateBatchMetadata(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+
+	var req createBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	batch, err := h.batchService.UpdateBatchMetadata(c.Request.Context(), int64(userID), batchID, req.Name, req.BatchMetadata)
+	if err != nil {
+		if err.Error() == "batch not found or access denied" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidBatchMetadata) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, batch)
+}
+
+// UploadSystemData stores system records. ?mode=strict rejects the whole
+// upload with 422 if validation finds any issue; the default lenient mode
+// stores the valid rows and keeps the issues on the batch.
 func (h *BatchHandler) UploadSystemData(c *gin.Context) {
 	// FIX BUG-03: Capture userID and pass it for ownership check in service layer.
 	userID, ok := getUserID(c)
@@ -70,6 +189,10 @@ func (h *BatchHandler) UploadSystemData(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
 		return
 	}
+	mode, ok := parseUploadMode(c)
+	if !ok {
+		return
+	}
 
 	var records []domain.SystemRecord
 	if err := c.ShouldBindJSON(&records); err != nil {
@@ -77,18 +200,29 @@ func (h *BatchHandler) UploadSystemData(c *gin.Context) {
 		return
 	}
 
-	if err := h.batchService.UploadSystemData(c.Request.Context(), int64(userID), batchID, records); err != nil {
+	report, err := h.batchService.UploadSystemData(c.Request.Context(), int64(userID), batchID, records, mode)
+	if err != nil {
 		if err.Error() == "batch not found or access denied" {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 			return
 		}
+		if errors.Is(err, domain.ErrBatchArchived) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, domain.ErrUploadRejected) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "validation": report.Validation})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "System data uploaded successfully"})
+	// The report lists low-quality addresses so the shipper can fix them at the source.
+	c.JSON(http.StatusOK, gin.H{"message": "System data uploaded successfully", "report": report})
 }
 
+// UploadFieldData stores field GPS records; ?mode works as for UploadSystemData.
 func (h *BatchHandler) UploadFieldData(c *gin.Context) {
 	// FIX BUG-03: Capture userID and pass it for ownership check in service layer.
 	userID, ok := getUserID(c)
@@ -102,6 +236,10 @@ func (h *BatchHandler) UploadFieldData(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
 		return
 	}
+	mode, ok := parseUploadMode(c)
+	if !ok {
+		return
+	}
 
 	var records []domain.FieldRecord
 	if err := c.ShouldBindJSON(&records); err != nil {
@@ -109,16 +247,146 @@ func (h *BatchHandler) UploadFieldData(c *gin.Context) {
 		return
 	}
 
-	if err := h.batchService.UploadFieldData(c.Request.Context(), int64(userID), batchID, records); err != nil {
+	validation, err := h.batchService.UploadFieldData(c.Request.Context(), int64(userID), batchID, records, mode)
+	if err != nil {
+		if err.Error() == "batch not found or access denied" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+		if errors.Is(err, domain.ErrBatchArchived) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, domain.ErrUploadRejected) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "validation": validation})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Field data uploaded successfully", "validation": validation})
+}
+
+// parseUploadMode reads ?mode=strict|lenient, defaulting to lenient. It
+// writes a 400 response and returns false for any other value.
+func parseUploadMode(c *gin.Context) (domain.UploadMode, bool) {
+	switch mode := domain.UploadMode(c.DefaultQuery("mode", string(domain.UploadModeLenient))); mode {
+	case domain.UploadModeLenient, domain.UploadModeStrict:
+		return mode, true
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be 'strict' or 'lenient'"})
+	return "", false
+}
+
+// ListUploadIssues returns the issues recorded by lenient uploads.
+// GET /api/batches/:id/upload-issues
+func (h *BatchHandler) ListUploadIssues(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+
+	issues, err := h.batchService.ListUploadIssues(c.Request.Context(), int64(userID), batchID)
+	if err != nil {
+		if err.Error() == "batch not found or access denied" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, issues)
+}
+
+// UploadSystemFile imports system records from a CSV or XLSX file.
+// POST /api/batches/:id/system-data/file (multipart: file, mapping, delimiter)
+func (h *BatchHandler) UploadSystemFile(c *gin.Context) {
+	h.importFile(c, h.batchService.ImportSystemFile)
+}
+
+// UploadFieldFile imports field GPS records from a CSV or XLSX file.
+// POST /api/batches/:id/field-data/file (multipart: file, mapping, delimiter)
+func (h *BatchHandler) UploadFieldFile(c *gin.Context) {
+	h.importFile(c, h.batchService.ImportFieldFile)
+}
+
+type importFunc func(ctx context.Context, userID int64, batchID uuid.UUID, src io.Reader, opts domain.ImportOptions) (*domain.ImportReport, error)
+
+// importFile reads the multipart form shared by both file endpoints. "mapping"
+// is an optional JSON domain.ColumnMapping; "delimiter" optionally forces the
+// CSV delimiter (use "\t" for tab).
+func (h *BatchHandler) importFile(c *gin.Context, importFn importFunc) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportUploadBytes)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing 'file' field: " + err.Error()})
+		return
+	}
+
+	opts := domain.ImportOptions{Filename: fileHeader.Filename}
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &opts.Mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid column mapping: " + err.Error()})
+			return
+		}
+	}
+	if d := c.PostForm("delimiter"); d != "" {
+		if d == "\\t" {
+			d = "\t"
+		}
+		r, size := utf8.DecodeRuneInString(d)
+		if size != len(d) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Delimiter must be a single character"})
+			return
+		}
+		opts.Delimiter = r
+	}
+
+	f, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot read uploaded file"})
+		return
+	}
+	defer f.Close()
+
+	report, err := importFn(c.Request.Context(), int64(userID), batchID, f, opts)
+	if err != nil {
 		if err.Error() == "batch not found or access denied" {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 			return
 		}
+		if errors.Is(err, domain.ErrBatchArchived) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidImport) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Field data uploaded successfully"})
+	c.JSON(http.StatusOK, report)
 }
 
 func (h *BatchHandler) ProcessBatch(c *gin.Context) {
@@ -140,37 +408,721 @@ func (h *BatchHandler) ProcessBatch(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 			return
 		}
+		if errors.Is(err, domain.ErrBatchJobActive) || errors.Is(err, domain.ErrBatchArchived) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// 202 Accepted: processing runs asynchronously via WebSocket
-	c.JSON(http.StatusAccepted, gin.H{"message": "Batch processing started"})
+	// 202 Accepted: the job is queued; progress is reported via WebSocket
+	c.JSON(http.StatusAccepted, gin.H{"message": "Batch processing queued"})
 }
 
-func (h *BatchHandler) GetBatchResults(c *gin.Context) {
-	// FIX BUG-03: Capture userID and pass it for ownership check in service layer.
+// ReprocessBatch re-geocodes only the items matching the filter in the body,
+// e.g. {"geocode_status": ["failed"], "bypass_cache": true}.
+// POST /api/batches/:id/reprocess
+func (h *BatchHandler) ReprocessBatch(c *gin.Context) {
 	userID, ok := getUserID(c)
 	if !ok {
 		return
 	}
 
-	batchIDStr := c.Param("id")
-	batchID, err := uuid.Parse(batchIDStr)
+	batchID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
 		return
 	}
 
-	items, err := h.batchService.GetBatchResults(c.Request.Context(), int64(userID), batchID)
+	var req domain.ReprocessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	queued, err := h.batchService.ReprocessBatch(c.Request.Context(), int64(userID), batchID, req)
 	if err != nil {
 		if err.Error() == "batch not found or access denied" {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 			return
 		}
+		if errors.Is(err, service.ErrEmptyReprocessFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, domain.ErrBatchJobActive) || errors.Is(err, domain.ErrBatchArchived) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, items)
+	if queued == 0 {
+		c.JSON(http.StatusOK, gin.H{"message": "No items matched the filter", "queued": 0})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Batch reprocessing queued", "queued": queued})
+}
+
+// PauseBatch stops processing after the current item.
+// POST /api/batches/:id/pause
+func (h *BatchHandler) PauseBatch(c *gin.Context) {
+	h.controlBatch(c, h.batchService.PauseBatch, "Batch processing paused")
+}
+
+// ResumeBatch continues a paused batch from its last processed item.
+// POST /api/batches/:id/resume
+func (h *BatchHandler) ResumeBatch(c *gin.Context) {
+	h.controlBatch(c, h.batchService.ResumeBatch, "Batch processing resumed")
+}
+
+// CancelBatch stops processing for good.
+// POST /api/batches/:id/cancel
+func (h *BatchHandler) CancelBatch(c *gin.Context) {
+	h.controlBatch(c, h.batchService.CancelBatch, "Batch processing cancelled")
+}
+
+// ArchiveBatch makes a batch read-only and hides it from the default list.
+// POST /api/batches/:id/archive
+func (h *BatchHandler) ArchiveBatch(c *gin.Context) {
+	h.controlBatch(c, h.batchService.ArchiveBatch, "Batch archived")
+}
+
+// UnarchiveBatch makes an archived batch editable again.
+// POST /api/batches/:id/unarchive
+func (h *BatchHandler) UnarchiveBatch(c *gin.Context) {
+	h.controlBatch(c, h.batchService.UnarchiveBatch, "Batch unarchived")
+}
+
+// DeleteBatch removes a batch with its items and courier performance rows.
+// DELETE /api/batches/:id
+func (h *BatchHandler) DeleteBatch(c *gin.Context) {
+	h.controlBatch(c, h.batchService.DeleteBatch, "Batch deleted")
+}
+
+// ListBatchShares lists who the batch is shared with.
+// GET /api/batches/:id/shares
+func (h *BatchHandler) ListBatchShares(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+
+	shares, err := h.batchService.ListBatchShares(c.Request.Context(), int64(userID), batchID)
+	if err != nil {
+		h.writeShareError(c, err)
+		return
+	}
+	if shares == nil {
+		shares = []domain.BatchShare{}
+	}
+	c.JSON(http.StatusOK, shares)
+}
+
+// ShareBatch shares the batch with a user or team, or changes the permission
+// of an existing share. Only the owner may share.
+// PUT /api/batches/:id/shares
+func (h *BatchHandler) ShareBatch(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+
+	var req domain.ShareBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	share, err := h.batchService.ShareBatch(c.Request.Context(), int64(userID), batchID, req)
+	if err != nil {
+		h.writeShareError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, share)
+}
+
+// UnshareBatch removes one share. Only the owner may unshare.
+// DELETE /api/batches/:id/shares/:shareId
+func (h *BatchHandler) UnshareBatch(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+	shareID, err := strconv.ParseInt(c.Param("shareId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid share ID"})
+		return
+	}
+
+	if err := h.batchService.UnshareBatch(c.Request.Context(), int64(userID), batchID, shareID); err != nil {
+		h.writeShareError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Share removed"})
+}
+
+type transferBatchRequest struct {
+	UserID     int64 `json:"user_id" binding:"required"`
+	KeepAccess bool  `json:"keep_access"` // leave the previous owner an editor share
+}
+
+// TransferBatch makes another user the owner. The previous owner loses
+// access unless keep_access is set.
+// POST /api/batches/:id/transfer
+func (h *BatchHandler) TransferBatch(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+
+	var req transferBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if err := h.batchService.TransferBatchOwnership(c.Request.Context(), int64(userID), batchID, req.UserID, req.KeepAccess); err != nil {
+		h.writeShareError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Batch ownership transferred"})
+}
+
+func (h *BatchHandler) writeShareError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "batch not found or access denied":
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	case errors.Is(err, service.ErrInvalidShare):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrShareTargetNotFound), errors.Is(err, service.ErrShareNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrBatchJobActive):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (h *BatchHandler) controlBatch(c *gin.Context, action func(ctx context.Context, userID int64, batchID uuid.UUID) error, message string) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+
+	if err := action(c.Request.Context(), int64(userID), batchID); err != nil {
+		if err.Error() == "batch not found or access denied" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+		if errors.Is(err, domain.ErrBatchJobNotActive) || errors.Is(err, domain.ErrBatchJobStopping) ||
+			errors.Is(err, domain.ErrBatchJobActive) || errors.Is(err, domain.ErrBatchArchived) || errors.Is(err, domain.ErrBatchPurged) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+// GetBatchJob returns the latest processing job, including its checkpointed progress.
+// GET /api/batches/:id/job
+func (h *BatchHandler) GetBatchJob(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+
+	job, err := h.batchService.GetBatchJob(c.Request.Context(), int64(userID), batchID)
+	if err != nil {
+		if err.Error() == "batch not found or access denied" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if job == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Batch has not been processed yet"})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// GetBatchStats returns distance percentiles, a histogram and breakdowns.
+// GET /api/batches/:id/stats?buckets=0,0.05,0.1,0.5,1 (edges in km)
+func (h *BatchHandler) GetBatchStats(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+
+	var edges []float64
+	for _, raw := range parseQueryList(c.Query("buckets")) {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "buckets must be comma-separated numbers"})
+			return
+		}
+		edges = append(edges, v)
+	}
+
+	stats, err := h.batchService.GetBatchStats(c.Request.Context(), int64(userID), batchID, edges)
+	if err != nil {
+		if err.Error() == "batch not found or access denied" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidHistogram) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// DiffBatches compares the batch with another one by connote.
+// GET /api/batches/:id/diff/:other — :id is the base, :other the re-run.
+func (h *BatchHandler) DiffBatches(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	baseID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+	targetID, err := uuid.Parse(c.Param("other"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+
+	diff, err := h.batchService.DiffBatches(c.Request.Context(), int64(userID), baseID, targetID)
+	if err != nil {
+		if err.Error() == "batch not found or access denied" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+		if errors.Is(err, service.ErrSameBatch) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// ReconcileBatch lists system records without a field report, field reports
+// without a system record, and suggested connote matches between them.
+// GET /api/batches/:id/reconciliation
+func (h *BatchHandler) ReconcileBatch(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+
+	report, err := h.batchService.ReconcileBatch(c.Request.Context(), int64(userID), batchID)
+	if err != nil {
+		if err.Error() == "batch not found or access denied" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// AcceptConnoteMatches merges confirmed system/field pairs into one item.
+// POST /api/batches/:id/reconciliation/accept
+func (h *BatchHandler) AcceptConnoteMatches(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+
+	var req domain.AcceptMatchesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	result, err := h.batchService.AcceptConnoteMatches(c.Request.Context(), int64(userID), batchID, req.Matches)
+	if err != nil {
+		if err.Error() == "batch not found or access denied" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+		if errors.Is(err, domain.ErrBatchJobActive) || errors.Is(err, domain.ErrBatchArchived) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetReviewQueue lists the batch's flagged items for manual review, worst
+// distance first, with counts per review status.
+// GET /api/batches/:id/review-queue?review_status=unreviewed,in_review&accuracy_level=inaccurate&assigned=me&limit=50&offset=0
+func (h *BatchHandler) GetReviewQueue(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+
+	q := domain.ReviewQueueQuery{AccuracyLevels: parseQueryList(c.Query("accuracy_level"))}
+	for _, st := range parseQueryList(c.Query("review_status")) {
+		q.Statuses = append(q.Statuses, domain.ReviewStatus(st))
+	}
+	switch c.Query("assigned") {
+	case "":
+	case "me":
+		me := int64(userID)
+		q.ReviewerID = &me
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "assigned must be me"})
+		return
+	}
+	if q.Limit, q.Offset, err = parseLimitOffset(c); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.batchService.GetReviewQueue(c.Request.Context(), int64(userID), batchID, q)
+	if err != nil {
+		h.writeReviewError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// ReviewBatchItems claims, releases or resolves many items at once. Items
+// that cannot make the transition are returned as skipped.
+// POST /api/batches/:id/review
+func (h *BatchHandler) ReviewBatchItems(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+
+	var req domain.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+
+	result, err := h.batchService.ReviewBatchItems(c.Request.Context(), int64(userID), batchID, req)
+	if err != nil {
+		h.writeReviewError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ListMyReviews lists the items the user has claimed, across batches.
+// GET /api/review-queue?limit=50
+func (h *BatchHandler) ListMyReviews(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	limit, _, err := parseLimitOffset(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	items, err := h.batchService.ListMyReviews(c.Request.Context(), int64(userID), limit)
+	if err != nil {
+		h.writeReviewError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+func (h *BatchHandler) writeReviewError(c *gin.Context, err error) {
+	switch {
+	case err.Error() == "batch not found or access denied":
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	case errors.Is(err, service.ErrInvalidReview):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrBatchArchived):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (h *BatchHandler) GetBatchResults(c *gin.Context) {
+	// FIX BUG-03: Capture userID and pass it for ownership check in service layer.
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	batchIDStr := c.Param("id")
+	batchID, err := uuid.Parse(batchIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+
+	q, err := parseBatchItemQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.batchService.ListBatchResults(c.Request.Context(), int64(userID), batchID, q)
+	if err != nil {
+		if err.Error() == "batch not found or access denied" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidResultsQuery) || errors.Is(err, domain.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// parseBatchItemQuery reads the results filters from the query string:
+//
+//	accuracy_level, geocode_status, courier  comma-separated lists
+//	min_distance_km, max_distance_km         numbers
+//	connote                                  substring match
+//	bbox=minLng,minLat,maxLng,maxLat         with bbox_match=any|field|system
+//	sort, order=asc|desc, limit, cursor
+//
+// Results are always paged; the whole batch is available from /export.
+func parseBatchItemQuery(c *gin.Context) (domain.BatchItemQuery, error) {
+	q := domain.BatchItemQuery{
+		AccuracyLevels:  parseQueryList(c.Query("accuracy_level")),
+		GeocodeStatuses: parseQueryList(c.Query("geocode_status")),
+		CourierIDs:      parseQueryList(c.Query("courier")),
+		Connote:         strings.TrimSpace(c.Query("connote")),
+		Sort:            c.Query("sort"),
+		Cursor:          c.Query("cursor"),
+	}
+
+	var err error
+	if q.MinDistanceKm, err = parseOptionalFloat(c, "min_distance_km"); err != nil {
+		return q, err
+	}
+	if q.MaxDistanceKm, err = parseOptionalFloat(c, "max_distance_km"); err != nil {
+		return q, err
+	}
+
+	switch strings.ToLower(c.DefaultQuery("order", "asc")) {
+	case "asc":
+	case "desc":
+		q.Desc = true
+	default:
+		return q, errors.New("order must be asc or desc")
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		if q.Limit, err = strconv.Atoi(raw); err != nil || q.Limit < 1 {
+			return q, errors.New("limit must be a positive integer")
+		}
+	}
+
+	if raw := c.Query("bbox"); raw != "" {
+		parts := strings.Split(raw, ",")
+		if len(parts) != 4 {
+			return q, errors.New("bbox must be minLng,minLat,maxLng,maxLat")
+		}
+		var v [4]float64
+		for i, p := range parts {
+			if v[i], err = strconv.ParseFloat(strings.TrimSpace(p), 64); err != nil {
+				return q, errors.New("bbox must be minLng,minLat,maxLng,maxLat")
+			}
+		}
+		q.BBox = &domain.BoundingBox{MinLng: v[0], MinLat: v[1], MaxLng: v[2], MaxLat: v[3], Match: c.Query("bbox_match")}
+	}
+	return q, nil
+}
+
+// parseQueryList splits a comma-separated filter value, dropping blanks.
+func parseQueryList(raw string) []string {
+	var values []string
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func parseOptionalFloat(c *gin.Context, key string) (*float64, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a number", key)
+	}
+	return &v, nil
+}
+
+// ExportBatch streams the batch results as a file download.
+// GET /api/batches/:id/export?format=csv|xlsx|geojson|kml|gpkg&locale=id|en&columns=connote,distance_m,...
+func (h *BatchHandler) ExportBatch(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid batch ID"})
+		return
+	}
+
+	opts := domain.ExportOptions{
+		Format:  c.DefaultQuery("format", service.ExportFormatCSV),
+		Columns: service.ParseExportColumns(c.Query("columns")),
+		Locale:  c.Query("locale"),
+	}
+
+	// Large exports outlive the server's write timeout.
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	format := strings.ToLower(opts.Format)
+	w := &exportWriter{c: c, contentType: exportContentTypes[format], filename: fmt.Sprintf("batch-%s.%s", batchID, format)}
+	err = h.batchService.ExportBatch(c.Request.Context(), int64(userID), batchID, w, opts)
+	if err == nil {
+		return
+	}
+	if w.started {
+		// Headers are already sent; the truncated download is all we can do.
+		log.Printf("[BatchHandler] ExportBatch %s aborted: %v", batchID, err)
+		c.Abort()
+		return
+	}
+	if err.Error() == "batch not found or access denied" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+	if errors.Is(err, service.ErrInvalidExport) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+var exportContentTypes = map[string]string{
+	service.ExportFormatCSV:        "text/csv; charset=utf-8",
+	service.ExportFormatXLSX:       "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	service.ExportFormatGeoJSON:    "application/geo+json",
+	service.ExportFormatKML:        "application/vnd.google-earth.kml+xml",
+	service.ExportFormatGeoPackage: "application/geopackage+sqlite3",
+}
+
+// exportWriter sends the download headers on the first write, so errors that
+// happen before any output can still be answered with JSON.
+type exportWriter struct {
+	c           *gin.Context
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (w *exportWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.c.Header("Content-Type", w.contentType)
+		w.c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, w.filename))
+		w.c.Status(http.StatusOK)
+	}
+	return w.c.Writer.Write(p)
 }
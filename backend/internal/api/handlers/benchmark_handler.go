@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"geoaccuracy-backend/internal/domain"
+	"geoaccuracy-backend/internal/service"
+)
+
+// maxGroundTruthUploadBytes bounds the CSV accepted by CreateDataset.
+const maxGroundTruthUploadBytes = 10 << 20
+
+type BenchmarkHandler struct {
+	svc domain.BenchmarkService
+}
+
+func NewBenchmarkHandler(svc domain.BenchmarkService) *BenchmarkHandler {
+	return &BenchmarkHandler{svc: svc}
+}
+
+// CreateDataset uploads a ground-truth CSV.
+// POST /api/ground-truth (multipart: name, file)
+func (h *BenchmarkHandler) CreateDataset(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxGroundTruthUploadBytes)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File CSV wajib diunggah pada field 'file'"})
+		return
+	}
+	f, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Gagal membaca file"})
+		return
+	}
+	defer f.Close()
+
+	entries, err := service.ParseGroundTruthCSV(f)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ds, err := h.svc.CreateDataset(c.Request.Context(), int64(userID), c.PostForm("name"), entries)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidDataset) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("BenchmarkHandler.CreateDataset error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menyimpan dataset"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, ds)
+}
+
+// GET /api/ground-truth
+func (h *BenchmarkHandler) ListDatasets(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	datasets, err := h.svc.ListDatasets(c.Request.Context(), int64(userID))
+	if err != nil {
+		log.Printf("BenchmarkHandler.ListDatasets error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil daftar dataset"})
+		return
+	}
+
+	c.JSON(http.StatusOK, datasets)
+}
+
+// DELETE /api/ground-truth/:id
+func (h *BenchmarkHandler) DeleteDataset(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteDataset(c.Request.Context(), int64(userID), id); err != nil {
+		if errors.Is(err, service.ErrDatasetNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Dataset tidak ditemukan"})
+			return
+		}
+		log.Printf("BenchmarkHandler.DeleteDataset error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal menghapus dataset"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Dataset dihapus"})
+}
+
+type startBenchmarkRequest struct {
+	Providers []string `json:"providers"` // optional; defaults to every configured provider
+}
+
+// StartBenchmark queues a benchmark run and returns the pending report.
+// POST /api/ground-truth/:id/benchmarks
+func (h *BenchmarkHandler) StartBenchmark(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req startBenchmarkRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+
+	report, err := h.svc.StartBenchmark(c.Request.Context(), int64(userID), id, req.Providers)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrDatasetNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Dataset tidak ditemukan"})
+		case errors.Is(err, service.ErrProviderNotConfigured):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			log.Printf("BenchmarkHandler.StartBenchmark error: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal memulai benchmark"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, report)
+}
+
+// GET /api/ground-truth/:id/benchmarks
+func (h *BenchmarkHandler) ListReports(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	reports, err := h.svc.ListReports(c.Request.Context(), int64(userID), id)
+	if err != nil {
+		log.Printf("BenchmarkHandler.ListReports error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil daftar laporan benchmark"})
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
+// GET /api/benchmarks/:id
+func (h *BenchmarkHandler) GetReport(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.svc.GetReport(c.Request.Context(), int64(userID), id)
+	if err != nil {
+		if errors.Is(err, service.ErrReportNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Laporan benchmark tidak ditemukan"})
+			return
+		}
+		log.Printf("BenchmarkHandler.GetReport error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gagal mengambil laporan benchmark"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
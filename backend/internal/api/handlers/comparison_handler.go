@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"encoding/json"
+	"log"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -9,6 +13,8 @@ import (
 	"geoaccuracy-backend/internal/service"
 )
 
+const ndjsonContentType = "application/x-ndjson"
+
 type ComparisonHandler struct {
 	compService service.ComparisonService
 }
@@ -17,6 +23,11 @@ func NewComparisonHandler(compService service.ComparisonService) *ComparisonHand
 	return &ComparisonHandler{compService: compService}
 }
 
+// ValidateBatch validates a batch of addresses against field coordinates.
+// POST /api/compare
+//
+// Clients sending "Accept: application/x-ndjson" receive one ValidationResult
+// per line, in input order, as soon as each result is ready.
 func (h *ComparisonHandler) ValidateBatch(c *gin.Context) {
 	userID, ok := getUserID(c)
 	if !ok {
@@ -29,6 +40,11 @@ func (h *ComparisonHandler) ValidateBatch(c *gin.Context) {
 		return
 	}
 
+	if strings.Contains(c.GetHeader("Accept"), ndjsonContentType) {
+		h.streamValidateBatch(c, userID, req)
+		return
+	}
+
 	// Pass userID so the service can persist a session summary in history.
 	res, err := h.compService.ValidateBatch(c.Request.Context(), userID, req)
 	if err != nil {
@@ -38,3 +54,31 @@ func (h *ComparisonHandler) ValidateBatch(c *gin.Context) {
 
 	c.JSON(http.StatusOK, res)
 }
+
+// streamValidateBatch writes results as NDJSON. Large batches can outlive the
+// server's WriteTimeout, so the write deadline is lifted for this response only.
+func (h *ComparisonHandler) streamValidateBatch(c *gin.Context, userID int, req domain.BatchValidationRequest) {
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("WARN: could not clear write deadline for NDJSON stream: %v", err)
+	}
+
+	c.Writer.Header().Set("Content-Type", ndjsonContentType)
+	c.Writer.Header().Set("X-Content-Type-Options", "nosniff")
+	c.Writer.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(c.Writer)
+	err := h.compService.StreamValidateBatch(c.Request.Context(), userID, req, func(res domain.ValidationResult) error {
+		if err := enc.Encode(res); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+
+	if err != nil {
+		log.Printf("ERROR streaming batch validation: %v", err)
+		// Headers are already sent; report the failure as a trailing line.
+		_ = enc.Encode(gin.H{"error": err.Error()})
+		c.Writer.Flush()
+	}
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"geoaccuracy-backend/internal/domain"
+	"geoaccuracy-backend/internal/service"
+)
+
+type ConnoteHandler struct {
+	svc domain.ConnoteService
+}
+
+func NewConnoteHandler(svc domain.ConnoteService) *ConnoteHandler {
+	return &ConnoteHandler{svc: svc}
+}
+
+// GetTimeline lists where a connote appeared: batch items of every source
+// plus webhook and ERP courier events, oldest first.
+// GET /api/connotes/:connote/timeline?limit=200
+func (h *ConnoteHandler) GetTimeline(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	limit, _, err := parseLimitOffset(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	timeline, err := h.svc.GetTimeline(c.Request.Context(), int64(userID), c.Param("connote"), limit)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTimeline) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, timeline)
+}
@@ -166,7 +166,7 @@ func (h *DataSourceHandler) RunPipeline(c *gin.Context) {
 	var etlItems []domain.BatchItem
 
 	if h.batchService != nil {
-		batch, err := h.batchService.CreateBatch(ctx, int64(userID), batchName)
+		batch, err := h.batchService.CreateBatch(ctx, int64(userID), batchName, domain.BatchMetadata{Source: domain.BatchSourceETL})
 		if err != nil {
 			log.Printf("WARN: could not create batch for ETL pipeline %d: %v", pipeline.ID, err)
 		} else {
@@ -200,6 +200,8 @@ func (h *DataSourceHandler) RunPipeline(c *gin.Context) {
 					session.FairlyCount++
 				case "inaccurate":
 					session.InaccurateCount++
+				case domain.AccuracyUnverifiable:
+					session.UnverifiableCount++
 				default:
 					session.ErrorCount++
 				}
@@ -220,6 +222,7 @@ func (h *DataSourceHandler) RunPipeline(c *gin.Context) {
 					GeocodeStatus: geoStatus,
 					AccuracyLevel: res.AccuracyLevel,
 					Error:         res.Error,
+					ReasonCode:    res.ReasonCode,
 				}
 				if res.GeoLat != 0 {
 					lat, lng := res.GeoLat, res.GeoLng
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -29,3 +30,19 @@ func getUserID(c *gin.Context) (int, bool) {
 		return 0, false
 	}
 }
+
+// parseIDParam reads a numeric :id path parameter. Writes 400 if it is invalid.
+func parseIDParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID tidak valid"})
+		return 0, false
+	}
+	return id, true
+}
+
+// isAdmin reports whether the authenticated user has the admin role.
+func isAdmin(c *gin.Context) bool {
+	role, _ := c.Get("userRole")
+	return role == "admin"
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"geoaccuracy-backend/internal/domain"
+	"geoaccuracy-backend/internal/service"
+)
+
+type SearchHandler struct {
+	svc domain.AddressSearchService
+}
+
+func NewSearchHandler(svc domain.AddressSearchService) *SearchHandler {
+	return &SearchHandler{svc: svc}
+}
+
+// SearchAddresses fuzzy-searches the addresses and recipients of the user's
+// batches and the geocode cache, with matches grouped by batch.
+// GET /api/search/addresses?q=Jl.+Kebon+Jeruk+12&limit=100
+func (h *SearchHandler) SearchAddresses(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	limit, _, err := parseLimitOffset(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.svc.SearchAddresses(c.Request.Context(), int64(userID), isAdmin(c), c.Query("q"), limit)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSearch) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
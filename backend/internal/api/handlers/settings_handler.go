@@ -61,6 +61,52 @@ func (h *SettingsHandler) UpdateSettings(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Pengaturan berhasil disimpan"})
 }
 
+// UpdateQualityPolicy saves the address quality policy for the current user.
+// PUT /api/settings/address-policy
+func (h *SettingsHandler) UpdateQualityPolicy(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateQualityPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Payload tidak valid: " + err.Error()})
+		return
+	}
+
+	if err := h.settingsSvc.UpdateQualityPolicy(userID, req); err != nil {
+		log.Printf("UpdateQualityPolicy error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menyimpan kebijakan kualitas alamat"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Kebijakan kualitas alamat berhasil disimpan"})
+}
+
+// UpdateRetentionPolicy saves the data retention policy for the current user.
+// PUT /api/settings/retention-policy
+func (h *SettingsHandler) UpdateRetentionPolicy(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateRetentionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "Payload tidak valid: " + err.Error()})
+		return
+	}
+
+	if err := h.settingsSvc.UpdateRetentionPolicy(userID, req); err != nil {
+		log.Printf("UpdateRetentionPolicy error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"message": "Gagal menyimpan kebijakan retensi data"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Kebijakan retensi data berhasil disimpan"})
+}
+
 // TestProviderKey validates an API key against the Provider API.
 // POST /api/settings/maps/test
 func (h *SettingsHandler) TestProviderKey(c *gin.Context) {
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"geoaccuracy-backend/internal/domain"
+	"geoaccuracy-backend/internal/service"
+)
+
+type TeamHandler struct {
+	svc domain.TeamService
+}
+
+func NewTeamHandler(svc domain.TeamService) *TeamHandler {
+	return &TeamHandler{svc: svc}
+}
+
+type createTeamRequest struct {
+	Name string `json:"name"`
+}
+
+// POST /api/teams
+func (h *TeamHandler) CreateTeam(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	var req createTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	team, err := h.svc.CreateTeam(c.Request.Context(), int64(userID), req.Name)
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, team)
+}
+
+// ListTeams lists the teams the user belongs to.
+// GET /api/teams
+func (h *TeamHandler) ListTeams(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+
+	teams, err := h.svc.ListTeams(c.Request.Context(), int64(userID))
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+	if teams == nil {
+		teams = []domain.Team{}
+	}
+	c.JSON(http.StatusOK, teams)
+}
+
+// GetTeam returns the team with its members.
+// GET /api/teams/:id
+func (h *TeamHandler) GetTeam(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	team, err := h.svc.GetTeam(c.Request.Context(), int64(userID), id)
+	if err != nil {
+		writeTeamError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, team)
+}
+
+// DELETE /api/teams/:id
+func (h *TeamHandler) DeleteTeam(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.DeleteTeam(c.Request.Context(), int64(userID), id); err != nil {
+		writeTeamError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Team deleted"})
+}
+
+type addTeamMemberRequest struct {
+	UserID int64 `json:"user_id" binding:"required"`
+}
+
+// POST /api/teams/:id/members
+func (h *TeamHandler) AddTeamMember(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req addTeamMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if err := h.svc.AddTeamMember(c.Request.Context(), int64(userID), id, req.UserID); err != nil {
+		writeTeamError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Member added"})
+}
+
+// RemoveTeamMember removes a member; editors may also remove themselves.
+// DELETE /api/teams/:id/members/:userId
+func (h *TeamHandler) RemoveTeamMember(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+	memberID, err := strconv.ParseInt(c.Param("userId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := h.svc.RemoveTeamMember(c.Request.Context(), int64(userID), id, memberID); err != nil {
+		writeTeamError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed"})
+}
+
+// LeaveTeam removes the caller from a team they are a member of; unlike
+// RemoveTeamMember it is open to every role.
+// POST /api/teams/:id/leave
+func (h *TeamHandler) LeaveTeam(c *gin.Context) {
+	userID, ok := getUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.svc.RemoveTeamMember(c.Request.Context(), int64(userID), id, int64(userID)); err != nil {
+		writeTeamError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Left the team"})
+}
+
+func writeTeamError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidTeam):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrTeamNotFound), errors.Is(err, domain.ErrShareTargetNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -23,6 +23,10 @@ func SetupRouter(
 	analyticsHandler *handlers.AnalyticsHandler,
 	erpHandler *handlers.ErpIntegrationHandler,
 	batchHandler *handlers.BatchHandler,
+	benchmarkHandler *handlers.BenchmarkHandler,
+	teamHandler *handlers.TeamHandler,
+	connoteHandler *handlers.ConnoteHandler,
+	searchHandler *handlers.SearchHandler,
 	wsHandler *handlers.WSHandler,
 	webhookRepo domain.WebhookRepository,
 ) *gin.Engine {
@@ -64,6 +68,7 @@ func SetupRouter(
 			protected.GET("/analytics", historyHandler.GetAnalytics)
 			protected.GET("/advanced-analytics/couriers", analyticsHandler.GetCourierLeaderboard)
 			protected.GET("/advanced-analytics/sla", analyticsHandler.GetSLATrends)
+			protected.GET("/advanced-analytics/failure-reasons", analyticsHandler.GetFailureReasons)
 
 			protected.GET("/datasources", dsHandler.List)
 			protected.GET("/datasources/:id/schema", dsHandler.GetSchema)
@@ -73,6 +78,26 @@ func SetupRouter(
 			protected.GET("/areas/check", areaHandler.CheckPointInArea)
 			protected.GET("/areas/:id", areaHandler.GetArea)
 
+			// Batches the user owns or that are shared with them; the batch
+			// service checks the per-batch permission.
+			protected.GET("/batches", batchHandler.ListBatches)
+			protected.GET("/batches/:id/results", batchHandler.GetBatchResults)
+			protected.GET("/batches/:id/export", batchHandler.ExportBatch)
+			protected.GET("/batches/:id/stats", batchHandler.GetBatchStats)
+			protected.GET("/batches/:id/diff/:other", batchHandler.DiffBatches)
+			protected.GET("/batches/:id/reconciliation", batchHandler.ReconcileBatch)
+			protected.GET("/batches/:id/upload-issues", batchHandler.ListUploadIssues)
+			protected.GET("/batches/:id/job", batchHandler.GetBatchJob)
+			protected.GET("/batches/:id/shares", batchHandler.ListBatchShares)
+			protected.GET("/batches/:id/review-queue", batchHandler.GetReviewQueue)
+			protected.GET("/review-queue", batchHandler.ListMyReviews)
+			protected.GET("/connotes/:connote/timeline", connoteHandler.GetTimeline)
+			protected.GET("/search/addresses", searchHandler.SearchAddresses)
+
+			protected.GET("/teams", teamHandler.ListTeams)
+			protected.GET("/teams/:id", teamHandler.GetTeam)
+			protected.POST("/teams/:id/leave", teamHandler.LeaveTeam)
+
 			// ── Editor & Admin Access (Operational Mutations) ──
 			editorGroup := protected.Group("/")
 			editorGroup.Use(middleware.RequireRole("admin", "editor"))
@@ -100,13 +125,40 @@ func SetupRouter(
 
 				// Enterprise Batch Processing
 				editorGroup.POST("/batches", batchHandler.CreateBatch)
-				editorGroup.GET("/batches", batchHandler.ListBatches)
-				editorGroup.GET("/batches/:id/results", batchHandler.GetBatchResults)
+				editorGroup.PUT("/batches/:id/metadata", batchHandler.UpdateBatchMetadata)
+				editorGroup.DELETE("/batches/:id", batchHandler.DeleteBatch)
+				editorGroup.POST("/batches/:id/archive", batchHandler.ArchiveBatch)
+				editorGroup.POST("/batches/:id/unarchive", batchHandler.UnarchiveBatch)
+				editorGroup.PUT("/batches/:id/shares", batchHandler.ShareBatch)
+				editorGroup.DELETE("/batches/:id/shares/:shareId", batchHandler.UnshareBatch)
+				editorGroup.POST("/batches/:id/transfer", batchHandler.TransferBatch)
+				editorGroup.POST("/batches/:id/reconciliation/accept", batchHandler.AcceptConnoteMatches)
+				editorGroup.POST("/batches/:id/review", batchHandler.ReviewBatchItems)
 				editorGroup.POST("/batches/:id/system-data", batchHandler.UploadSystemData)
 				editorGroup.POST("/batches/:id/field-data", batchHandler.UploadFieldData)
+				editorGroup.POST("/batches/:id/system-data/file", batchHandler.UploadSystemFile)
+				editorGroup.POST("/batches/:id/field-data/file", batchHandler.UploadFieldFile)
 				editorGroup.POST("/batches/:id/process", batchHandler.ProcessBatch)
+				editorGroup.POST("/batches/:id/reprocess", batchHandler.ReprocessBatch)
+				editorGroup.POST("/batches/:id/pause", batchHandler.PauseBatch)
+				editorGroup.POST("/batches/:id/resume", batchHandler.ResumeBatch)
+				editorGroup.POST("/batches/:id/cancel", batchHandler.CancelBatch)
 
 				editorGroup.GET("/ws/batches/:id", wsHandler.HandleBatchWS)
+
+				// Teams for batch sharing
+				editorGroup.POST("/teams", teamHandler.CreateTeam)
+				editorGroup.DELETE("/teams/:id", teamHandler.DeleteTeam)
+				editorGroup.POST("/teams/:id/members", teamHandler.AddTeamMember)
+				editorGroup.DELETE("/teams/:id/members/:userId", teamHandler.RemoveTeamMember)
+
+				// Provider Benchmarking (Ground-Truth Datasets)
+				editorGroup.POST("/ground-truth", benchmarkHandler.CreateDataset)
+				editorGroup.GET("/ground-truth", benchmarkHandler.ListDatasets)
+				editorGroup.DELETE("/ground-truth/:id", benchmarkHandler.DeleteDataset)
+				editorGroup.POST("/ground-truth/:id/benchmarks", benchmarkHandler.StartBenchmark)
+				editorGroup.GET("/ground-truth/:id/benchmarks", benchmarkHandler.ListReports)
+				editorGroup.GET("/benchmarks/:id", benchmarkHandler.GetReport)
 			}
 
 			// ── Admin Only Access (Global Settings) ──
@@ -117,6 +169,8 @@ func SetupRouter(
 				adminGroup.GET("/settings", settingsHandler.GetSettings)
 				adminGroup.PUT("/settings/keys", settingsHandler.UpdateSettings)
 				adminGroup.POST("/settings/keys/test", settingsHandler.TestProviderKey)
+				adminGroup.PUT("/settings/address-policy", settingsHandler.UpdateQualityPolicy)
+				adminGroup.PUT("/settings/retention-policy", settingsHandler.UpdateRetentionPolicy)
 
 				// External Ingestion API Keys (Webhooks)
 				adminGroup.GET("/settings/api-keys", webhookHandler.ListAPIKeys)
@@ -0,0 +1,3 @@
+ALTER TABLE geocode_cache DROP COLUMN IF EXISTS precision_level;
+DROP INDEX IF EXISTS idx_batch_items_reason_code;
+ALTER TABLE batch_items DROP COLUMN IF EXISTS reason_code;
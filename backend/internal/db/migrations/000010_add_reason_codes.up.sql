@@ -0,0 +1,6 @@
+-- Structured reason codes on batch items and geocode precision on the cache.
+ALTER TABLE batch_items
+    ADD COLUMN IF NOT EXISTS reason_code VARCHAR(64) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_batch_items_reason_code ON batch_items(reason_code);
+ALTER TABLE geocode_cache
+    ADD COLUMN IF NOT EXISTS precision_level TEXT NOT NULL DEFAULT 'unknown';
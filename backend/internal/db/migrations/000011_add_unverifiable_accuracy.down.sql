@@ -0,0 +1,12 @@
+ALTER TABLE comparison_sessions DROP COLUMN IF EXISTS unverifiable_count;
+UPDATE courier_performance SET accuracy_status = 'error' WHERE accuracy_status = 'unverifiable';
+ALTER TABLE courier_performance DROP CONSTRAINT IF EXISTS courier_performance_accuracy_status_check;
+ALTER TABLE courier_performance
+    ADD CONSTRAINT courier_performance_accuracy_status_check CHECK (
+        accuracy_status IN (
+            'accurate',
+            'fairly_accurate',
+            'inaccurate',
+            'error'
+        )
+    );
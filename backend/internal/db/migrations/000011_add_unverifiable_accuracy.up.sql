@@ -0,0 +1,14 @@
+-- Low-precision geocodes are labelled 'unverifiable' and counted separately.
+ALTER TABLE courier_performance DROP CONSTRAINT IF EXISTS courier_performance_accuracy_status_check;
+ALTER TABLE courier_performance
+    ADD CONSTRAINT courier_performance_accuracy_status_check CHECK (
+        accuracy_status IN (
+            'accurate',
+            'fairly_accurate',
+            'inaccurate',
+            'unverifiable',
+            'error'
+        )
+    );
+ALTER TABLE comparison_sessions
+    ADD COLUMN IF NOT EXISTS unverifiable_count INT NOT NULL DEFAULT 0;
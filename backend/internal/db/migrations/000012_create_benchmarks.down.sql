@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS benchmark_reports;
+DROP TABLE IF EXISTS ground_truth_entries;
+DROP TABLE IF EXISTS ground_truth_datasets;
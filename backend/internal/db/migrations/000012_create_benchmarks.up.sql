@@ -0,0 +1,31 @@
+-- Ground-truth datasets: addresses with surveyed coordinates used to benchmark providers.
+CREATE TABLE IF NOT EXISTS ground_truth_datasets (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_ground_truth_datasets_user_id ON ground_truth_datasets(user_id);
+
+CREATE TABLE IF NOT EXISTS ground_truth_entries (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    dataset_id BIGINT NOT NULL REFERENCES ground_truth_datasets(id) ON DELETE CASCADE,
+    address TEXT NOT NULL,
+    province VARCHAR(100) NOT NULL DEFAULT '',
+    lat DOUBLE PRECISION NOT NULL,
+    lng DOUBLE PRECISION NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_ground_truth_entries_dataset_id ON ground_truth_entries(dataset_id);
+
+CREATE TABLE IF NOT EXISTS benchmark_reports (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    dataset_id BIGINT NOT NULL REFERENCES ground_truth_datasets(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    providers TEXT[] NOT NULL,
+    result JSONB,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    completed_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS idx_benchmark_reports_dataset_id ON benchmark_reports(dataset_id);
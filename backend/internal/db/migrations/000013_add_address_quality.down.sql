@@ -0,0 +1,6 @@
+ALTER TABLE user_settings
+    DROP COLUMN IF EXISTS min_premium_quality_score,
+    DROP COLUMN IF EXISTS skip_premium_low_quality;
+ALTER TABLE batch_items
+    DROP COLUMN IF EXISTS address_quality_flags,
+    DROP COLUMN IF EXISTS address_quality_score;
@@ -0,0 +1,9 @@
+-- Address quality score computed on upload; flags explain what is missing.
+ALTER TABLE batch_items
+    ADD COLUMN IF NOT EXISTS address_quality_score INT,
+    ADD COLUMN IF NOT EXISTS address_quality_flags TEXT[] NOT NULL DEFAULT '{}';
+
+-- Per-user policy to keep low-quality addresses away from premium geocoders.
+ALTER TABLE user_settings
+    ADD COLUMN IF NOT EXISTS skip_premium_low_quality BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS min_premium_quality_score INT NOT NULL DEFAULT 40;
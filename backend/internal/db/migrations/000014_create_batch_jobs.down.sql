@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_batch_items_batch_status;
+DROP TABLE IF EXISTS batch_jobs;
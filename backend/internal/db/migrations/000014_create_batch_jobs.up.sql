@@ -0,0 +1,28 @@
+-- Durable queue for batch processing. Workers claim rows with
+-- FOR UPDATE SKIP LOCKED and hold a lease that is renewed at every checkpoint;
+-- a job whose lease expired (crashed server) is picked up again.
+CREATE TABLE IF NOT EXISTS batch_jobs (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    batch_id UUID NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    attempts INT NOT NULL DEFAULT 0,
+    processed INT NOT NULL DEFAULT 0,
+    total INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    locked_by VARCHAR(255),
+    locked_until TIMESTAMPTZ,
+    run_after TIMESTAMPTZ NOT NULL DEFAULT now(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    completed_at TIMESTAMPTZ
+);
+
+-- At most one queued or running job per batch.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_batch_jobs_active_batch
+    ON batch_jobs(batch_id) WHERE status IN ('queued', 'running');
+CREATE INDEX IF NOT EXISTS idx_batch_jobs_claim ON batch_jobs(status, run_after);
+CREATE INDEX IF NOT EXISTS idx_batch_jobs_batch_id ON batch_jobs(batch_id, created_at DESC);
+
+-- Items are processed in chunks of pending rows; this keeps that lookup cheap.
+CREATE INDEX IF NOT EXISTS idx_batch_items_batch_status ON batch_items(batch_id, geocode_status);
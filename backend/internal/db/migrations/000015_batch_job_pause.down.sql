@@ -0,0 +1,4 @@
+UPDATE batch_jobs SET status = 'cancelled' WHERE status = 'paused';
+DROP INDEX IF EXISTS idx_batch_jobs_active_batch;
+CREATE UNIQUE INDEX idx_batch_jobs_active_batch
+    ON batch_jobs(batch_id) WHERE status IN ('queued', 'running');
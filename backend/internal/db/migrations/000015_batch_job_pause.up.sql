@@ -0,0 +1,5 @@
+-- A paused job still owns its batch: it must be resumed or cancelled before
+-- the batch can be queued again.
+DROP INDEX IF EXISTS idx_batch_jobs_active_batch;
+CREATE UNIQUE INDEX idx_batch_jobs_active_batch
+    ON batch_jobs(batch_id) WHERE status IN ('queued', 'running', 'paused');
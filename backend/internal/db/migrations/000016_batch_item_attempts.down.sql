@@ -0,0 +1,4 @@
+ALTER TABLE batch_jobs DROP COLUMN IF EXISTS bypass_cache;
+ALTER TABLE batch_items
+    DROP COLUMN IF EXISTS attempt_count,
+    DROP COLUMN IF EXISTS provider;
@@ -0,0 +1,8 @@
+-- Provider that produced the geocode and how often the item has been processed,
+-- so failed or provider-specific subsets can be reprocessed.
+ALTER TABLE batch_items
+    ADD COLUMN IF NOT EXISTS provider VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS attempt_count INT NOT NULL DEFAULT 0;
+
+ALTER TABLE batch_jobs
+    ADD COLUMN IF NOT EXISTS bypass_cache BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_batch_items_page_distance;
+DROP INDEX IF EXISTS idx_batch_items_page_connote;
+DROP INDEX IF EXISTS idx_batch_items_page_updated;
+DROP INDEX IF EXISTS idx_batch_items_page_created;
@@ -0,0 +1,6 @@
+-- Keyset pagination of batch results: each sort order is (batch_id, key, id).
+CREATE INDEX IF NOT EXISTS idx_batch_items_page_created ON batch_items(batch_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_batch_items_page_updated ON batch_items(batch_id, updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_batch_items_page_connote ON batch_items(batch_id, connote, id);
+CREATE INDEX IF NOT EXISTS idx_batch_items_page_distance
+    ON batch_items(batch_id, (COALESCE(distance_km, 'Infinity'::float8)), id);
@@ -0,0 +1 @@
+ALTER TABLE batch_items DROP COLUMN IF EXISTS from_cache;
@@ -0,0 +1,3 @@
+-- Whether the item's geocode came from a cache (persistent or in-batch);
+-- NULL until the item has been geocoded. Feeds the batch cache hit ratio.
+ALTER TABLE batch_items ADD COLUMN IF NOT EXISTS from_cache BOOLEAN;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_batch_items_batch_connote;
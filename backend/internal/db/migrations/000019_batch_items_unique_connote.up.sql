@@ -0,0 +1,15 @@
+-- courier_id has been written by the application since field uploads carried
+-- reported_by, but no migration created it.
+ALTER TABLE batch_items ADD COLUMN IF NOT EXISTS courier_id VARCHAR(255);
+
+-- UpsertBatchItems merges with ON CONFLICT (batch_id, connote), which needs a
+-- unique index. Earlier per-row upserts updated every duplicate alike, so the
+-- most recently updated copy is kept.
+DELETE FROM batch_items a
+USING batch_items b
+WHERE a.batch_id = b.batch_id
+  AND a.connote = b.connote
+  AND (COALESCE(a.updated_at, a.created_at, '-infinity'), a.id)
+    < (COALESCE(b.updated_at, b.created_at, '-infinity'), b.id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_batch_items_batch_connote ON batch_items(batch_id, connote);
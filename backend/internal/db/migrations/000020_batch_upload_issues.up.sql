@@ -0,0 +1,15 @@
+-- Row-level problems found by lenient uploads. Each upload replaces the
+-- issues of the previous upload from the same source.
+CREATE TABLE IF NOT EXISTS batch_upload_issues (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    batch_id UUID NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    source VARCHAR(10) NOT NULL CHECK (source IN ('system', 'field')),
+    row_number INT NOT NULL,
+    connote VARCHAR(255) NOT NULL DEFAULT '',
+    code VARCHAR(50) NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    skipped BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_batch_upload_issues_batch ON batch_upload_issues(batch_id, source, row_number);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_cp_batch_id;
+ALTER TABLE user_settings
+    DROP COLUMN IF EXISTS batch_retention_days,
+    DROP COLUMN IF EXISTS item_retention_days;
+ALTER TABLE batches
+    DROP COLUMN IF EXISTS summary,
+    DROP COLUMN IF EXISTS items_purged_at,
+    DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,14 @@
+-- Archived batches are read-only and hidden from the default batch list.
+-- Once retention purges a batch's items, summary keeps its statistics.
+ALTER TABLE batches
+    ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS items_purged_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS summary JSONB;
+
+-- Per-user retention; 0 keeps data forever.
+ALTER TABLE user_settings
+    ADD COLUMN IF NOT EXISTS item_retention_days INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS batch_retention_days INT NOT NULL DEFAULT 0;
+
+-- Batch deletion removes the courier_performance rows of the batch.
+CREATE INDEX IF NOT EXISTS idx_cp_batch_id ON courier_performance(batch_id);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_batches_tags;
+DROP INDEX IF EXISTS idx_batches_user_region;
+DROP INDEX IF EXISTS idx_batches_user_client;
+ALTER TABLE batches
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS source,
+    DROP COLUMN IF EXISTS delivery_to,
+    DROP COLUMN IF EXISTS delivery_from,
+    DROP COLUMN IF EXISTS region,
+    DROP COLUMN IF EXISTS client;
@@ -0,0 +1,13 @@
+-- Searchable batch metadata. Existing batches get no source rather than a guess.
+ALTER TABLE batches
+    ADD COLUMN IF NOT EXISTS client VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS region VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS delivery_from DATE,
+    ADD COLUMN IF NOT EXISTS delivery_to DATE,
+    ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT ''
+        CHECK (source IN ('', 'csv', 'etl', 'webhook', 'erp')),
+    ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_batches_user_client ON batches(user_id, lower(client));
+CREATE INDEX IF NOT EXISTS idx_batches_user_region ON batches(user_id, lower(region));
+CREATE INDEX IF NOT EXISTS idx_batches_tags ON batches USING GIN (tags);
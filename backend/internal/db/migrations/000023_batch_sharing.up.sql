@@ -0,0 +1,34 @@
+-- Teams let a batch be shared with a group of users at once. The owner is
+-- also stored as a member.
+CREATE TABLE IF NOT EXISTS teams (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    owner_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS team_members (
+    team_id BIGINT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    added_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_members_user ON team_members(user_id);
+
+-- A share grants one user or one team access to a batch owned by someone else.
+CREATE TABLE IF NOT EXISTS batch_shares (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    batch_id UUID NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    team_id BIGINT REFERENCES teams(id) ON DELETE CASCADE,
+    permission VARCHAR(10) NOT NULL CHECK (permission IN ('viewer', 'editor')),
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((user_id IS NULL) <> (team_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_batch_shares_user ON batch_shares(batch_id, user_id) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_batch_shares_team ON batch_shares(batch_id, team_id) WHERE team_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_batch_shares_user_lookup ON batch_shares(user_id) WHERE user_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_batch_shares_team_lookup ON batch_shares(team_id) WHERE team_id IS NOT NULL;
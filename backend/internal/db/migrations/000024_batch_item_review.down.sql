@@ -0,0 +1,10 @@
+ALTER TABLE courier_performance DROP COLUMN IF EXISTS accuracy_override;
+DROP INDEX IF EXISTS idx_batch_items_reviewer;
+DROP INDEX IF EXISTS idx_batch_items_review;
+ALTER TABLE batch_items
+    DROP COLUMN IF EXISTS reviewed_at,
+    DROP COLUMN IF EXISTS corrected_lng,
+    DROP COLUMN IF EXISTS corrected_lat,
+    DROP COLUMN IF EXISTS review_comment,
+    DROP COLUMN IF EXISTS reviewer_id,
+    DROP COLUMN IF EXISTS review_status;
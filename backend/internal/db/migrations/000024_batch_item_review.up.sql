@@ -0,0 +1,18 @@
+-- Manual review of flagged items. corrected_lat/lng hold the coordinate a
+-- reviewer set when the master address was wrong.
+ALTER TABLE batch_items
+    ADD COLUMN IF NOT EXISTS review_status VARCHAR(20) NOT NULL DEFAULT 'unreviewed'
+        CHECK (review_status IN ('unreviewed', 'in_review', 'accepted', 'address_corrected', 'courier_disputed')),
+    ADD COLUMN IF NOT EXISTS reviewer_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS review_comment TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS corrected_lat DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS corrected_lng DOUBLE PRECISION,
+    ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_batch_items_review ON batch_items(batch_id, review_status);
+CREATE INDEX IF NOT EXISTS idx_batch_items_reviewer ON batch_items(reviewer_id) WHERE review_status = 'in_review';
+
+-- A resolved review overrides the accuracy of the item's courier events.
+ALTER TABLE courier_performance
+    ADD COLUMN IF NOT EXISTS accuracy_override VARCHAR(50)
+        CHECK (accuracy_override IN ('accurate', 'inaccurate'));
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_cp_order_id;
@@ -0,0 +1,3 @@
+-- The connote timeline looks courier events up by order_id; batch_items
+-- already has idx_batch_items_connote.
+CREATE INDEX IF NOT EXISTS idx_cp_order_id ON courier_performance(order_id);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_batch_items_system_address;
+DROP INDEX IF EXISTS idx_geocode_cache_address_fts;
+DROP INDEX IF EXISTS idx_geocode_cache_address_trgm;
+DROP INDEX IF EXISTS idx_batch_items_address_fts;
+DROP INDEX IF EXISTS idx_batch_items_recipient_trgm;
+DROP INDEX IF EXISTS idx_batch_items_address_trgm;
+-- pg_trgm is left installed; other objects may depend on it.
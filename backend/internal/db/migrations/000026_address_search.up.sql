@@ -0,0 +1,18 @@
+-- Fuzzy (trigram) and full-text search over addresses and recipients. The
+-- expressions must match the ones in repository/address_search.go.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_batch_items_address_trgm
+    ON batch_items USING GIN (system_address gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_batch_items_recipient_trgm
+    ON batch_items USING GIN (recipient_name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_batch_items_address_fts
+    ON batch_items USING GIN (to_tsvector('simple', COALESCE(system_address, '') || ' ' || COALESCE(recipient_name, '')));
+
+CREATE INDEX IF NOT EXISTS idx_geocode_cache_address_trgm
+    ON geocode_cache USING GIN (original_address gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_geocode_cache_address_fts
+    ON geocode_cache USING GIN (to_tsvector('simple', original_address));
+-- Non-admin cache searches look the address up among the user's items.
+-- Hash, not btree: addresses have no length limit.
+CREATE INDEX IF NOT EXISTS idx_batch_items_system_address ON batch_items USING HASH (system_address);
@@ -0,0 +1,5 @@
+ALTER TABLE batch_items
+    DROP COLUMN IF EXISTS processed_at,
+    DROP COLUMN IF EXISTS geocoded_province,
+    DROP COLUMN IF EXISTS geocoded_city,
+    DROP COLUMN IF EXISTS cache_status;
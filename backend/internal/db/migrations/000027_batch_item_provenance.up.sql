@@ -0,0 +1,12 @@
+-- Where an item's geocode came from. cache_status refines from_cache: an
+-- exact hit had the same address text, a fuzzy one only matched after
+-- normalization. Items geocoded before this have from_cache alone, so only
+-- the misses can be backfilled.
+ALTER TABLE batch_items
+    ADD COLUMN IF NOT EXISTS cache_status VARCHAR(10) NOT NULL DEFAULT ''
+        CHECK (cache_status IN ('', 'exact', 'fuzzy', 'miss')),
+    ADD COLUMN IF NOT EXISTS geocoded_city TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS geocoded_province TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS processed_at TIMESTAMPTZ;
+
+UPDATE batch_items SET cache_status = 'miss' WHERE from_cache = FALSE;
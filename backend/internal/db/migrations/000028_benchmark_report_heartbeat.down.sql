@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_benchmark_reports_running;
+ALTER TABLE benchmark_reports DROP COLUMN IF EXISTS heartbeat_at;
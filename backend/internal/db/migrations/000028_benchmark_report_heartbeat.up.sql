@@ -0,0 +1,6 @@
+-- A running report's runner refreshes heartbeat_at. Reports whose heartbeat
+-- (or, for rows from before this column, creation) is stale lost their
+-- runner to a restart and are failed by BenchmarkService.
+ALTER TABLE benchmark_reports ADD COLUMN IF NOT EXISTS heartbeat_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_benchmark_reports_running ON benchmark_reports(heartbeat_at) WHERE status = 'running';
@@ -0,0 +1,13 @@
+-- Batch processing records one courier event per item; reprocessing used to
+-- add another. Keep the newest row per (batch, connote) and let the upsert
+-- replace it from now on. Only batch rows are unique: webhook and ERP
+-- batch_ids are free text and their repeated events are the history.
+DELETE FROM courier_performance cp
+USING courier_performance newer
+WHERE cp.batch_id = newer.batch_id
+  AND cp.order_id = newer.order_id
+  AND cp.id < newer.id
+  AND cp.batch_id ~ '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$';
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cp_batch_order ON courier_performance(batch_id, order_id)
+    WHERE batch_id ~ '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$';
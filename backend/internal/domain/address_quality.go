@@ -0,0 +1,54 @@
+package domain
+
+// AddressQualityFlag names one problem found by the address quality scorer.
+type AddressQualityFlag string
+
+const (
+	AddressFlagMissingStreet      AddressQualityFlag = "MISSING_STREET"
+	AddressFlagMissingHouseNumber AddressQualityFlag = "MISSING_HOUSE_NUMBER"
+	AddressFlagMissingAdminArea   AddressQualityFlag = "MISSING_ADMIN_AREA"
+	AddressFlagMissingPostalCode  AddressQualityFlag = "MISSING_POSTAL_CODE"
+	AddressFlagTooShort           AddressQualityFlag = "TOO_SHORT"
+	AddressFlagLandmarkOnly       AddressQualityFlag = "LANDMARK_ONLY"
+)
+
+// DefaultMinPremiumQualityScore is the score below which an address is
+// reported as low quality and, when the policy is on, kept away from premium
+// geocoders.
+const DefaultMinPremiumQualityScore = 40
+
+// AddressComponents are the parts recognised in a free-text Indonesian address.
+type AddressComponents struct {
+	Street      string `json:"street,omitempty"`
+	HouseNumber string `json:"house_number,omitempty"`
+	RTRW        string `json:"rt_rw,omitempty"`
+	Village     string `json:"village,omitempty"`  // kelurahan / desa
+	District    string `json:"district,omitempty"` // kecamatan
+	City        string `json:"city,omitempty"`     // kota / kabupaten
+	PostalCode  string `json:"postal_code,omitempty"`
+}
+
+// AddressQuality is the geocodability score (0-100) of an address.
+type AddressQuality struct {
+	Score      int                  `json:"score"`
+	Flags      []AddressQualityFlag `json:"flags"`
+	Components AddressComponents    `json:"components"`
+}
+
+// LowQualityAddress is one entry of an upload report.
+type LowQualityAddress struct {
+	Connote       string               `json:"connote"`
+	SystemAddress string               `json:"system_address"`
+	Score         int                  `json:"score"`
+	Flags         []AddressQualityFlag `json:"flags"`
+}
+
+// UploadReport summarises an upload for the shipper so incomplete addresses
+// can be fixed at the source.
+type UploadReport struct {
+	Accepted        int                 `json:"accepted"`
+	QualityMinScore int                 `json:"quality_min_score"`
+	LowQualityCount int                 `json:"low_quality_count"`
+	LowQuality      []LowQualityAddress `json:"low_quality"`
+	Validation      *UploadValidation   `json:"validation,omitempty"`
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HighlightSegment is a piece of a matched text; the pieces concatenate to
+// the whole text and Match marks the words that matched the query.
+type HighlightSegment struct {
+	Text  string `json:"text"`
+	Match bool   `json:"match,omitempty"`
+}
+
+// AddressMatch is a batch item whose address or recipient matched a search.
+// Score is the pg_trgm word similarity of the better field, from 0 to 1.
+type AddressMatch struct {
+	ItemID             uuid.UUID          `json:"item_id"`
+	BatchID            uuid.UUID          `json:"-"`
+	BatchName          string             `json:"-"`
+	BatchCreatedAt     time.Time          `json:"-"`
+	Connote            string             `json:"connote"`
+	RecipientName      string             `json:"recipient_name"`
+	SystemAddress      string             `json:"system_address"`
+	AccuracyLevel      string             `json:"accuracy_level"`
+	Score              float64            `json:"score"`
+	RecipientHighlight []HighlightSegment `json:"recipient_highlight"`
+	AddressHighlight   []HighlightSegment `json:"address_highlight"`
+}
+
+// AddressSearchBatch groups the matches of one batch, best match first.
+type AddressSearchBatch struct {
+	BatchID   uuid.UUID      `json:"batch_id"`
+	BatchName string         `json:"batch_name"`
+	CreatedAt time.Time      `json:"created_at"`
+	Matches   []AddressMatch `json:"matches"`
+}
+
+// CachedAddressMatch is a geocode cache entry whose address matched.
+type CachedAddressMatch struct {
+	OriginalAddress string             `json:"original_address"`
+	City            string             `json:"city"`
+	Province        string             `json:"province"`
+	Lat             float64            `json:"lat"`
+	Lng             float64            `json:"lng"`
+	Provider        string             `json:"provider"`
+	CreatedAt       time.Time          `json:"created_at"`
+	Score           float64            `json:"score"`
+	Highlight       []HighlightSegment `json:"highlight"`
+}
+
+// AddressSearchResult is the response of GET /api/search/addresses. Batches
+// are ordered by their best match.
+type AddressSearchResult struct {
+	Query     string               `json:"query"`
+	Batches   []AddressSearchBatch `json:"batches"`
+	Cache     []CachedAddressMatch `json:"cache"`
+	Truncated bool                 `json:"truncated"` // more items matched than the limit
+}
+
+type AddressSearchRepository interface {
+	// SearchBatchItems returns up to limit items of batches userID can see
+	// whose address or recipient matches query, best first.
+	SearchBatchItems(ctx context.Context, userID int64, query string, limit int) ([]AddressMatch, error)
+	// SearchGeocodeCache returns up to limit matching cache entries. The cache
+	// is shared by all users, so unless allUsers is set only entries for an
+	// address of a batch userID can see are returned.
+	SearchGeocodeCache(ctx context.Context, userID int64, allUsers bool, query string, limit int) ([]CachedAddressMatch, error)
+}
+
+type AddressSearchService interface {
+	// SearchAddresses searches batch items and the geocode cache; admins
+	// see every cache entry.
+	SearchAddresses(ctx context.Context, userID int64, isAdmin bool, query string, limit int) (*AddressSearchResult, error)
+}
@@ -17,21 +17,26 @@ type CourierPerformance struct {
 	ActualLat              *float64  `db:"actual_lat" json:"actual_lat"`
 	ActualLng              *float64  `db:"actual_lng" json:"actual_lng"`
 	DistanceVarianceMeters *float64  `db:"distance_variance_meters" json:"distance_variance_meters"`
-	AccuracyStatus         string    `db:"accuracy_status" json:"accuracy_status"` // accurate, fairly_accurate, inaccurate, error
+	AccuracyStatus         string    `db:"accuracy_status" json:"accuracy_status"` // accurate, fairly_accurate, inaccurate, unverifiable, error
 	SLAStatus              string    `db:"sla_status" json:"sla_status"`           // on_time, late, unknown
 	EventTimestamp         time.Time `db:"event_timestamp" json:"event_timestamp"`
 	CreatedAt              time.Time `db:"created_at" json:"created_at"`
+
+	// AccuracyOverride replaces AccuracyStatus in the analytics once a
+	// reviewer resolved the item: accurate, inaccurate or empty.
+	AccuracyOverride string `db:"accuracy_override" json:"accuracy_override,omitempty"`
 }
 
 // CourierAccuracyAgg represents grouped accuracy metrics per courier.
 type CourierAccuracyAgg struct {
-	CourierID       string  `db:"courier_id" json:"courier_id"`
-	TotalDeliveries int     `db:"total_deliveries" json:"total_deliveries"`
-	AccurateCount   int     `db:"accurate_count" json:"accurate_count"`
-	FairlyCount     int     `db:"fairly_count" json:"fairly_count"`
-	InaccurateCount int     `db:"inaccurate_count" json:"inaccurate_count"`
-	ErrorCount      int     `db:"error_count" json:"error_count"`
-	AccuracyRate    float64 `db:"accuracy_rate" json:"accuracy_rate"` // Percentage of accurate / total
+	CourierID         string  `db:"courier_id" json:"courier_id"`
+	TotalDeliveries   int     `db:"total_deliveries" json:"total_deliveries"`
+	AccurateCount     int     `db:"accurate_count" json:"accurate_count"`
+	FairlyCount       int     `db:"fairly_count" json:"fairly_count"`
+	InaccurateCount   int     `db:"inaccurate_count" json:"inaccurate_count"`
+	ErrorCount        int     `db:"error_count" json:"error_count"`
+	UnverifiableCount int     `db:"unverifiable_count" json:"unverifiable_count"`
+	AccuracyRate      float64 `db:"accuracy_rate" json:"accuracy_rate"` // Percentage of accurate / verifiable deliveries
 }
 
 // SLATrendAgg represents on-time vs late metrics grouped by time interval (e.g., daily).
@@ -43,6 +48,13 @@ type SLATrendAgg struct {
 	OnTimeRate  float64 `db:"on_time_rate" json:"on_time_rate"`
 }
 
+// FailureReasonAgg counts batch items per failure reason code.
+type FailureReasonAgg struct {
+	ReasonCode  ReasonCode        `db:"reason_code" json:"reason_code"`
+	Count       int               `db:"count" json:"count"`
+	Explanation ReasonExplanation `db:"-" json:"explanation"`
+}
+
 // AdvancedAnalyticsResponse wraps the dashboard data.
 type AdvancedAnalyticsResponse struct {
 	TotalEvents        int                  `json:"total_events"`
@@ -57,4 +69,5 @@ type AnalyticsRepository interface {
 	SaveCourierPerformance(ctx context.Context, cp *CourierPerformance) error
 	GetCourierLeaderboard(ctx context.Context, userID int64, limit int) ([]CourierAccuracyAgg, error)
 	GetSLATrends(ctx context.Context, userID int64, days int) ([]SLATrendAgg, error)
+	GetFailureReasons(ctx context.Context, userID int64, days int) ([]FailureReasonAgg, error)
 }
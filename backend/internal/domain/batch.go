@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,6 +16,24 @@ const (
 	BatchStatusProcessing BatchStatus = "processing"
 	BatchStatusCompleted  BatchStatus = "completed"
 	BatchStatusFailed     BatchStatus = "failed"
+	BatchStatusPaused     BatchStatus = "paused"
+	BatchStatusCancelled  BatchStatus = "cancelled"
+)
+
+// Geocode statuses of a BatchItem.
+const (
+	ItemStatusPending   = "pending"
+	ItemStatusCompleted = "completed"
+	ItemStatusFailed    = "failed"
+	ItemStatusSkipped   = "skipped"
+)
+
+// Cache statuses of a geocoded BatchItem. An exact hit was cached under the
+// same address text; a fuzzy one only matched after normalizeAddress.
+const (
+	CacheStatusExact = "exact"
+	CacheStatusFuzzy = "fuzzy"
+	CacheStatusMiss  = "miss"
 )
 
 // Batch represents a group of items uploaded by a user for processing
@@ -25,6 +44,15 @@ type Batch struct {
 	Status    BatchStatus `json:"status" db:"status"`
 	CreatedAt time.Time   `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time   `json:"updated_at" db:"updated_at"`
+
+	ArchivedAt    *time.Time `json:"archived_at,omitempty" db:"archived_at"`
+	ItemsPurgedAt *time.Time `json:"items_purged_at,omitempty" db:"items_purged_at"` // only the summary is left
+
+	BatchMetadata
+
+	// Access is the requesting user's permission on the batch; it is set
+	// when the batch is listed or checked for a user.
+	Access BatchPermission `json:"access,omitempty" db:"-"`
 }
 
 // BatchItem represents an individual record within a batch
@@ -45,19 +73,138 @@ type BatchItem struct {
 	GeocodeStatus string    `json:"geocode_status" db:"geocode_status"`
 	CreatedAt     time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+
+	ReasonCode  ReasonCode         `json:"reason_code" db:"reason_code"`
+	Explanation *ReasonExplanation `json:"explanation,omitempty" db:"-"` // filled on read from ReasonCode
+
+	// Set on system-data upload; nil until scored.
+	AddressQualityScore *int     `json:"address_quality_score" db:"address_quality_score"`
+	AddressQualityFlags []string `json:"address_quality_flags" db:"address_quality_flags"`
+
+	Provider     string `json:"provider" db:"provider"`           // geocoder that produced SystemLat/Lng
+	AttemptCount int    `json:"attempt_count" db:"attempt_count"` // times the item has been processed
+	FromCache    *bool  `json:"from_cache" db:"from_cache"`       // nil until geocoded
+
+	// Provenance of the last processing run; empty or nil before it.
+	CacheStatus      string     `json:"cache_status" db:"cache_status"` // CacheStatus*
+	GeocodedCity     string     `json:"geocoded_city" db:"geocoded_city"`
+	GeocodedProvince string     `json:"geocoded_province" db:"geocoded_province"`
+	ProcessedAt      *time.Time `json:"processed_at" db:"processed_at"`
+
+	// Manual review of flagged items; see ReviewStatus.
+	ReviewStatus  ReviewStatus `json:"review_status" db:"review_status"`
+	ReviewerID    *int64       `json:"reviewer_id" db:"reviewer_id"`
+	ReviewComment string       `json:"review_comment" db:"review_comment"`
+	CorrectedLat  *float64     `json:"corrected_lat" db:"corrected_lat"`
+	CorrectedLng  *float64     `json:"corrected_lng" db:"corrected_lng"`
+	ReviewedAt    *time.Time   `json:"reviewed_at" db:"reviewed_at"`
+}
+
+// ReprocessFilter selects the items of a batch to process again. Items must
+// match every non-empty list; an empty filter matches all items.
+type ReprocessFilter struct {
+	GeocodeStatuses []string `json:"geocode_status"`
+	ReasonCodes     []string `json:"reason_codes"`
+	Providers       []string `json:"providers"`
+	AccuracyLevels  []string `json:"accuracy_levels"`
+}
+
+// IsEmpty reports whether the filter matches every item.
+func (f ReprocessFilter) IsEmpty() bool {
+	return len(f.GeocodeStatuses) == 0 && len(f.ReasonCodes) == 0 && len(f.Providers) == 0 && len(f.AccuracyLevels) == 0
+}
+
+// ReprocessRequest is the body of POST /api/batches/:id/reprocess.
+type ReprocessRequest struct {
+	ReprocessFilter
+	BypassCache bool `json:"bypass_cache"`
 }
 
 // BatchRepository defines the interface for batch data access
 type BatchRepository interface {
 	CreateBatch(ctx context.Context, batch *Batch) error
 	GetBatchByID(ctx context.Context, id uuid.UUID) (*Batch, error)
-	GetBatchesByUserID(ctx context.Context, userID int64) ([]Batch, error)
+	// GetBatchesByUserID lists the batches the user owns or has been shared
+	// that match filter, newest first, with Access set.
+	GetBatchesByUserID(ctx context.Context, userID int64, filter BatchFilter) ([]Batch, error)
+	UpdateBatchMetadata(ctx context.Context, id uuid.UUID, name string, meta BatchMetadata) error
 	UpdateBatchStatus(ctx context.Context, id uuid.UUID, status BatchStatus) error
+	SetBatchArchived(ctx context.Context, id uuid.UUID, archived bool) error
+	// DeleteBatch removes the batch, its items and its courier_performance rows.
+	DeleteBatch(ctx context.Context, id uuid.UUID) error
+
+	// GetBatchPermission returns the best permission the batch's shares give
+	// userID directly or through a team, or "" if none.
+	GetBatchPermission(ctx context.Context, batchID uuid.UUID, userID int64) (BatchPermission, error)
+	ListBatchShares(ctx context.Context, batchID uuid.UUID) ([]BatchShare, error)
+	// UpsertBatchShare creates the share or updates the permission of the
+	// existing one for the same user or team. It returns
+	// ErrShareTargetNotFound for an unknown user or team.
+	UpsertBatchShare(ctx context.Context, share *BatchShare) error
+	// DeleteBatchShare returns sql.ErrNoRows if the batch has no such share.
+	DeleteBatchShare(ctx context.Context, batchID uuid.UUID, shareID int64) error
+	// TransferBatchOwnership makes toUserID the owner, with the batch's
+	// courier_performance rows; with keepAccess fromUserID gets an editor
+	// share. It returns ErrShareTargetNotFound for an unknown user.
+	TransferBatchOwnership(ctx context.Context, batchID uuid.UUID, fromUserID, toUserID int64, keepAccess bool) error
 
 	// BatchItem methods
 	UpsertBatchItems(ctx context.Context, items []BatchItem) error
 	GetBatchItemsByBatchID(ctx context.Context, batchID uuid.UUID) ([]BatchItem, error)
 	GetBatchItemsByBatchIDAndStatus(ctx context.Context, batchID uuid.UUID, status string) ([]BatchItem, error)
+	// StreamBatchItems calls fn for every item in upload order without loading
+	// the whole batch; returning an error from fn stops the iteration.
+	StreamBatchItems(ctx context.Context, batchID uuid.UUID, fn func(*BatchItem) error) error
+	// MarkBatchItemsPending queues the items matching filter for
+	// (re)processing and returns how many there were.
+	MarkBatchItemsPending(ctx context.Context, batchID uuid.UUID, filter ReprocessFilter) (int, error)
+	// GetPendingBatchItems returns up to limit items still waiting to be processed.
+	GetPendingBatchItems(ctx context.Context, batchID uuid.UUID, limit int) ([]BatchItem, error)
+	CountBatchItemsByStatus(ctx context.Context, batchID uuid.UUID, status string) (int, error)
+	// QueryBatchItems returns one filtered, sorted page using keyset pagination.
+	QueryBatchItems(ctx context.Context, batchID uuid.UUID, q BatchItemQuery) (*BatchItemPage, error)
+	// GetBatchStats aggregates a batch in SQL; Histogram is left empty.
+	GetBatchStats(ctx context.Context, batchID uuid.UUID) (*BatchStats, error)
+	// CountDistanceBuckets counts distances per width_bucket index over the
+	// ascending edges: 0 is below edges[0], len(edges) is at or above the last.
+	CountDistanceBuckets(ctx context.Context, batchID uuid.UUID, edges []float64) (map[int]int, error)
+	// DiffBatchItems joins two batches on connote and calls fn per connote in
+	// connote order; the side missing from a batch is nil.
+	DiffBatchItems(ctx context.Context, baseID, targetID uuid.UUID, fn func(base, target *BatchDiffSide) error) error
+	// ListOrphanBatchItems returns items that have only system data and items
+	// that have only field data.
+	ListOrphanBatchItems(ctx context.Context, batchID uuid.UUID) (systemOnly, fieldOnly []OrphanItem, err error)
+	// MergeOrphanBatchItems moves the field data of fieldItemID onto
+	// systemItemID, deletes the field item and leaves the merged item pending.
+	// It returns ErrNotOrphanPair unless both are still orphans of the batch.
+	MergeOrphanBatchItems(ctx context.Context, batchID, systemItemID, fieldItemID uuid.UUID) error
+	// ReplaceUploadIssues swaps the stored issues of one upload source for issues.
+	ReplaceUploadIssues(ctx context.Context, batchID uuid.UUID, source string, issues []UploadIssue) error
+	ListUploadIssues(ctx context.Context, batchID uuid.UUID) ([]BatchUploadIssue, error)
+
+	// ListReviewQueue returns one page of the batch's items matching q, worst
+	// distance first, with counts per review status.
+	ListReviewQueue(ctx context.Context, batchID uuid.UUID, q ReviewQueueQuery) (*ReviewQueuePage, error)
+	// ListClaimedReviews returns the items reviewerID has in review across
+	// all batches, oldest claim first.
+	ListClaimedReviews(ctx context.Context, reviewerID int64, limit int) ([]ReviewItem, error)
+	// ApplyReview moves the items of the batch that are in one of u.From (and
+	// claimed by u.ReviewerID if u.RequireOwn) to u.To, sets the accuracy
+	// override of their courier_performance rows and returns the moved IDs.
+	ApplyReview(ctx context.Context, batchID uuid.UUID, u ReviewUpdate) ([]uuid.UUID, error)
+
+	// ListBatchesForItemPurge returns up to limit batches without an active
+	// job whose owner's item retention has passed since their last update.
+	ListBatchesForItemPurge(ctx context.Context, limit int) ([]uuid.UUID, error)
+	// PurgeBatchItems stores summary on the batch, archives it and deletes its
+	// items; courier_performance rows are kept for the analytics. It reports
+	// false if the batch was already purged or a job was queued meanwhile.
+	PurgeBatchItems(ctx context.Context, id uuid.UUID, summary *BatchStats) (bool, error)
+	// GetBatchSummary returns the summary stored by PurgeBatchItems, or nil.
+	GetBatchSummary(ctx context.Context, id uuid.UUID) (*BatchStats, error)
+	// ListBatchesForDeletion returns up to limit batches without an active
+	// job that are older than their owner's batch retention.
+	ListBatchesForDeletion(ctx context.Context, limit int) ([]uuid.UUID, error)
 }
 
 type SystemRecord struct {
@@ -74,19 +221,134 @@ type FieldRecord struct {
 	ReportDate string  `json:"report_date"`
 }
 
+// ColumnMapping names the source column (header text, case-insensitive) for
+// each field of a spreadsheet import. Empty fields fall back to common header
+// aliases such as "alamat" or "no_resi".
+type ColumnMapping struct {
+	Connote       string `json:"connote"`
+	RecipientName string `json:"recipient_name"`
+	Address       string `json:"address"`
+	Lat           string `json:"lat"`
+	Lng           string `json:"lng"`
+	ReportedBy    string `json:"reported_by"`
+	ReportDate    string `json:"report_date"`
+}
+
+// ImportOptions controls how an uploaded CSV/XLSX file is read.
+type ImportOptions struct {
+	Filename  string
+	Mapping   ColumnMapping
+	Delimiter rune // 0 auto-detects
+}
+
+// RowError describes one rejected row of an import. Row is the 1-based line
+// (CSV) or row number (XLSX) in the source file.
+type RowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportReport is returned by the file upload endpoints.
+type ImportReport struct {
+	Format             string        `json:"format"`
+	Encoding           string        `json:"encoding,omitempty"`
+	Delimiter          string        `json:"delimiter,omitempty"`
+	TotalRows          int           `json:"total_rows"`
+	Imported           int           `json:"imported"`
+	Rejected           int           `json:"rejected"`
+	RowErrors          []RowError    `json:"row_errors"`
+	RowErrorsTruncated bool          `json:"row_errors_truncated"`
+	Quality            *UploadReport `json:"quality,omitempty"` // system data only
+}
+
+// ExportOptions controls GET /api/batches/:id/export.
+type ExportOptions struct {
+	Format  string   // "csv" or "xlsx"
+	Columns []string // column keys; empty uses the default set
+	Locale  string   // "id" (decimal comma) or "en"
+}
+
 // BatchService defines the interface for batch business logic
 type BatchService interface {
-	CreateBatch(ctx context.Context, userID int64, name string) (*Batch, error)
+	// CreateBatch and UpdateBatchMetadata normalise the metadata; the source
+	// defaults to CSV.
+	CreateBatch(ctx context.Context, userID int64, name string, meta BatchMetadata) (*Batch, error)
+	UpdateBatchMetadata(ctx context.Context, userID int64, batchID uuid.UUID, name string, meta BatchMetadata) (*Batch, error)
 	GetBatch(ctx context.Context, id uuid.UUID) (*Batch, error)
-	ListUserBatches(ctx context.Context, userID int64) ([]Batch, error)
+	// ListUserBatches lists and searches the batches the user owns or that
+	// are shared with them.
+	ListUserBatches(ctx context.Context, userID int64, filter BatchFilter) ([]Batch, error)
+	// ListBatchShares is open to anyone with access to the batch; ShareBatch,
+	// UnshareBatch and TransferBatchOwnership only to its owner.
+	ListBatchShares(ctx context.Context, userID int64, batchID uuid.UUID) ([]BatchShare, error)
+	ShareBatch(ctx context.Context, userID int64, batchID uuid.UUID, req ShareBatchRequest) (*BatchShare, error)
+	UnshareBatch(ctx context.Context, userID int64, batchID uuid.UUID, shareID int64) error
+	TransferBatchOwnership(ctx context.Context, userID int64, batchID uuid.UUID, newOwnerID int64, keepAccess bool) error
+	// ArchiveBatch makes a batch read-only and hides it from the default list;
+	// UnarchiveBatch reverses that unless retention already purged the items.
+	ArchiveBatch(ctx context.Context, userID int64, batchID uuid.UUID) error
+	UnarchiveBatch(ctx context.Context, userID int64, batchID uuid.UUID) error
+	// DeleteBatch removes a batch without an active job, with its items and
+	// courier_performance rows.
+	DeleteBatch(ctx context.Context, userID int64, batchID uuid.UUID) error
+	// EnforceRetention applies every user's retention policy. It is run by the
+	// scheduler, not on behalf of a user.
+	EnforceRetention(ctx context.Context) (*RetentionResult, error)
 
 	// FIX BUG-03: userID added to UploadSystemData, UploadFieldData, GetBatchResults
 	// so the service layer can verify batch ownership before allowing the operation.
-	UploadSystemData(ctx context.Context, userID int64, batchID uuid.UUID, records []SystemRecord) error
-	UploadFieldData(ctx context.Context, userID int64, batchID uuid.UUID, records []FieldRecord) error
+	// UploadSystemData scores every address and reports the low-quality ones.
+	// Both uploads validate their rows first; in strict mode an upload with
+	// issues returns ErrUploadRejected together with the validation report.
+	UploadSystemData(ctx context.Context, userID int64, batchID uuid.UUID, records []SystemRecord, mode UploadMode) (*UploadReport, error)
+	UploadFieldData(ctx context.Context, userID int64, batchID uuid.UUID, records []FieldRecord, mode UploadMode) (*UploadValidation, error)
+	// ListUploadIssues returns the issues recorded by lenient uploads.
+	ListUploadIssues(ctx context.Context, userID int64, batchID uuid.UUID) ([]BatchUploadIssue, error)
 
+	// ImportSystemFile / ImportFieldFile stream-parse a CSV or XLSX upload and
+	// store valid rows; unparseable rows are reported instead of failing the upload.
+	ImportSystemFile(ctx context.Context, userID int64, batchID uuid.UUID, src io.Reader, opts ImportOptions) (*ImportReport, error)
+	ImportFieldFile(ctx context.Context, userID int64, batchID uuid.UUID, src io.Reader, opts ImportOptions) (*ImportReport, error)
+
+	// ProcessBatch queues the batch for the worker pool.
 	ProcessBatch(ctx context.Context, userID int64, batchID uuid.UUID) error
+	// ReprocessBatch queues only the items matching the filter and returns
+	// how many were queued.
+	ReprocessBatch(ctx context.Context, userID int64, batchID uuid.UUID, req ReprocessRequest) (int, error)
+	GetBatchJob(ctx context.Context, userID int64, batchID uuid.UUID) (*BatchJob, error)
+	// RunBatchJob does the work of a claimed job, calling checkpoint after
+	// every saved chunk. Returning ctx.Err() means the job was interrupted.
+	RunBatchJob(ctx context.Context, job *BatchJob, checkpoint func(processed, total int) error) error
+	MarkBatchFailed(ctx context.Context, batchID uuid.UUID, reason string) error
+	// PauseBatch, ResumeBatch and CancelBatch control the batch's active job.
+	// Running work stops after the current item; resuming continues with the
+	// items that are still pending.
+	PauseBatch(ctx context.Context, userID int64, batchID uuid.UUID) error
+	ResumeBatch(ctx context.Context, userID int64, batchID uuid.UUID) error
+	CancelBatch(ctx context.Context, userID int64, batchID uuid.UUID) error
 	GetBatchResults(ctx context.Context, userID int64, batchID uuid.UUID) ([]BatchItem, error)
+	// ListBatchResults is GetBatchResults with filters, sorting and cursor pagination.
+	ListBatchResults(ctx context.Context, userID int64, batchID uuid.UUID, q BatchItemQuery) (*BatchItemPage, error)
+	// GetBatchStats returns distance percentiles, a histogram over the given
+	// bucket edges (defaults when empty) and provider/courier breakdowns.
+	GetBatchStats(ctx context.Context, userID int64, batchID uuid.UUID, edges []float64) (*BatchStats, error)
+	// DiffBatches compares two batches the user can view item by item.
+	DiffBatches(ctx context.Context, userID int64, baseID, targetID uuid.UUID) (*BatchDiff, error)
+	// ReconcileBatch reports orphan items and suggests fuzzy connote matches.
+	ReconcileBatch(ctx context.Context, userID int64, batchID uuid.UUID) (*ReconciliationReport, error)
+	// AcceptConnoteMatches merges the given system/field orphan pairs.
+	AcceptConnoteMatches(ctx context.Context, userID int64, batchID uuid.UUID, matches []ConnoteMatch) (*AcceptMatchesResult, error)
+	// ExportBatch validates opts and ownership before the first write to w, so
+	// callers can still report those errors as JSON.
+	ExportBatch(ctx context.Context, userID int64, batchID uuid.UUID, w io.Writer, opts ExportOptions) error
+
+	// GetReviewQueue lists a batch's flagged items for review; ReviewBatchItems
+	// applies one review transition to many items of a batch the user can
+	// edit. ListMyReviews returns the items the user has claimed.
+	GetReviewQueue(ctx context.Context, userID int64, batchID uuid.UUID, q ReviewQueueQuery) (*ReviewQueuePage, error)
+	ReviewBatchItems(ctx context.Context, userID int64, batchID uuid.UUID, req ReviewRequest) (*ReviewResult, error)
+	ListMyReviews(ctx context.Context, userID int64, limit int) ([]ReviewItem, error)
 
 	// ETL-specific methods: persist pipeline results to batch_items for Dashboard visibility
 	UpsertETLItems(ctx context.Context, batchID uuid.UUID, items []BatchItem) error
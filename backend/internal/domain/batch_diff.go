@@ -0,0 +1,73 @@
+package domain
+
+import "github.com/google/uuid"
+
+// Directions of a BatchItemChange.
+const (
+	DiffImproved  = "improved"
+	DiffRegressed = "regressed"
+	DiffChanged   = "changed" // coordinates moved without a better or worse result
+)
+
+// Fields reported in BatchItemChange.Fields.
+const (
+	DiffFieldAccuracy    = "accuracy_level"
+	DiffFieldDistance    = "distance"
+	DiffFieldCoordinates = "coordinates"
+)
+
+// BatchDiff compares a base batch with a target batch (usually a re-run of
+// the same shipments) by connote.
+type BatchDiff struct {
+	BaseBatchID   uuid.UUID         `json:"base_batch_id"`
+	TargetBatchID uuid.UUID         `json:"target_batch_id"`
+	Summary       BatchDiffSummary  `json:"summary"`
+	Changed       []BatchItemChange `json:"changed"`
+	OnlyInBase    []BatchDiffSide   `json:"only_in_base"`
+	OnlyInTarget  []BatchDiffSide   `json:"only_in_target"`
+	// Truncated is set when a list hit the response limit; Summary still
+	// counts every item.
+	Truncated bool `json:"truncated"`
+}
+
+// BatchDiffSummary aggregates the whole comparison. Distance means cover the
+// matched items that have a distance in both batches.
+type BatchDiffSummary struct {
+	Matched      int `json:"matched"`
+	OnlyInBase   int `json:"only_in_base"`
+	OnlyInTarget int `json:"only_in_target"`
+	Unchanged    int `json:"unchanged"`
+	Changed      int `json:"changed"`
+	Improved     int `json:"improved"`
+	Regressed    int `json:"regressed"`
+
+	BaseAccuracy   map[string]int `json:"base_accuracy"`
+	TargetAccuracy map[string]int `json:"target_accuracy"`
+
+	BaseMeanDistanceKm   *float64 `json:"base_mean_distance_km"`
+	TargetMeanDistanceKm *float64 `json:"target_mean_distance_km"`
+	MeanDistanceDeltaKm  *float64 `json:"mean_distance_delta_km"` // negative is an improvement
+}
+
+// BatchDiffSide is one batch's version of an item.
+type BatchDiffSide struct {
+	ItemID        uuid.UUID  `json:"item_id"`
+	Connote       string     `json:"connote"`
+	AccuracyLevel string     `json:"accuracy_level"`
+	GeocodeStatus string     `json:"geocode_status"`
+	ReasonCode    ReasonCode `json:"reason_code"`
+	DistanceKm    *float64   `json:"distance_km"`
+	SystemLat     *float64   `json:"system_lat"`
+	SystemLng     *float64   `json:"system_lng"`
+}
+
+// BatchItemChange is a connote whose result differs between the batches.
+type BatchItemChange struct {
+	Connote           string        `json:"connote"`
+	Fields            []string      `json:"fields"` // DiffField*
+	Direction         string        `json:"direction"`
+	DistanceDeltaKm   *float64      `json:"distance_delta_km"`
+	CoordinateShiftKm *float64      `json:"coordinate_shift_km"`
+	Base              BatchDiffSide `json:"base"`
+	Target            BatchDiffSide `json:"target"`
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BatchJobStatus string
+
+const (
+	BatchJobQueued    BatchJobStatus = "queued"
+	BatchJobRunning   BatchJobStatus = "running"
+	BatchJobCompleted BatchJobStatus = "completed"
+	BatchJobFailed    BatchJobStatus = "failed"
+	BatchJobPaused    BatchJobStatus = "paused"
+	BatchJobCancelled BatchJobStatus = "cancelled"
+)
+
+// ErrBatchJobActive is returned when a batch already has a queued, running
+// or paused job.
+var ErrBatchJobActive = errors.New("batch is already queued, processing or paused")
+
+// ErrBatchJobNotActive is returned when pause, resume or cancel finds no job
+// in a state the action applies to.
+var ErrBatchJobNotActive = errors.New("batch has no job that can be changed this way")
+
+// ErrBatchJobStopping is returned when resuming a job whose worker has not
+// finished its current item yet.
+var ErrBatchJobStopping = errors.New("batch job is still stopping, try again shortly")
+
+// ErrBatchJobStopped is returned to a worker whose job was paused or
+// cancelled while it ran.
+var ErrBatchJobStopped = errors.New("batch job was paused or cancelled")
+
+// ErrBatchJobLeaseLost is returned by a checkpoint when the job's lease
+// expired and another worker may have claimed it.
+var ErrBatchJobLeaseLost = errors.New("batch job lease lost")
+
+// BatchJob is one persisted request to process a batch.
+type BatchJob struct {
+	ID          int64          `json:"id"`
+	BatchID     uuid.UUID      `json:"batch_id"`
+	UserID      int64          `json:"user_id"`
+	Status      BatchJobStatus `json:"status"`
+	Attempts    int            `json:"attempts"`
+	Processed   int            `json:"processed"`
+	Total       int            `json:"total"`
+	LastError   string         `json:"last_error,omitempty"`
+	BypassCache bool           `json:"bypass_cache"`
+	LockedBy    *string        `json:"-"`
+	LockedUntil *time.Time     `json:"-"`
+	RunAfter    time.Time      `json:"run_after"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	CompletedAt *time.Time     `json:"completed_at,omitempty"`
+}
+
+// BatchJobRepository persists the batch job queue.
+type BatchJobRepository interface {
+	// Enqueue returns ErrBatchJobActive if the batch already has an active job.
+	Enqueue(ctx context.Context, batchID uuid.UUID, userID int64, bypassCache bool) (*BatchJob, error)
+	// Claim takes the oldest runnable job (queued, or running with an expired
+	// lease) for workerID. It returns nil when the queue is empty.
+	Claim(ctx context.Context, workerID string, lease time.Duration) (*BatchJob, error)
+	// Checkpoint records progress and renews the lease. Progress is saved even
+	// when the job was paused or cancelled meanwhile; ErrBatchJobStopped then
+	// tells the worker to stop.
+	Checkpoint(ctx context.Context, jobID int64, workerID string, processed, total int, lease time.Duration) error
+	// ExtendLease renews the lease between checkpoints. Like Checkpoint it
+	// returns ErrBatchJobLeaseLost if workerID no longer holds the job.
+	ExtendLease(ctx context.Context, jobID int64, workerID string, lease time.Duration) error
+	// Complete, Retry and Fail only apply while workerID holds the running
+	// job; otherwise they return ErrBatchJobLeaseLost and change nothing, so
+	// a job paused, cancelled or claimed by another worker keeps its state.
+	Complete(ctx context.Context, jobID int64, workerID string) error
+	// Retry puts the job back in the queue to run again at runAfter.
+	Retry(ctx context.Context, jobID int64, workerID, lastError string, runAfter time.Time) error
+	Fail(ctx context.Context, jobID int64, workerID, lastError string) error
+	// Release unlocks a job held by workerID. A running job goes back to the
+	// queue without counting an attempt (graceful shutdown); a paused or
+	// cancelled one keeps its status.
+	Release(ctx context.Context, jobID int64, workerID string) error
+	// Transition moves the batch's job from one of the from statuses to to and
+	// returns it, or returns nil if there is no such job.
+	Transition(ctx context.Context, batchID uuid.UUID, from []BatchJobStatus, to BatchJobStatus) (*BatchJob, error)
+	GetLatestByBatchID(ctx context.Context, batchID uuid.UUID) (*BatchJob, error)
+}
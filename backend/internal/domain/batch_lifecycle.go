@@ -0,0 +1,16 @@
+package domain
+
+import "errors"
+
+// ErrBatchArchived is returned when changing the items of an archived batch.
+var ErrBatchArchived = errors.New("batch is archived")
+
+// ErrBatchPurged is returned when unarchiving a batch whose item detail was
+// removed by retention; only its summary is left.
+var ErrBatchPurged = errors.New("batch items were purged by the retention policy")
+
+// RetentionResult reports one run of the retention job.
+type RetentionResult struct {
+	ItemsPurged    int `json:"items_purged"`    // batches reduced to their summary
+	BatchesDeleted int `json:"batches_deleted"` // batches removed with their courier_performance rows
+}
@@ -0,0 +1,43 @@
+package domain
+
+// Where a batch's data came from.
+const (
+	BatchSourceCSV     = "csv"
+	BatchSourceETL     = "etl"
+	BatchSourceWebhook = "webhook"
+	BatchSourceERP     = "erp"
+)
+
+// BatchMetadata describes a batch for searching. Delivery dates are
+// YYYY-MM-DD; tags are stored lower-cased.
+type BatchMetadata struct {
+	Client       string   `json:"client"`
+	Region       string   `json:"region"`
+	DeliveryFrom string   `json:"delivery_from,omitempty"`
+	DeliveryTo   string   `json:"delivery_to,omitempty"`
+	Source       string   `json:"source"` // BatchSource*
+	Tags         []string `json:"tags"`
+}
+
+// BatchFilter selects the batches listed by GET /api/batches. Text matches
+// ignore case; every set field must match.
+type BatchFilter struct {
+	Scope    string // BatchScope*
+	Archived bool
+	Query    string   // substring of the name, client or region
+	Client   string   // whole client name
+	Region   string   // whole region name
+	Sources  []string // any of
+	Statuses []string // any of
+	Tags     []string // all of
+
+	// DeliveryFrom/DeliveryTo (YYYY-MM-DD) match batches whose delivery range
+	// overlaps them; CreatedFrom/CreatedTo bound the creation date inclusively.
+	DeliveryFrom string
+	DeliveryTo   string
+	CreatedFrom  string
+	CreatedTo    string
+
+	Limit  int // 0 lists every match
+	Offset int
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "errors"
+
+// Sort keys accepted by BatchItemQuery.Sort.
+const (
+	BatchItemSortCreatedAt = "created_at"
+	BatchItemSortUpdatedAt = "updated_at"
+	BatchItemSortConnote   = "connote"
+	BatchItemSortDistance  = "distance_km"
+)
+
+// Which coordinates a bounding box is matched against.
+const (
+	BBoxMatchAny    = "any"
+	BBoxMatchField  = "field"
+	BBoxMatchSystem = "system"
+)
+
+// ErrInvalidCursor is returned for a cursor that is malformed or was issued
+// for a different sort key or direction.
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+// BoundingBox is a WGS84 rectangle in degrees.
+type BoundingBox struct {
+	MinLng, MinLat, MaxLng, MaxLat float64
+	Match                          string // BBoxMatch*; empty means any
+}
+
+// BatchItemQuery filters, sorts and pages batch results. Zero values mean
+// "no filter". The repository returns every matching item for Limit 0; the
+// service always pages, so a full download goes through the export.
+type BatchItemQuery struct {
+	AccuracyLevels  []string
+	GeocodeStatuses []string
+	CourierIDs      []string
+	MinDistanceKm   *float64
+	MaxDistanceKm   *float64
+	Connote         string // case-insensitive substring
+	BBox            *BoundingBox
+
+	Sort   string // BatchItemSort*; empty means created_at
+	Desc   bool
+	Limit  int
+	Cursor string // NextCursor of the previous page
+}
+
+// BatchItemPage is one page of batch results.
+type BatchItemPage struct {
+	Items      []BatchItem `json:"items"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+	HasMore    bool        `json:"has_more"`
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// Why a fuzzy connote match was suggested, strongest first.
+const (
+	MatchNormalized   = "normalized"        // differs only in case, spaces or punctuation
+	MatchLeadingZeros = "leading_zeros"     // "000123" vs "123"
+	MatchPrefix       = "prefix"            // "JNE00123" vs "123"
+	MatchTransposed   = "transposed_digits" // one pair of adjacent characters swapped
+)
+
+// ErrNotOrphanPair is returned when an accepted match does not pair a
+// system-only item with a field-only item of the same batch.
+var ErrNotOrphanPair = errors.New("items are not a system-only and field-only pair")
+
+// OrphanItem is a batch item that only received one side of the data:
+// system records have an address, field records have coordinates.
+type OrphanItem struct {
+	ItemID        uuid.UUID `json:"item_id"`
+	Connote       string    `json:"connote"`
+	RecipientName string    `json:"recipient_name,omitempty"`
+	SystemAddress string    `json:"system_address,omitempty"`
+	CourierID     string    `json:"courier_id,omitempty"`
+	FieldLat      *float64  `json:"field_lat,omitempty"`
+	FieldLng      *float64  `json:"field_lng,omitempty"`
+}
+
+// ConnoteMatch pairs a system-only item with a field-only item.
+type ConnoteMatch struct {
+	SystemItemID  uuid.UUID `json:"system_item_id" binding:"required"`
+	FieldItemID   uuid.UUID `json:"field_item_id" binding:"required"`
+	SystemConnote string    `json:"system_connote,omitempty"`
+	FieldConnote  string    `json:"field_connote,omitempty"`
+	Reason        string    `json:"reason,omitempty"` // Match*
+	Score         float64   `json:"score,omitempty"`
+}
+
+// ReconciliationReport lists the orphans of a batch and suggested matches.
+// The orphan lists are capped; the counts are not.
+type ReconciliationReport struct {
+	BatchID         uuid.UUID      `json:"batch_id"`
+	SystemOnlyCount int            `json:"system_only_count"`
+	FieldOnlyCount  int            `json:"field_only_count"`
+	SystemOnly      []OrphanItem   `json:"system_only"`
+	FieldOnly       []OrphanItem   `json:"field_only"`
+	Suggestions     []ConnoteMatch `json:"suggestions"`
+	Truncated       bool           `json:"truncated"`
+}
+
+// AcceptMatchesRequest confirms matches to merge.
+type AcceptMatchesRequest struct {
+	Matches []ConnoteMatch `json:"matches" binding:"required,min=1,dive"`
+}
+
+// AcceptMatchesResult reports the merged pairs and the ones that were refused.
+type AcceptMatchesResult struct {
+	Merged   int             `json:"merged"`
+	Rejected []RejectedMatch `json:"rejected"`
+}
+
+// RejectedMatch is an accepted match that could not be merged.
+type RejectedMatch struct {
+	ConnoteMatch
+	Error string `json:"error"`
+}
@@ -0,0 +1,102 @@
+package domain
+
+import "github.com/google/uuid"
+
+// ReviewStatus is where a flagged item stands in manual review. An item is
+// claimed (in_review) by one reviewer, who then resolves it or releases it
+// back to unreviewed; a resolved item may be claimed again to change the
+// outcome.
+type ReviewStatus string
+
+const (
+	ReviewUnreviewed       ReviewStatus = "unreviewed"
+	ReviewInReview         ReviewStatus = "in_review"
+	ReviewAccepted         ReviewStatus = "accepted"          // the distance is explained; count it as accurate
+	ReviewAddressCorrected ReviewStatus = "address_corrected" // the master address was wrong; Corrected* holds the right point
+	ReviewCourierDisputed  ReviewStatus = "courier_disputed"  // the courier reported from the wrong place
+)
+
+// reviewTransitions lists the states each status may be reached from.
+var reviewTransitions = map[ReviewStatus][]ReviewStatus{
+	ReviewUnreviewed:       {ReviewInReview},
+	ReviewInReview:         {ReviewUnreviewed, ReviewAccepted, ReviewAddressCorrected, ReviewCourierDisputed},
+	ReviewAccepted:         {ReviewInReview},
+	ReviewAddressCorrected: {ReviewInReview},
+	ReviewCourierDisputed:  {ReviewInReview},
+}
+
+// ReviewSources returns the states an item may move to s from, or nil for
+// an unknown status.
+func ReviewSources(s ReviewStatus) []ReviewStatus {
+	return reviewTransitions[s]
+}
+
+// IsResolved reports whether s is a final review outcome.
+func (s ReviewStatus) IsResolved() bool {
+	return s == ReviewAccepted || s == ReviewAddressCorrected || s == ReviewCourierDisputed
+}
+
+// AccuracyOverride is the accuracy the analytics use instead of the computed
+// one for an item resolved with s, or "" to keep the computed accuracy.
+func (s ReviewStatus) AccuracyOverride() string {
+	switch s {
+	case ReviewAccepted, ReviewAddressCorrected:
+		return "accurate"
+	case ReviewCourierDisputed:
+		return "inaccurate"
+	}
+	return ""
+}
+
+// ReviewQueueQuery selects the items of a review queue. Empty lists match
+// everything, except AccuracyLevels, which defaults to the flagged level.
+type ReviewQueueQuery struct {
+	Statuses       []ReviewStatus
+	AccuracyLevels []string
+	ReviewerID     *int64 // only items claimed by this reviewer
+	Limit          int
+	Offset         int
+}
+
+// ReviewQueuePage is one page of a batch's review queue, worst distance
+// first. Counts covers the whole queue per review status.
+type ReviewQueuePage struct {
+	Items  []BatchItem          `json:"items"`
+	Counts map[ReviewStatus]int `json:"counts"`
+}
+
+// ReviewItem is a claimed item in a reviewer's cross-batch work queue.
+type ReviewItem struct {
+	BatchItem
+	BatchName string `json:"batch_name"`
+}
+
+// ReviewRequest is the body of POST /batches/:id/review. It moves every
+// listed item to Status; CorrectedLat/Lng are required for
+// address_corrected unless the field coordinate should be used.
+type ReviewRequest struct {
+	ItemIDs      []uuid.UUID  `json:"item_ids" binding:"required,min=1"`
+	Status       ReviewStatus `json:"status" binding:"required"`
+	Comment      string       `json:"comment"`
+	CorrectedLat *float64     `json:"corrected_lat"`
+	CorrectedLng *float64     `json:"corrected_lng"`
+}
+
+// ReviewResult reports the items moved and the ones whose current state or
+// reviewer did not allow the transition.
+type ReviewResult struct {
+	Updated int         `json:"updated"`
+	Skipped []uuid.UUID `json:"skipped"`
+}
+
+// ReviewUpdate is one bulk transition as applied by the repository.
+type ReviewUpdate struct {
+	ItemIDs      []uuid.UUID
+	From         []ReviewStatus
+	To           ReviewStatus
+	ReviewerID   int64
+	RequireOwn   bool // only items claimed by ReviewerID
+	Comment      string
+	CorrectedLat *float64
+	CorrectedLng *float64
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrShareTargetNotFound is returned when sharing with, adding to a team or
+// transferring to a user or team that does not exist.
+var ErrShareTargetNotFound = errors.New("user or team not found")
+
+// BatchPermission is what a user may do with a batch. Viewers read results,
+// stats and exports; editors also upload, process and edit metadata; only
+// the owner deletes, shares or transfers the batch.
+type BatchPermission string
+
+const (
+	BatchPermissionViewer BatchPermission = "viewer"
+	BatchPermissionEditor BatchPermission = "editor"
+	BatchPermissionOwner  BatchPermission = "owner"
+)
+
+var batchPermissionRank = map[BatchPermission]int{
+	BatchPermissionViewer: 1,
+	BatchPermissionEditor: 2,
+	BatchPermissionOwner:  3,
+}
+
+// Allows reports whether p includes need. The empty permission allows nothing.
+func (p BatchPermission) Allows(need BatchPermission) bool {
+	return batchPermissionRank[p] > 0 && batchPermissionRank[p] >= batchPermissionRank[need]
+}
+
+// Which batches GET /api/batches lists.
+const (
+	BatchScopeAll    = ""       // owned and shared
+	BatchScopeOwned  = "owned"  // owned by the user
+	BatchScopeShared = "shared" // shared with the user or one of their teams
+)
+
+// BatchShare grants one user or one team access to a batch; exactly one of
+// UserID and TeamID is set.
+type BatchShare struct {
+	ID         int64           `json:"id"`
+	BatchID    uuid.UUID       `json:"batch_id"`
+	UserID     *int64          `json:"user_id,omitempty"`
+	TeamID     *int64          `json:"team_id,omitempty"`
+	Name       string          `json:"name"` // of the user or team
+	Permission BatchPermission `json:"permission"`
+	CreatedBy  int64           `json:"created_by"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// ShareBatchRequest is the body of PUT /batches/:id/shares. Sharing again
+// with the same user or team changes the permission.
+type ShareBatchRequest struct {
+	UserID     *int64          `json:"user_id"`
+	TeamID     *int64          `json:"team_id"`
+	Permission BatchPermission `json:"permission"`
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BatchStats summarises a batch's results. Distances are in kilometres and
+// only cover items with both system and field coordinates.
+type BatchStats struct {
+	BatchID    uuid.UUID         `json:"batch_id"`
+	TotalItems int               `json:"total_items"`
+	Statuses   map[string]int    `json:"statuses"` // geocode_status -> items
+	Distance   DistanceStats     `json:"distance"`
+	Histogram  []HistogramBucket `json:"histogram"`
+	Providers  []GroupStats      `json:"providers"`
+	Couriers   []GroupStats      `json:"couriers"`
+	Cache      CacheStats        `json:"cache"`
+
+	// Reviews counts items per review status. Accuracy counts items per
+	// accuracy level after reviewer overrides; unscored items are left out.
+	Reviews  map[ReviewStatus]int `json:"reviews"`
+	Accuracy map[string]int       `json:"accuracy"`
+
+	// ItemsPurgedAt is set when the stats are the summary kept after the
+	// retention policy removed the batch's items.
+	ItemsPurgedAt *time.Time `json:"items_purged_at,omitempty"`
+}
+
+// DistanceStats holds distance aggregates; the pointers are nil when no item
+// has a distance.
+type DistanceStats struct {
+	Count    int      `json:"count"`
+	MeanKm   *float64 `json:"mean_km"`
+	MedianKm *float64 `json:"median_km"`
+	P90Km    *float64 `json:"p90_km"`
+	P99Km    *float64 `json:"p99_km"`
+	MinKm    *float64 `json:"min_km"`
+	MaxKm    *float64 `json:"max_km"`
+}
+
+// HistogramBucket counts distances in [MinKm, MaxKm). A nil bound is open.
+type HistogramBucket struct {
+	MinKm *float64 `json:"min_km"`
+	MaxKm *float64 `json:"max_km"`
+	Count int      `json:"count"`
+}
+
+// GroupStats breaks the batch down by one key (provider or courier).
+type GroupStats struct {
+	Key       string   `json:"key"`
+	Items     int      `json:"items"`
+	Completed int      `json:"completed"`
+	Failed    int      `json:"failed"`
+	CacheHits int      `json:"cache_hits"`
+	MeanKm    *float64 `json:"mean_km"`
+	MedianKm  *float64 `json:"median_km"`
+	P90Km     *float64 `json:"p90_km"`
+}
+
+// CacheStats counts geocodes served from a cache. HitRatio is nil until
+// something has been geocoded.
+type CacheStats struct {
+	Lookups  int      `json:"lookups"`
+	Hits     int      `json:"hits"`
+	HitRatio *float64 `json:"hit_ratio"`
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// GroundTruthDataset is a set of addresses with surveyed, known-good
+// coordinates used to benchmark geocoding providers.
+type GroundTruthDataset struct {
+	ID         int64     `json:"id"`
+	UserID     int64     `json:"user_id"`
+	Name       string    `json:"name"`
+	EntryCount int       `json:"entry_count"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// GroundTruthEntry is one address and its known-good coordinate.
+type GroundTruthEntry struct {
+	ID        int64   `json:"id"`
+	DatasetID int64   `json:"dataset_id"`
+	Address   string  `json:"address"`
+	Province  string  `json:"province"` // optional; empty entries are grouped as "unknown"
+	Lat       float64 `json:"lat"`
+	Lng       float64 `json:"lng"`
+}
+
+type BenchmarkStatus string
+
+const (
+	BenchmarkStatusRunning   BenchmarkStatus = "running"
+	BenchmarkStatusCompleted BenchmarkStatus = "completed"
+	BenchmarkStatusFailed    BenchmarkStatus = "failed"
+)
+
+// BenchmarkReport tracks one benchmark run; Result is filled once it completes.
+type BenchmarkReport struct {
+	ID          int64            `json:"id"`
+	UserID      int64            `json:"user_id"`
+	DatasetID   int64            `json:"dataset_id"`
+	Status      BenchmarkStatus  `json:"status"`
+	Providers   []string         `json:"providers"`
+	Result      *BenchmarkResult `json:"result,omitempty"`
+	Error       string           `json:"error,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+}
+
+// BenchmarkResult is stored as JSONB on benchmark_reports.
+type BenchmarkResult struct {
+	TotalEntries int                 `json:"total_entries"`
+	Providers    []ProviderBenchmark `json:"providers"`
+}
+
+// BenchmarkStats summarises the error distribution of one provider over a set
+// of entries. Errors are measured in meters and only over hits.
+type BenchmarkStats struct {
+	Attempts       int     `json:"attempts"`
+	Hits           int     `json:"hits"`
+	HitRate        float64 `json:"hit_rate"`
+	MedianErrorM   float64 `json:"median_error_m"`
+	P90ErrorM      float64 `json:"p90_error_m"`
+	AvgLatencyMs   float64 `json:"avg_latency_ms"`
+	ProviderErrors int     `json:"provider_errors"` // failures other than "not found"
+}
+
+type ProviderBenchmark struct {
+	Provider string `json:"provider"`
+	BenchmarkStats
+	EstimatedCostUSD float64             `json:"estimated_cost_usd"`
+	Provinces        []ProvinceBenchmark `json:"provinces"`
+}
+
+type ProvinceBenchmark struct {
+	Province string `json:"province"`
+	BenchmarkStats
+}
+
+type BenchmarkRepository interface {
+	CreateDataset(ctx context.Context, ds *GroundTruthDataset, entries []GroundTruthEntry) error
+	GetDataset(ctx context.Context, id, userID int64) (*GroundTruthDataset, error)
+	ListDatasets(ctx context.Context, userID int64) ([]GroundTruthDataset, error)
+	DeleteDataset(ctx context.Context, id, userID int64) error
+	GetEntries(ctx context.Context, datasetID int64) ([]GroundTruthEntry, error)
+
+	CreateReport(ctx context.Context, report *BenchmarkReport) error
+	FinishReport(ctx context.Context, id int64, status BenchmarkStatus, result *BenchmarkResult, errMsg string) error
+	HeartbeatReport(ctx context.Context, id int64) error
+	// FailStaleReports fails running reports without a heartbeat for
+	// staleAfter and returns how many it failed.
+	FailStaleReports(ctx context.Context, staleAfter time.Duration, errMsg string) (int64, error)
+	GetReport(ctx context.Context, id, userID int64) (*BenchmarkReport, error)
+	ListReports(ctx context.Context, datasetID, userID int64) ([]BenchmarkReport, error)
+}
+
+type BenchmarkService interface {
+	CreateDataset(ctx context.Context, userID int64, name string, entries []GroundTruthEntry) (*GroundTruthDataset, error)
+	ListDatasets(ctx context.Context, userID int64) ([]GroundTruthDataset, error)
+	DeleteDataset(ctx context.Context, userID, datasetID int64) error
+
+	// StartBenchmark runs asynchronously; an empty providers list means every
+	// provider the user has configured.
+	StartBenchmark(ctx context.Context, userID, datasetID int64, providers []string) (*BenchmarkReport, error)
+	GetReport(ctx context.Context, userID, reportID int64) (*BenchmarkReport, error)
+	ListReports(ctx context.Context, userID, datasetID int64) ([]BenchmarkReport, error)
+
+	// Start begins failing reports orphaned by a restart; Stop cancels the
+	// running benchmarks, which are recorded as failed, and waits for them.
+	Start()
+	Stop()
+}
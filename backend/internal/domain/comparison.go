@@ -1,5 +1,9 @@
 package domain
 
+// AccuracyUnverifiable marks results whose geocode was too coarse (e.g. a city
+// centroid) for the distance to say anything about the courier.
+const AccuracyUnverifiable = "unverifiable"
+
 type ValidationRequestItem struct {
 	ID            string  `json:"id"`
 	SystemAddress string  `json:"system_address"`
@@ -22,6 +26,15 @@ type ValidationResult struct {
 	AccuracyLevel string  `json:"accuracy_level"`
 	Provider      string  `json:"provider"`
 	Error         string  `json:"error,omitempty"`
+
+	ReasonCode  ReasonCode        `json:"reason_code"`
+	Explanation ReasonExplanation `json:"explanation"`
+}
+
+// SetReason records the reason code together with its explanation.
+func (r *ValidationResult) SetReason(code ReasonCode) {
+	r.ReasonCode = code
+	r.Explanation = code.Explain()
 }
 
 type BatchValidationResponse struct {
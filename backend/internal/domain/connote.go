@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ERPSyncBatchPrefix starts the batch reference of ERP syncs whose payload
+// names no batch of its own.
+const ERPSyncBatchPrefix = "ERP-SYNC-"
+
+// Where a connote event was recorded.
+const (
+	ConnoteOriginBatchItem    = "batch_item"    // a row of a CSV, ETL, webhook or ERP batch, or its courier event once purged
+	ConnoteOriginCourierEvent = "courier_event" // a webhook or ERP point outside any batch
+)
+
+// ConnoteEvent is one appearance of a connote. Lat/Lng is the courier's
+// reported point and SystemLat/Lng the expected one; Accuracy already
+// reflects reviewer overrides.
+type ConnoteEvent struct {
+	Origin     string    `json:"origin"` // ConnoteOrigin*
+	Source     string    `json:"source"` // BatchSource*; empty for batches created before sources were recorded
+	OccurredAt time.Time `json:"occurred_at"`
+	// BatchRef is the batch ID, or the reference a webhook or ERP sync sent.
+	BatchRef      string       `json:"batch_ref"`
+	BatchName     string       `json:"batch_name,omitempty"`
+	CourierID     string       `json:"courier_id"`
+	Lat           *float64     `json:"lat"`
+	Lng           *float64     `json:"lng"`
+	SystemLat     *float64     `json:"system_lat"`
+	SystemLng     *float64     `json:"system_lng"`
+	DistanceKm    *float64     `json:"distance_km"`
+	Accuracy      string       `json:"accuracy"`
+	GeocodeStatus string       `json:"geocode_status,omitempty"`
+	ReasonCode    ReasonCode   `json:"reason_code,omitempty"`
+	ReviewStatus  ReviewStatus `json:"review_status,omitempty"`
+}
+
+// ConnoteTimeline lists a connote's most recent events, oldest first.
+// Truncated is set when older events were left out.
+type ConnoteTimeline struct {
+	Connote   string         `json:"connote"`
+	Events    []ConnoteEvent `json:"events"`
+	Truncated bool           `json:"truncated"`
+}
+
+type ConnoteRepository interface {
+	// ListConnoteEvents returns up to limit of the newest events for connote
+	// that userID can see: items of batches they own or that are shared with
+	// them, and their own courier events.
+	ListConnoteEvents(ctx context.Context, userID int64, connote string, limit int) ([]ConnoteEvent, error)
+}
+
+type ConnoteService interface {
+	GetTimeline(ctx context.Context, userID int64, connote string, limit int) (*ConnoteTimeline, error)
+}
@@ -13,6 +13,7 @@ type GeocodeResponse struct {
 	Lat       float64 `json:"lat"`
 	Lng       float64 `json:"lng"`
 	Provider  string  `json:"provider"`
+	Precision string  `json:"precision"`
 	FromCache bool    `json:"from_cache"`
 }
 
@@ -25,6 +26,39 @@ type GeocodeCache struct {
 	Lat             float64   `json:"lat"`
 	Lng             float64   `json:"lng"`
 	Provider        string    `json:"provider"`
+	Precision       string    `json:"precision"`
 	CreatedAt       time.Time `json:"created_at"`
 	ExpiresAt       time.Time `json:"expires_at"`
 }
+
+// Geocode precision levels, from most to least specific. Providers report
+// precision in their own vocabulary; it is normalised to one of these values.
+const (
+	PrecisionRooftop  = "rooftop"
+	PrecisionStreet   = "street"
+	PrecisionDistrict = "district"
+	PrecisionCity     = "city"
+	PrecisionRegion   = "region"
+	PrecisionUnknown  = "unknown"
+)
+
+var precisionRanks = map[string]int{
+	PrecisionRegion:   1,
+	PrecisionCity:     2,
+	PrecisionDistrict: 3,
+	PrecisionStreet:   4,
+	PrecisionRooftop:  5,
+}
+
+// PrecisionRank orders precision levels so they can be compared.
+// Unknown precision ranks 0, below every known level.
+func PrecisionRank(precision string) int {
+	return precisionRanks[precision]
+}
+
+// IsCityLevelOrCoarser reports whether a known precision is no better than a
+// city centroid. Unknown precision (e.g. legacy cache rows) is not flagged.
+func IsCityLevelOrCoarser(precision string) bool {
+	rank := PrecisionRank(precision)
+	return rank > 0 && rank <= PrecisionRank(PrecisionCity)
+}
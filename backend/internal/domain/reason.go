@@ -0,0 +1,82 @@
+package domain
+
+// ReasonCode is a stable, machine-readable explanation attached to every
+// validation result. Codes are persisted on batch_items and grouped in analytics,
+// so existing values must never be renamed.
+type ReasonCode string
+
+const (
+	ReasonWithinAccurateThreshold  ReasonCode = "WITHIN_ACCURATE_THRESHOLD"
+	ReasonWithinFairThreshold      ReasonCode = "WITHIN_FAIR_THRESHOLD"
+	ReasonDistanceExceedsThreshold ReasonCode = "DISTANCE_EXCEEDS_THRESHOLD"
+	ReasonAddressEmpty             ReasonCode = "ADDRESS_EMPTY"
+	ReasonAddressNotFound          ReasonCode = "ADDRESS_NOT_FOUND"
+	ReasonProviderAllFailed        ReasonCode = "PROVIDER_ALL_FAILED"
+	ReasonFieldCoordMissing        ReasonCode = "FIELD_COORD_MISSING"
+	ReasonCityLevelMatchOnly       ReasonCode = "CITY_LEVEL_MATCH_ONLY"
+	ReasonLowPrecisionMatch        ReasonCode = "LOW_PRECISION_MATCH"
+	ReasonOutsideServiceArea       ReasonCode = "OUTSIDE_SERVICE_AREA"
+	ReasonAddressLowQuality        ReasonCode = "ADDRESS_LOW_QUALITY"
+)
+
+// ReasonExplanation is the human-readable text for a ReasonCode.
+type ReasonExplanation struct {
+	EN string `json:"en"`
+	ID string `json:"id"` // Bahasa Indonesia
+}
+
+var reasonExplanations = map[ReasonCode]ReasonExplanation{
+	ReasonWithinAccurateThreshold: {
+		EN: "Field coordinate is within 50 m of the geocoded address.",
+		ID: "Koordinat lapangan berada dalam radius 50 m dari alamat hasil geocode.",
+	},
+	ReasonWithinFairThreshold: {
+		EN: "Field coordinate is between 50 m and 100 m from the geocoded address.",
+		ID: "Koordinat lapangan berjarak 50 m hingga 100 m dari alamat hasil geocode.",
+	},
+	ReasonDistanceExceedsThreshold: {
+		EN: "Field coordinate is more than 100 m from the geocoded address.",
+		ID: "Koordinat lapangan berjarak lebih dari 100 m dari alamat hasil geocode.",
+	},
+	ReasonAddressEmpty: {
+		EN: "The system address is empty, so it could not be geocoded.",
+		ID: "Alamat sistem kosong sehingga tidak dapat di-geocode.",
+	},
+	ReasonAddressNotFound: {
+		EN: "No geocoding provider could find this address.",
+		ID: "Alamat tidak ditemukan oleh penyedia geocoding mana pun.",
+	},
+	ReasonProviderAllFailed: {
+		EN: "All configured geocoding providers failed or were rate limited.",
+		ID: "Semua penyedia geocoding yang dikonfigurasi gagal atau terkena batas kuota.",
+	},
+	ReasonFieldCoordMissing: {
+		EN: "No field coordinate was reported, so distance could not be measured.",
+		ID: "Koordinat lapangan tidak dilaporkan sehingga jarak tidak dapat diukur.",
+	},
+	ReasonCityLevelMatchOnly: {
+		EN: "The address only resolved to a city or region centroid; the distance is not meaningful.",
+		ID: "Alamat hanya ditemukan di titik tengah kota atau wilayah; jarak tidak dapat dijadikan acuan.",
+	},
+	ReasonLowPrecisionMatch: {
+		EN: "The address resolved below the configured geocode precision; the distance is not meaningful.",
+		ID: "Presisi hasil geocode di bawah batas yang dikonfigurasi; jarak tidak dapat dijadikan acuan.",
+	},
+	ReasonOutsideServiceArea: {
+		EN: "The geocoded address lies outside every configured service area.",
+		ID: "Alamat hasil geocode berada di luar seluruh area layanan yang dikonfigurasi.",
+	},
+	ReasonAddressLowQuality: {
+		EN: "The address is too incomplete or ambiguous; premium providers were skipped by policy and the free ones found nothing.",
+		ID: "Alamat terlalu tidak lengkap atau ambigu; penyedia berbayar dilewati sesuai kebijakan dan penyedia gratis tidak menemukannya.",
+	},
+}
+
+// Explain returns the English and Indonesian explanation for the code.
+// Unknown codes yield an empty explanation.
+func (c ReasonCode) Explain() ReasonExplanation {
+	return reasonExplanations[c]
+}
+
+// SuccessReasonCodes lists the codes excluded from failure analytics.
+var SuccessReasonCodes = []ReasonCode{ReasonWithinAccurateThreshold, ReasonWithinFairThreshold}
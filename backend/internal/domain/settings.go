@@ -9,6 +9,17 @@ type UserSettings struct {
 	GeoapifyKey      string    `db:"geoapify_key" json:"geoapify_key"`
 	PositionStackKey string    `db:"position_stack_key" json:"position_stack_key"`
 	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+
+	// Address quality policy: when enabled, addresses scoring below
+	// MinPremiumQualityScore never reach premium (paid) geocoders.
+	SkipPremiumLowQuality  bool `db:"skip_premium_low_quality" json:"skip_premium_low_quality"`
+	MinPremiumQualityScore int  `db:"min_premium_quality_score" json:"min_premium_quality_score"`
+
+	// Retention policy in days; 0 keeps data forever. Item detail is purged
+	// ItemRetentionDays after a batch's last update, keeping a summary; whole
+	// batches are deleted BatchRetentionDays after creation.
+	ItemRetentionDays  int `db:"item_retention_days" json:"item_retention_days"`
+	BatchRetentionDays int `db:"batch_retention_days" json:"batch_retention_days"`
 }
 
 // UpdateSettingsRequest is the payload for PUT /api/settings/maps
@@ -18,6 +29,18 @@ type UpdateSettingsRequest struct {
 	PositionStackKey string `json:"position_stack_key"`
 }
 
+// UpdateQualityPolicyRequest is the payload for PUT /api/settings/address-policy
+type UpdateQualityPolicyRequest struct {
+	SkipPremiumLowQuality  bool `json:"skip_premium_low_quality"`
+	MinPremiumQualityScore int  `json:"min_premium_quality_score" binding:"min=0,max=100"`
+}
+
+// UpdateRetentionPolicyRequest is the payload for PUT /api/settings/retention-policy
+type UpdateRetentionPolicyRequest struct {
+	ItemRetentionDays  int `json:"item_retention_days" binding:"min=0,max=3650"`
+	BatchRetentionDays int `json:"batch_retention_days" binding:"min=0,max=3650"`
+}
+
 // TestMapsKeyRequest is the payload for POST /api/settings/maps/test
 type TestMapsKeyRequest struct {
 	Provider string `json:"provider" binding:"required"` // 'google', 'geoapify', 'positionstack'
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrTeamNotFound is returned for a missing team or one the user may not see
+// or manage.
+var ErrTeamNotFound = errors.New("team not found or access denied")
+
+// Team groups users so a batch can be shared with all of them at once. Only
+// the owner manages the members; the owner is a member too.
+type Team struct {
+	ID        int64        `json:"id"`
+	Name      string       `json:"name"`
+	OwnerID   int64        `json:"owner_id"`
+	CreatedAt time.Time    `json:"created_at"`
+	Members   []TeamMember `json:"members,omitempty"`
+}
+
+// HasMember reports whether userID is among the loaded Members.
+func (t *Team) HasMember(userID int64) bool {
+	for _, m := range t.Members {
+		if m.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+type TeamMember struct {
+	UserID  int64     `json:"user_id"`
+	Name    string    `json:"name"`
+	Email   string    `json:"email"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+type TeamRepository interface {
+	// CreateTeam also adds the owner as the first member.
+	CreateTeam(ctx context.Context, team *Team) error
+	// GetTeam returns nil for a missing team; Members is filled in.
+	GetTeam(ctx context.Context, id int64) (*Team, error)
+	// ListTeamsByMember lists the teams userID belongs to, without members.
+	ListTeamsByMember(ctx context.Context, userID int64) ([]Team, error)
+	DeleteTeam(ctx context.Context, id int64) error
+	// AddTeamMember is a no-op for an existing member and returns
+	// ErrShareTargetNotFound for an unknown user.
+	AddTeamMember(ctx context.Context, teamID, userID int64) error
+	RemoveTeamMember(ctx context.Context, teamID, userID int64) error
+}
+
+type TeamService interface {
+	CreateTeam(ctx context.Context, userID int64, name string) (*Team, error)
+	ListTeams(ctx context.Context, userID int64) ([]Team, error)
+	// GetTeam is limited to members.
+	GetTeam(ctx context.Context, userID, teamID int64) (*Team, error)
+	DeleteTeam(ctx context.Context, userID, teamID int64) error
+	AddTeamMember(ctx context.Context, userID, teamID, memberID int64) error
+	// RemoveTeamMember is allowed to the owner and to a member leaving.
+	RemoveTeamMember(ctx context.Context, userID, teamID, memberID int64) error
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UploadMode decides what happens to an upload that fails validation.
+type UploadMode string
+
+const (
+	// UploadModeLenient stores the valid rows and records the issues on the batch.
+	UploadModeLenient UploadMode = "lenient"
+	// UploadModeStrict rejects the whole upload if any row has an issue.
+	UploadModeStrict UploadMode = "strict"
+)
+
+// Which upload an issue came from.
+const (
+	UploadSourceSystem = "system"
+	UploadSourceField  = "field"
+)
+
+// Upload issue codes.
+const (
+	IssueMissingConnote    = "missing_connote"
+	IssueMissingAddress    = "missing_address"
+	IssueDuplicateConnote  = "duplicate_connote"        // connote repeated within the upload
+	IssueConflictingReport = "conflicting_field_report" // repeated connote with other coordinates
+	IssueInvalidCoordinate = "invalid_coordinate"
+	IssueInvalidCourierID  = "invalid_courier_id"
+)
+
+// ErrUploadRejected is returned for a strict-mode upload with issues. Nothing
+// is stored; the accompanying report lists the issues.
+var ErrUploadRejected = errors.New("upload rejected: validation found issues")
+
+// UploadIssue is one problem found in an uploaded row. Row is the 1-based
+// position in the uploaded array.
+type UploadIssue struct {
+	Row     int    `json:"row"`
+	Connote string `json:"connote"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Skipped bool   `json:"skipped"` // the row was not stored
+}
+
+// UploadValidation summarises the checks run on an upload. Counts are per
+// issue code and exact; Issues is capped.
+type UploadValidation struct {
+	Mode            UploadMode     `json:"mode"`
+	TotalRows       int            `json:"total_rows"`
+	ValidRows       int            `json:"valid_rows"` // rows without any issue
+	SkippedRows     int            `json:"skipped_rows"`
+	Rejected        bool           `json:"rejected"`
+	Counts          map[string]int `json:"counts"`
+	Issues          []UploadIssue  `json:"issues"`
+	IssuesTruncated bool           `json:"issues_truncated"`
+}
+
+// HasIssues reports whether any row had an issue.
+func (v *UploadValidation) HasIssues() bool {
+	return v.ValidRows < v.TotalRows
+}
+
+// BatchUploadIssue is an issue kept on a batch after a lenient upload. Each
+// upload replaces the issues of the previous upload from the same source.
+type BatchUploadIssue struct {
+	BatchID   uuid.UUID `json:"batch_id"`
+	Source    string    `json:"source"` // UploadSource*
+	CreatedAt time.Time `json:"created_at"`
+	UploadIssue
+}
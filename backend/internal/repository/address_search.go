@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+// The search expressions match the indexes of migration 000026. "<%" is the
+// pg_trgm word-similarity operator: the query is close to some run of words
+// in the text, which suits a short query against a long address. The 'simple'
+// full-text configuration adds exact-word matches the trigrams miss, such as
+// very short house numbers.
+const (
+	itemTextVector  = `to_tsvector('simple', COALESCE(bi.system_address, '') || ' ' || COALESCE(bi.recipient_name, ''))`
+	cacheTextVector = `to_tsvector('simple', gc.original_address)`
+)
+
+type addressSearchRepository struct {
+	db *sql.DB
+}
+
+func NewAddressSearchRepository(db *sql.DB) domain.AddressSearchRepository {
+	return &addressSearchRepository{db: db}
+}
+
+func (r *addressSearchRepository) SearchBatchItems(ctx context.Context, userID int64, query string, limit int) ([]domain.AddressMatch, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT bi.id, b.id, b.name, b.created_at, bi.connote,
+		       COALESCE(bi.recipient_name, ''), COALESCE(bi.system_address, ''), COALESCE(bi.accuracy_level, ''),
+		       GREATEST(word_similarity($1, COALESCE(bi.system_address, '')),
+		                word_similarity($1, COALESCE(bi.recipient_name, ''))) AS score
+		FROM batch_items bi
+		JOIN batches b ON b.id = bi.batch_id
+		WHERE ($1 <% bi.system_address OR $1 <% bi.recipient_name
+		       OR `+itemTextVector+` @@ plainto_tsquery('simple', $1))
+		  AND `+fmt.Sprintf(accessibleBatchCond, "$2")+`
+		ORDER BY score DESC, b.created_at DESC, bi.id
+		LIMIT $3`, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []domain.AddressMatch
+	for rows.Next() {
+		var m domain.AddressMatch
+		if err := rows.Scan(&m.ItemID, &m.BatchID, &m.BatchName, &m.BatchCreatedAt, &m.Connote,
+			&m.RecipientName, &m.SystemAddress, &m.AccuracyLevel, &m.Score); err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+func (r *addressSearchRepository) SearchGeocodeCache(ctx context.Context, userID int64, allUsers bool, query string, limit int) ([]domain.CachedAddressMatch, error) {
+	// The geocoder caches the address text it was given, so an entry belongs
+	// to the users with an item of exactly that address.
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT gc.original_address, COALESCE(gc.city, ''), COALESCE(gc.province, ''),
+		       gc.lat, gc.lng, gc.provider, gc.created_at,
+		       word_similarity($1, gc.original_address) AS score
+		FROM geocode_cache gc
+		WHERE ($1 <% gc.original_address OR `+cacheTextVector+` @@ plainto_tsquery('simple', $1))
+		  AND ($3 OR EXISTS (
+		      SELECT 1 FROM batch_items bi JOIN batches b ON b.id = bi.batch_id
+		      WHERE bi.system_address = gc.original_address AND `+fmt.Sprintf(accessibleBatchCond, "$2")+`))
+		ORDER BY score DESC, gc.id
+		LIMIT $4`, query, userID, allUsers, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var matches []domain.CachedAddressMatch
+	for rows.Next() {
+		var m domain.CachedAddressMatch
+		if err := rows.Scan(&m.OriginalAddress, &m.City, &m.Province, &m.Lat, &m.Lng,
+			&m.Provider, &m.CreatedAt, &m.Score); err != nil {
+			return nil, err
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
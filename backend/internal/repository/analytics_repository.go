@@ -6,6 +6,7 @@ import (
 	"geoaccuracy-backend/internal/domain"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 // analyticsRepository implements domain.AnalyticsRepository
@@ -18,18 +19,35 @@ func NewAnalyticsRepository(db *sqlx.DB) domain.AnalyticsRepository {
 	return &analyticsRepository{db: db}
 }
 
-// SaveCourierPerformance persists a new courier performance record.
+// batchEventPredicate matches the courier_performance rows written by batch
+// processing, whose batch_id is the batch UUID. It must match the predicate
+// of idx_cp_batch_order (migration 000029) for ON CONFLICT to use the index;
+// prefix it with a table alias to use it in a join.
+const batchEventPredicate = `batch_id ~ '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$'`
+
+// SaveCourierPerformance persists a courier performance record. A batch
+// item processed again replaces its earlier record, so reprocessing does not
+// count a delivery twice; webhook and ERP events are always added.
 func (r *analyticsRepository) SaveCourierPerformance(ctx context.Context, cp *domain.CourierPerformance) error {
 	query := `
 		INSERT INTO courier_performance (
 			user_id, batch_id, courier_id, order_id, 
 			reported_lat, reported_lng, actual_lat, actual_lng, 
-			distance_variance_meters, accuracy_status, sla_status, event_timestamp
+			distance_variance_meters, accuracy_status, sla_status, event_timestamp, accuracy_override
 		) VALUES (
 			:user_id, :batch_id, :courier_id, :order_id,
 			:reported_lat, :reported_lng, :actual_lat, :actual_lng,
-			:distance_variance_meters, :accuracy_status, :sla_status, :event_timestamp
-		) RETURNING id, created_at
+			:distance_variance_meters, :accuracy_status, :sla_status, :event_timestamp,
+			NULLIF(:accuracy_override, '')
+		)
+		ON CONFLICT (batch_id, order_id) WHERE ` + batchEventPredicate + ` DO UPDATE
+		SET user_id = EXCLUDED.user_id, courier_id = EXCLUDED.courier_id,
+			reported_lat = EXCLUDED.reported_lat, reported_lng = EXCLUDED.reported_lng,
+			actual_lat = EXCLUDED.actual_lat, actual_lng = EXCLUDED.actual_lng,
+			distance_variance_meters = EXCLUDED.distance_variance_meters,
+			accuracy_status = EXCLUDED.accuracy_status, sla_status = EXCLUDED.sla_status,
+			event_timestamp = EXCLUDED.event_timestamp, accuracy_override = EXCLUDED.accuracy_override
+		RETURNING id, created_at
 	`
 	rows, err := r.db.NamedQueryContext(ctx, query, cp)
 	if err != nil {
@@ -46,7 +64,9 @@ func (r *analyticsRepository) SaveCourierPerformance(ctx context.Context, cp *do
 }
 
 // GetCourierLeaderboard fetches courier metrics aggregated by courier_id.
-// Ordered by total accurate deliveries.
+// Ordered by total accurate deliveries. Unverifiable deliveries are counted
+// but left out of the accuracy rate, since they cannot be held against anyone.
+// A reviewer's override takes the place of the computed accuracy.
 func (r *analyticsRepository) GetCourierLeaderboard(ctx context.Context, userID int64, limit int) ([]domain.CourierAccuracyAgg, error) {
 	if limit <= 0 {
 		limit = 10
@@ -55,15 +75,20 @@ func (r *analyticsRepository) GetCourierLeaderboard(ctx context.Context, userID
 		SELECT 
 			courier_id,
 			COUNT(*) as total_deliveries,
-			COUNT(*) FILTER (WHERE accuracy_status = 'accurate') as accurate_count,
-			COUNT(*) FILTER (WHERE accuracy_status = 'fairly_accurate') as fairly_count,
-			COUNT(*) FILTER (WHERE accuracy_status = 'inaccurate') as inaccurate_count,
-			COUNT(*) FILTER (WHERE accuracy_status = 'error') as error_count,
-			ROUND(
-				(COUNT(*) FILTER (WHERE accuracy_status = 'accurate')::numeric / COUNT(*)) * 100, 
-			2) as accuracy_rate
-		FROM courier_performance
-		WHERE user_id = $1
+			COUNT(*) FILTER (WHERE status = 'accurate') as accurate_count,
+			COUNT(*) FILTER (WHERE status = 'fairly_accurate') as fairly_count,
+			COUNT(*) FILTER (WHERE status = 'inaccurate') as inaccurate_count,
+			COUNT(*) FILTER (WHERE status = 'error') as error_count,
+			COUNT(*) FILTER (WHERE status = 'unverifiable') as unverifiable_count,
+			COALESCE(ROUND(
+				(COUNT(*) FILTER (WHERE status = 'accurate')::numeric /
+				 NULLIF(COUNT(*) FILTER (WHERE status <> 'unverifiable'), 0)) * 100, 
+			2), 0) as accuracy_rate
+		FROM (
+			SELECT courier_id, COALESCE(accuracy_override, accuracy_status) AS status
+			FROM courier_performance
+			WHERE user_id = $1
+		) cp
 		GROUP BY courier_id
 		ORDER BY accurate_count DESC, accuracy_rate DESC
 		LIMIT $2
@@ -110,3 +135,41 @@ func (r *analyticsRepository) GetSLATrends(ctx context.Context, userID int64, da
 
 	return trends, nil
 }
+
+// GetFailureReasons groups the user's batch items by failure reason code over
+// the last N days. Codes for successful matches are excluded, as are items a
+// reviewer accepted or corrected.
+func (r *analyticsRepository) GetFailureReasons(ctx context.Context, userID int64, days int) ([]domain.FailureReasonAgg, error) {
+	if days <= 0 {
+		days = 30
+	}
+	successCodes := make([]string, 0, len(domain.SuccessReasonCodes))
+	for _, code := range domain.SuccessReasonCodes {
+		successCodes = append(successCodes, string(code))
+	}
+
+	query := `
+		SELECT 
+			bi.reason_code,
+			COUNT(*) as count
+		FROM batch_items bi
+		JOIN batches b ON b.id = bi.batch_id
+		WHERE b.user_id = $1
+		  AND bi.reason_code <> ''
+		  AND bi.reason_code <> ALL($2)
+		  AND bi.review_status NOT IN ('accepted', 'address_corrected')
+		  AND bi.updated_at >= CURRENT_DATE - ($3 || ' days')::INTERVAL
+		GROUP BY bi.reason_code
+		ORDER BY count DESC
+	`
+	var reasons []domain.FailureReasonAgg
+	if err := r.db.SelectContext(ctx, &reasons, query, userID, pq.Array(successCodes), days); err != nil {
+		return nil, fmt.Errorf("failed to fetch failure reasons: %w", err)
+	}
+
+	for i := range reasons {
+		reasons[i].Explanation = reasons[i].ReasonCode.Explain()
+	}
+
+	return reasons, nil
+}
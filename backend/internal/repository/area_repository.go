@@ -16,6 +16,7 @@ type AreaRepository interface {
 	ListAll(ctx context.Context) ([]domain.Area, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 	CheckPointInArea(ctx context.Context, pointLat float64, pointLng float64) ([]domain.Area, error)
+	IsOutsideAllAreas(ctx context.Context, pointLat float64, pointLng float64) (bool, error)
 }
 
 type postgresAreaRepository struct {
@@ -162,3 +163,20 @@ func (r *postgresAreaRepository) CheckPointInArea(ctx context.Context, pointLat
 
 	return areas, nil
 }
+
+// IsOutsideAllAreas reports whether at least one area is configured and the
+// point intersects none of them. With no areas configured nothing is "outside".
+func (r *postgresAreaRepository) IsOutsideAllAreas(ctx context.Context, pointLat float64, pointLng float64) (bool, error) {
+	query := `
+		SELECT EXISTS (SELECT 1 FROM areas)
+		   AND NOT EXISTS (
+				SELECT 1 FROM areas
+				WHERE ST_Intersects(geom, ST_SetSRID(ST_MakePoint($1, $2), 4326))
+		   )
+	`
+	var outside bool
+	if err := r.db.QueryRowContext(ctx, query, pointLng, pointLat).Scan(&outside); err != nil {
+		return false, fmt.Errorf("failed to execute service area query: %w", err)
+	}
+	return outside, nil
+}
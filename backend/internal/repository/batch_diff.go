@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+func (r *batchRepository) DiffBatchItems(ctx context.Context, baseID, targetID uuid.UUID, fn func(base, target *domain.BatchDiffSide) error) error {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT COALESCE(b.connote, t.connote),
+		       b.id, COALESCE(b.accuracy_level, ''), COALESCE(b.geocode_status, ''), COALESCE(b.reason_code, ''),
+		       b.distance_km, b.system_lat, b.system_lng,
+		       t.id, COALESCE(t.accuracy_level, ''), COALESCE(t.geocode_status, ''), COALESCE(t.reason_code, ''),
+		       t.distance_km, t.system_lat, t.system_lng
+		FROM (SELECT * FROM batch_items WHERE batch_id = $1) b
+		FULL OUTER JOIN (SELECT * FROM batch_items WHERE batch_id = $2) t ON t.connote = b.connote
+		ORDER BY 1`, baseID, targetID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var connote string
+		var baseItemID, targetItemID uuid.NullUUID
+		var base, target domain.BatchDiffSide
+		if err := rows.Scan(&connote,
+			&baseItemID, &base.AccuracyLevel, &base.GeocodeStatus, &base.ReasonCode,
+			&base.DistanceKm, &base.SystemLat, &base.SystemLng,
+			&targetItemID, &target.AccuracyLevel, &target.GeocodeStatus, &target.ReasonCode,
+			&target.DistanceKm, &target.SystemLat, &target.SystemLng,
+		); err != nil {
+			return err
+		}
+		if err := fn(diffSide(connote, baseItemID, &base), diffSide(connote, targetItemID, &target)); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// diffSide returns nil when the outer join found no item for this side.
+func diffSide(connote string, id uuid.NullUUID, side *domain.BatchDiffSide) *domain.BatchDiffSide {
+	if !id.Valid {
+		return nil
+	}
+	side.ItemID = id.UUID
+	side.Connote = connote
+	return side
+}
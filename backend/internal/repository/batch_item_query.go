@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+// batchItemSort describes one sortable column: the SQL expression, the type
+// its cursor value is cast to, and how to read that value from an item.
+type batchItemSort struct {
+	expr  string
+	cast  string
+	value func(*domain.BatchItem) string
+}
+
+var batchItemSorts = map[string]batchItemSort{
+	domain.BatchItemSortCreatedAt: {"created_at", "timestamptz", func(i *domain.BatchItem) string { return i.CreatedAt.Format(time.RFC3339Nano) }},
+	domain.BatchItemSortUpdatedAt: {"updated_at", "timestamptz", func(i *domain.BatchItem) string { return i.UpdatedAt.Format(time.RFC3339Nano) }},
+	domain.BatchItemSortConnote:   {"connote", "text", func(i *domain.BatchItem) string { return i.Connote }},
+	// Items without a distance sort after every measured one.
+	domain.BatchItemSortDistance: {"COALESCE(distance_km, 'Infinity'::float8)", "float8", func(i *domain.BatchItem) string {
+		if i.DistanceKm == nil {
+			return "Infinity"
+		}
+		return strconv.FormatFloat(*i.DistanceKm, 'g', -1, 64)
+	}},
+}
+
+// batchItemCursor is the opaque position handed out as NextCursor.
+type batchItemCursor struct {
+	Sort  string    `json:"s"`
+	Desc  bool      `json:"d,omitempty"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+func encodeBatchItemCursor(c batchItemCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeBatchItemCursor rejects a cursor issued for another sort key or
+// direction: its position means nothing in the other order.
+func decodeBatchItemCursor(raw, sort string, desc bool) (*batchItemCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+	var c batchItemCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.Sort != sort || c.Desc != desc {
+		return nil, domain.ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// queryBuilder collects WHERE conditions and their positional arguments.
+type queryBuilder struct {
+	conds []string
+	args  []interface{}
+}
+
+func (b *queryBuilder) arg(v interface{}) string {
+	b.args = append(b.args, v)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+// likeContains is an ILIKE pattern matching s literally anywhere in the
+// value; use it with ESCAPE '\'.
+func likeContains(s string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s) + "%"
+}
+
+func (b *queryBuilder) where(format string, args ...interface{}) {
+	placeholders := make([]interface{}, len(args))
+	for i, a := range args {
+		placeholders[i] = b.arg(a)
+	}
+	b.conds = append(b.conds, fmt.Sprintf(format, placeholders...))
+}
+
+func (r *batchRepository) QueryBatchItems(ctx context.Context, batchID uuid.UUID, q domain.BatchItemQuery) (*domain.BatchItemPage, error) {
+	sortKey := q.Sort
+	if sortKey == "" {
+		sortKey = domain.BatchItemSortCreatedAt
+	}
+	sort, ok := batchItemSorts[sortKey]
+	if !ok {
+		return nil, fmt.Errorf("unknown sort %q", q.Sort)
+	}
+
+	b := &queryBuilder{}
+	b.where("batch_id = %s", batchID)
+	if len(q.AccuracyLevels) > 0 {
+		b.where("COALESCE(accuracy_level, '') = ANY(%s)", pq.Array(q.AccuracyLevels))
+	}
+	if len(q.GeocodeStatuses) > 0 {
+		b.where("COALESCE(geocode_status, '') = ANY(%s)", pq.Array(q.GeocodeStatuses))
+	}
+	if len(q.CourierIDs) > 0 {
+		b.where("courier_id = ANY(%s)", pq.Array(q.CourierIDs))
+	}
+	if q.MinDistanceKm != nil {
+		b.where("distance_km >= %s", *q.MinDistanceKm)
+	}
+	if q.MaxDistanceKm != nil {
+		b.where("distance_km <= %s", *q.MaxDistanceKm)
+	}
+	if q.Connote != "" {
+		b.where(`connote ILIKE %s ESCAPE '\'`, likeContains(q.Connote))
+	}
+	if bb := q.BBox; bb != nil {
+		minLat, maxLat, minLng, maxLng := b.arg(bb.MinLat), b.arg(bb.MaxLat), b.arg(bb.MinLng), b.arg(bb.MaxLng)
+		inBox := func(prefix string) string {
+			return fmt.Sprintf("(%[1]s_lat BETWEEN %[2]s AND %[3]s AND %[1]s_lng BETWEEN %[4]s AND %[5]s)",
+				prefix, minLat, maxLat, minLng, maxLng)
+		}
+		switch bb.Match {
+		case domain.BBoxMatchField:
+			b.conds = append(b.conds, inBox("field"))
+		case domain.BBoxMatchSystem:
+			b.conds = append(b.conds, inBox("system"))
+		default:
+			b.conds = append(b.conds, "("+inBox("field")+" OR "+inBox("system")+")")
+		}
+	}
+
+	dir, cmp := "ASC", ">"
+	if q.Desc {
+		dir, cmp = "DESC", "<"
+	}
+	if q.Cursor != "" {
+		cur, err := decodeBatchItemCursor(q.Cursor, sortKey, q.Desc)
+		if err != nil {
+			return nil, err
+		}
+		b.conds = append(b.conds, fmt.Sprintf("(%s, id) %s (%s::%s, %s)",
+			sort.expr, cmp, b.arg(cur.Value), sort.cast, b.arg(cur.ID)))
+	}
+
+	query := `SELECT ` + batchItemColumns + ` FROM batch_items WHERE ` + strings.Join(b.conds, " AND ") +
+		fmt.Sprintf(" ORDER BY %s %s, id %s", sort.expr, dir, dir)
+	if q.Limit > 0 {
+		// One extra row tells whether another page exists.
+		query += " LIMIT " + b.arg(q.Limit+1)
+	}
+
+	items, err := r.queryBatchItems(ctx, query, b.args...)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.BatchItemPage{Items: items}
+	if page.Items == nil {
+		page.Items = []domain.BatchItem{}
+	}
+	if q.Limit > 0 && len(items) > q.Limit {
+		page.Items = items[:q.Limit]
+		last := &page.Items[q.Limit-1]
+		page.HasMore = true
+		page.NextCursor = encodeBatchItemCursor(batchItemCursor{Sort: sortKey, Desc: q.Desc, Value: sort.value(last), ID: last.ID})
+	}
+	return page, nil
+}
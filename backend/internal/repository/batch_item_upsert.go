@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+// batchItemStagingColumns are COPY'd into batch_items_staging, in this order.
+var batchItemStagingColumns = []string{
+	"id", "batch_id", "connote", "recipient_name", "system_address", "courier_id",
+	"system_lat", "system_lng", "field_lat", "field_lng",
+	"distance_km", "accuracy_level", "error", "geocode_status", "reason_code",
+	"address_quality_score", "address_quality_flags", "provider", "attempt_count", "from_cache",
+	"cache_status", "geocoded_city", "geocoded_province", "processed_at",
+}
+
+// The staging table lives for one transaction. Its primary key lets the
+// ON CONFLICT branch look up the staged flags (see below).
+const createBatchItemStaging = `
+	CREATE TEMP TABLE batch_items_staging (
+		id UUID NOT NULL,
+		batch_id UUID NOT NULL,
+		connote VARCHAR(100) NOT NULL,
+		recipient_name VARCHAR(255),
+		system_address TEXT,
+		courier_id VARCHAR(255),
+		system_lat DOUBLE PRECISION,
+		system_lng DOUBLE PRECISION,
+		field_lat DOUBLE PRECISION,
+		field_lng DOUBLE PRECISION,
+		distance_km DOUBLE PRECISION,
+		accuracy_level VARCHAR(50),
+		error TEXT,
+		geocode_status VARCHAR(50),
+		reason_code VARCHAR(64),
+		address_quality_score INT,
+		address_quality_flags TEXT[],
+		provider VARCHAR(50),
+		attempt_count INT,
+		from_cache BOOLEAN,
+		cache_status VARCHAR(10),
+		geocoded_city TEXT,
+		geocoded_province TEXT,
+		processed_at TIMESTAMPTZ,
+		PRIMARY KEY (batch_id, connote)
+	) ON COMMIT DROP`
+
+// mergeBatchItemStaging applies the staged rows in one statement. Empty
+// strings and NULLs leave the stored value alone, so system and field uploads
+// fill in different columns of the same item. EXCLUDED cannot tell a staged
+// NULL flag array from an empty one once it is defaulted to '{}', so the flags
+// are read from the staging row itself.
+const mergeBatchItemStaging = `
+	INSERT INTO batch_items AS bi (
+		id, batch_id, connote, recipient_name, system_address, courier_id,
+		system_lat, system_lng, field_lat, field_lng,
+		distance_km, accuracy_level, error, geocode_status, reason_code,
+		address_quality_score, address_quality_flags, provider, attempt_count, from_cache,
+		cache_status, geocoded_city, geocoded_province, processed_at
+	)
+	SELECT id, batch_id, connote, recipient_name, system_address, courier_id,
+	       system_lat, system_lng, field_lat, field_lng,
+	       distance_km, accuracy_level, error, geocode_status, reason_code,
+	       address_quality_score, COALESCE(address_quality_flags, '{}'), provider, attempt_count, from_cache,
+	       COALESCE(cache_status, ''), COALESCE(geocoded_city, ''), COALESCE(geocoded_province, ''), processed_at
+	FROM batch_items_staging
+	ON CONFLICT (batch_id, connote) DO UPDATE
+	SET recipient_name = COALESCE(NULLIF(EXCLUDED.recipient_name, ''), bi.recipient_name),
+		system_address = COALESCE(NULLIF(EXCLUDED.system_address, ''), bi.system_address),
+		courier_id     = COALESCE(NULLIF(EXCLUDED.courier_id, ''), bi.courier_id),
+		system_lat     = COALESCE(EXCLUDED.system_lat, bi.system_lat),
+		system_lng     = COALESCE(EXCLUDED.system_lng, bi.system_lng),
+		field_lat      = COALESCE(EXCLUDED.field_lat, bi.field_lat),
+		field_lng      = COALESCE(EXCLUDED.field_lng, bi.field_lng),
+		distance_km    = COALESCE(EXCLUDED.distance_km, bi.distance_km),
+		accuracy_level = COALESCE(NULLIF(EXCLUDED.accuracy_level, ''), bi.accuracy_level),
+		error          = COALESCE(NULLIF(EXCLUDED.error, ''), bi.error),
+		geocode_status = COALESCE(NULLIF(EXCLUDED.geocode_status, ''), bi.geocode_status),
+		reason_code    = COALESCE(NULLIF(EXCLUDED.reason_code, ''), bi.reason_code),
+		address_quality_score = COALESCE(EXCLUDED.address_quality_score, bi.address_quality_score),
+		address_quality_flags = COALESCE(
+			(SELECT s.address_quality_flags FROM batch_items_staging s
+			 WHERE s.batch_id = EXCLUDED.batch_id AND s.connote = EXCLUDED.connote),
+			bi.address_quality_flags),
+		provider       = COALESCE(NULLIF(EXCLUDED.provider, ''), bi.provider),
+		attempt_count  = CASE WHEN EXCLUDED.attempt_count > 0 THEN EXCLUDED.attempt_count ELSE bi.attempt_count END,
+		from_cache     = COALESCE(EXCLUDED.from_cache, bi.from_cache),
+		cache_status   = COALESCE(NULLIF(EXCLUDED.cache_status, ''), bi.cache_status),
+		geocoded_city  = COALESCE(NULLIF(EXCLUDED.geocoded_city, ''), bi.geocoded_city),
+		geocoded_province = COALESCE(NULLIF(EXCLUDED.geocoded_province, ''), bi.geocoded_province),
+		processed_at   = COALESCE(EXCLUDED.processed_at, bi.processed_at),
+		updated_at     = CURRENT_TIMESTAMP`
+
+// UpsertBatchItems COPYs items into a staging table and merges them into
+// batch_items with a single INSERT ... ON CONFLICT, instead of two round
+// trips per item.
+func (r *batchRepository) UpsertBatchItems(ctx context.Context, items []domain.BatchItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	items = dedupeBatchItems(items)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, createBatchItemStaging); err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("batch_items_staging", batchItemStagingColumns...))
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if item.ID == uuid.Nil {
+			item.ID = uuid.New()
+		}
+		if _, err := stmt.ExecContext(ctx,
+			item.ID, item.BatchID, item.Connote, item.RecipientName, item.SystemAddress, item.CourierID,
+			item.SystemLat, item.SystemLng, item.FieldLat, item.FieldLng,
+			item.DistanceKm, item.AccuracyLevel, item.Error, item.GeocodeStatus, item.ReasonCode,
+			item.AddressQualityScore, nullableStringArray(item.AddressQualityFlags),
+			item.Provider, item.AttemptCount, item.FromCache,
+			item.CacheStatus, item.GeocodedCity, item.GeocodedProvince, item.ProcessedAt,
+		); err != nil {
+			stmt.Close()
+			return err
+		}
+	}
+	// The argument-less Exec flushes the COPY.
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return err
+	}
+	if err := stmt.Close(); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, mergeBatchItemStaging); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// dedupeBatchItems folds repeated (batch, connote) pairs into one item using
+// the same rules as the merge: a later non-empty value wins. ON CONFLICT
+// cannot touch the same row twice in one statement, while the per-row upsert
+// this replaced simply applied duplicates in order.
+func dedupeBatchItems(items []domain.BatchItem) []domain.BatchItem {
+	type key struct {
+		batchID uuid.UUID
+		connote string
+	}
+	index := make(map[key]int, len(items))
+	out := make([]domain.BatchItem, 0, len(items))
+	for _, item := range items {
+		k := key{item.BatchID, item.Connote}
+		i, seen := index[k]
+		if !seen {
+			index[k] = len(out)
+			out = append(out, item)
+			continue
+		}
+		mergeBatchItem(&out[i], item)
+	}
+	return out
+}
+
+func mergeBatchItem(dst *domain.BatchItem, src domain.BatchItem) {
+	mergeString := func(d *string, s string) {
+		if s != "" {
+			*d = s
+		}
+	}
+	mergeFloat := func(d **float64, s *float64) {
+		if s != nil {
+			*d = s
+		}
+	}
+	if dst.ID == uuid.Nil {
+		dst.ID = src.ID
+	}
+	mergeString(&dst.RecipientName, src.RecipientName)
+	mergeString(&dst.SystemAddress, src.SystemAddress)
+	mergeString(&dst.CourierID, src.CourierID)
+	mergeFloat(&dst.SystemLat, src.SystemLat)
+	mergeFloat(&dst.SystemLng, src.SystemLng)
+	mergeFloat(&dst.FieldLat, src.FieldLat)
+	mergeFloat(&dst.FieldLng, src.FieldLng)
+	mergeFloat(&dst.DistanceKm, src.DistanceKm)
+	mergeString(&dst.AccuracyLevel, src.AccuracyLevel)
+	mergeString(&dst.Error, src.Error)
+	mergeString(&dst.GeocodeStatus, src.GeocodeStatus)
+	if src.ReasonCode != "" {
+		dst.ReasonCode = src.ReasonCode
+	}
+	if src.AddressQualityScore != nil {
+		dst.AddressQualityScore = src.AddressQualityScore
+	}
+	if src.AddressQualityFlags != nil {
+		dst.AddressQualityFlags = src.AddressQualityFlags
+	}
+	mergeString(&dst.Provider, src.Provider)
+	if src.AttemptCount > 0 {
+		dst.AttemptCount = src.AttemptCount
+	}
+	if src.FromCache != nil {
+		dst.FromCache = src.FromCache
+	}
+	mergeString(&dst.CacheStatus, src.CacheStatus)
+	mergeString(&dst.GeocodedCity, src.GeocodedCity)
+	mergeString(&dst.GeocodedProvince, src.GeocodedProvince)
+	if src.ProcessedAt != nil {
+		dst.ProcessedAt = src.ProcessedAt
+	}
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+type batchJobRepository struct {
+	db *sql.DB
+}
+
+func NewBatchJobRepository(db *sql.DB) domain.BatchJobRepository {
+	return &batchJobRepository{db: db}
+}
+
+const batchJobColumns = `id, batch_id, user_id, status, attempts, processed, total, last_error, bypass_cache,
+	locked_by, locked_until, run_after, created_at, updated_at, completed_at`
+
+func scanBatchJob(row interface{ Scan(...interface{}) error }) (*domain.BatchJob, error) {
+	var j domain.BatchJob
+	err := row.Scan(&j.ID, &j.BatchID, &j.UserID, &j.Status, &j.Attempts, &j.Processed, &j.Total, &j.LastError, &j.BypassCache,
+		&j.LockedBy, &j.LockedUntil, &j.RunAfter, &j.CreatedAt, &j.UpdatedAt, &j.CompletedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &j, nil
+}
+
+func (r *batchJobRepository) Enqueue(ctx context.Context, batchID uuid.UUID, userID int64, bypassCache bool) (*domain.BatchJob, error) {
+	query := `
+		INSERT INTO batch_jobs (batch_id, user_id, bypass_cache)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (batch_id) WHERE status IN ('queued', 'running', 'paused') DO NOTHING
+		RETURNING ` + batchJobColumns
+	job, err := scanBatchJob(r.db.QueryRowContext(ctx, query, batchID, userID, bypassCache))
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, domain.ErrBatchJobActive
+	}
+	return job, nil
+}
+
+func (r *batchJobRepository) Claim(ctx context.Context, workerID string, lease time.Duration) (*domain.BatchJob, error) {
+	query := `
+		UPDATE batch_jobs
+		SET status = 'running',
+		    attempts = attempts + 1,
+		    locked_by = $1,
+		    locked_until = now() + $2::interval,
+		    updated_at = now()
+		WHERE id = (
+			SELECT id FROM batch_jobs
+			WHERE (status = 'queued' AND run_after <= now())
+			   OR (status = 'running' AND locked_until < now())
+			ORDER BY run_after, id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING ` + batchJobColumns
+	return scanBatchJob(r.db.QueryRowContext(ctx, query, workerID, pgInterval(lease)))
+}
+
+func (r *batchJobRepository) Checkpoint(ctx context.Context, jobID int64, workerID string, processed, total int, lease time.Duration) error {
+	query := `
+		UPDATE batch_jobs
+		SET processed = $1, total = $2, locked_until = now() + $3::interval, updated_at = now()
+		WHERE id = $4 AND locked_by = $5
+		RETURNING status
+	`
+	var status domain.BatchJobStatus
+	err := r.db.QueryRowContext(ctx, query, processed, total, pgInterval(lease), jobID, workerID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return domain.ErrBatchJobLeaseLost
+	}
+	if err != nil {
+		return err
+	}
+	if status != domain.BatchJobRunning {
+		return domain.ErrBatchJobStopped
+	}
+	return nil
+}
+
+// leaseResult maps an update that matched no row to ErrBatchJobLeaseLost.
+func leaseResult(res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrBatchJobLeaseLost
+	}
+	return nil
+}
+
+func (r *batchJobRepository) ExtendLease(ctx context.Context, jobID int64, workerID string, lease time.Duration) error {
+	query := `
+		UPDATE batch_jobs
+		SET locked_until = now() + $1::interval, updated_at = now()
+		WHERE id = $2 AND status = 'running' AND locked_by = $3
+	`
+	res, err := r.db.ExecContext(ctx, query, pgInterval(lease), jobID, workerID)
+	return leaseResult(res, err)
+}
+
+func (r *batchJobRepository) Complete(ctx context.Context, jobID int64, workerID string) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE batch_jobs
+		SET status = 'completed', locked_by = NULL, locked_until = NULL, last_error = '',
+		    completed_at = now(), updated_at = now()
+		WHERE id = $1 AND status = 'running' AND locked_by = $2
+	`, jobID, workerID)
+	return leaseResult(res, err)
+}
+
+func (r *batchJobRepository) Retry(ctx context.Context, jobID int64, workerID, lastError string, runAfter time.Time) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE batch_jobs
+		SET status = 'queued', locked_by = NULL, locked_until = NULL, last_error = $1,
+		    run_after = $2, updated_at = now()
+		WHERE id = $3 AND status = 'running' AND locked_by = $4
+	`, lastError, runAfter, jobID, workerID)
+	return leaseResult(res, err)
+}
+
+func (r *batchJobRepository) Fail(ctx context.Context, jobID int64, workerID, lastError string) error {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE batch_jobs
+		SET status = 'failed', locked_by = NULL, locked_until = NULL, last_error = $1,
+		    completed_at = now(), updated_at = now()
+		WHERE id = $2 AND status = 'running' AND locked_by = $3
+	`, lastError, jobID, workerID)
+	return leaseResult(res, err)
+}
+
+func (r *batchJobRepository) Release(ctx context.Context, jobID int64, workerID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE batch_jobs
+		SET status = CASE WHEN status = 'running' THEN 'queued' ELSE status END,
+		    attempts = CASE WHEN status = 'running' THEN GREATEST(attempts - 1, 0) ELSE attempts END,
+		    locked_by = NULL, locked_until = NULL, run_after = now(), updated_at = now()
+		WHERE id = $1 AND locked_by = $2
+	`, jobID, workerID)
+	return err
+}
+
+func (r *batchJobRepository) Transition(ctx context.Context, batchID uuid.UUID, from []domain.BatchJobStatus, to domain.BatchJobStatus) (*domain.BatchJob, error) {
+	fromStrings := make([]string, len(from))
+	for i, s := range from {
+		fromStrings[i] = string(s)
+	}
+	// A job going back to the queue must first be released by the worker that
+	// is stopping it, otherwise two workers could run it at once.
+	query := `
+		UPDATE batch_jobs
+		SET status = $1::text,
+		    run_after = CASE WHEN $1::text = 'queued' THEN now() ELSE run_after END,
+		    completed_at = CASE WHEN $1::text = 'cancelled' THEN now() ELSE completed_at END,
+		    updated_at = now()
+		WHERE batch_id = $2 AND status = ANY($3)
+		  AND ($1::text <> 'queued' OR locked_by IS NULL OR locked_until < now())
+		RETURNING ` + batchJobColumns
+	return scanBatchJob(r.db.QueryRowContext(ctx, query, to, batchID, pq.Array(fromStrings)))
+}
+
+func (r *batchJobRepository) GetLatestByBatchID(ctx context.Context, batchID uuid.UUID) (*domain.BatchJob, error) {
+	query := `SELECT ` + batchJobColumns + ` FROM batch_jobs WHERE batch_id = $1 ORDER BY created_at DESC, id DESC LIMIT 1`
+	return scanBatchJob(r.db.QueryRowContext(ctx, query, batchID))
+}
+
+// pgInterval formats d for a Postgres ::interval cast.
+func pgInterval(d time.Duration) string {
+	return fmt.Sprintf("%d milliseconds", d.Milliseconds())
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+// noActiveJobCond keeps lifecycle changes away from batches a worker may be
+// processing or resume later.
+const noActiveJobCond = `NOT EXISTS (
+		SELECT 1 FROM batch_jobs j
+		WHERE j.batch_id = b.id AND j.status IN ('queued', 'running', 'paused'))`
+
+func (r *batchRepository) SetBatchArchived(ctx context.Context, id uuid.UUID, archived bool) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE batches
+		SET archived_at = CASE WHEN $2 THEN COALESCE(archived_at, CURRENT_TIMESTAMP) END,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`, id, archived)
+	return err
+}
+
+// DeleteBatch returns domain.ErrBatchJobActive if a job was queued for the
+// batch after the caller checked.
+func (r *batchRepository) DeleteBatch(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// courier_performance stores the batch ID as text and has no foreign key.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM courier_performance WHERE batch_id = $1`, id.String()); err != nil {
+		return err
+	}
+	// Items, jobs and upload issues cascade.
+	res, err := tx.ExecContext(ctx, `DELETE FROM batches b WHERE b.id = $1 AND `+noActiveJobCond, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrBatchJobActive
+	}
+	return tx.Commit()
+}
+
+func (r *batchRepository) ListBatchesForItemPurge(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	return r.listBatchIDs(ctx, `
+		SELECT b.id
+		FROM batches b
+		JOIN user_settings us ON us.user_id = b.user_id
+		WHERE us.item_retention_days > 0
+		  AND b.items_purged_at IS NULL
+		  AND b.updated_at < now() - make_interval(days => us.item_retention_days)
+		  AND `+noActiveJobCond+`
+		ORDER BY b.updated_at
+		LIMIT $1`, limit)
+}
+
+func (r *batchRepository) ListBatchesForDeletion(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	return r.listBatchIDs(ctx, `
+		SELECT b.id
+		FROM batches b
+		JOIN user_settings us ON us.user_id = b.user_id
+		WHERE us.batch_retention_days > 0
+		  AND b.created_at < now() - make_interval(days => us.batch_retention_days)
+		  AND `+noActiveJobCond+`
+		ORDER BY b.created_at
+		LIMIT $1`, limit)
+}
+
+func (r *batchRepository) listBatchIDs(ctx context.Context, query string, args ...interface{}) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *batchRepository) PurgeBatchItems(ctx context.Context, id uuid.UUID, summary *domain.BatchStats) (bool, error) {
+	raw, err := json.Marshal(summary)
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// updated_at is left alone: the batch itself did not change.
+	res, err := tx.ExecContext(ctx, `
+		UPDATE batches b
+		SET summary = $2, items_purged_at = CURRENT_TIMESTAMP,
+		    archived_at = COALESCE(archived_at, CURRENT_TIMESTAMP)
+		WHERE b.id = $1 AND b.items_purged_at IS NULL AND `+noActiveJobCond, id, raw)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err // purged concurrently or a job was queued; nothing to do
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM batch_items WHERE batch_id = $1`, id); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM batch_upload_issues WHERE batch_id = $1`, id); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (r *batchRepository) GetBatchSummary(ctx context.Context, id uuid.UUID) (*domain.BatchStats, error) {
+	var raw []byte
+	var purgedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx,
+		`SELECT summary, items_purged_at FROM batches WHERE id = $1`, id).Scan(&raw, &purgedAt)
+	if err == sql.ErrNoRows || (err == nil && raw == nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stats domain.BatchStats
+	if err := json.Unmarshal(raw, &stats); err != nil {
+		return nil, err
+	}
+	if purgedAt.Valid {
+		stats.ItemsPurgedAt = &purgedAt.Time
+	}
+	return &stats, nil
+}
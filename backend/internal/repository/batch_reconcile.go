@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+// Orphan predicates. Field uploads never set system_address and system
+// uploads never set field coordinates, so an item with only one of them was
+// never matched by connote.
+const (
+	systemOnlyCond = `COALESCE(system_address, '') <> '' AND field_lat IS NULL`
+	fieldOnlyCond  = `COALESCE(system_address, '') = '' AND field_lat IS NOT NULL`
+)
+
+func (r *batchRepository) ListOrphanBatchItems(ctx context.Context, batchID uuid.UUID) ([]domain.OrphanItem, []domain.OrphanItem, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, connote, COALESCE(recipient_name, ''), COALESCE(system_address, ''),
+		       COALESCE(courier_id, ''), field_lat, field_lng, field_lat IS NULL
+		FROM batch_items
+		WHERE batch_id = $1 AND ((`+systemOnlyCond+`) OR (`+fieldOnlyCond+`))
+		ORDER BY connote`, batchID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var systemOnly, fieldOnly []domain.OrphanItem
+	for rows.Next() {
+		var o domain.OrphanItem
+		var isSystem bool
+		if err := rows.Scan(&o.ItemID, &o.Connote, &o.RecipientName, &o.SystemAddress,
+			&o.CourierID, &o.FieldLat, &o.FieldLng, &isSystem); err != nil {
+			return nil, nil, err
+		}
+		if isSystem {
+			systemOnly = append(systemOnly, o)
+		} else {
+			fieldOnly = append(fieldOnly, o)
+		}
+	}
+	return systemOnly, fieldOnly, rows.Err()
+}
+
+func (r *batchRepository) MergeOrphanBatchItems(ctx context.Context, batchID, systemItemID, fieldItemID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Deleting first locks the field item, so two requests cannot merge it twice.
+	var lat, lng float64
+	var courierID string
+	err = tx.QueryRowContext(ctx, `
+		DELETE FROM batch_items
+		WHERE id = $1 AND batch_id = $2 AND `+fieldOnlyCond+`
+		RETURNING field_lat, field_lng, COALESCE(courier_id, '')`,
+		fieldItemID, batchID,
+	).Scan(&lat, &lng, &courierID)
+	if err == sql.ErrNoRows {
+		return domain.ErrNotOrphanPair
+	}
+	if err != nil {
+		return err
+	}
+
+	// Results computed without field coordinates are stale; the item goes
+	// back to pending so the next (re)process run picks it up.
+	res, err := tx.ExecContext(ctx, `
+		UPDATE batch_items
+		SET field_lat = $3, field_lng = $4,
+		    courier_id = COALESCE(NULLIF($5, ''), courier_id),
+		    geocode_status = $6, distance_km = NULL, accuracy_level = '',
+		    reason_code = '', error = '', updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND batch_id = $2 AND `+systemOnlyCond,
+		systemItemID, batchID, lat, lng, courierID, domain.ItemStatusPending)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrNotOrphanPair
+	}
+	return tx.Commit()
+}
@@ -6,8 +6,25 @@ import (
 	"geoaccuracy-backend/internal/domain"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// batchItemColumns is the column list shared by every batch_items SELECT; it
+// must stay in sync with scanBatchItem.
+const batchItemColumns = `id, batch_id, connote, recipient_name, system_address, courier_id,
+		       system_lat, system_lng, field_lat, field_lng,
+		       distance_km, accuracy_level, error, geocode_status, created_at, updated_at,
+		       reason_code, address_quality_score, address_quality_flags,
+		       provider, attempt_count, from_cache,
+		       cache_status, geocoded_city, geocoded_province, processed_at,
+		       review_status, reviewer_id, review_comment, corrected_lat, corrected_lng, reviewed_at`
+
+// batchColumns is the column list of every batches SELECT; it must stay in
+// sync with scanBatch.
+const batchColumns = `id, user_id, name, status, created_at, updated_at, archived_at, items_purged_at,
+		       client, region, COALESCE(to_char(delivery_from, 'YYYY-MM-DD'), ''),
+		       COALESCE(to_char(delivery_to, 'YYYY-MM-DD'), ''), source, tags`
+
 type batchRepository struct {
 	db *sql.DB
 }
@@ -16,10 +33,23 @@ func NewBatchRepository(db *sql.DB) domain.BatchRepository {
 	return &batchRepository{db: db}
 }
 
+// scanBatch scans batchColumns followed by any extra columns.
+func scanBatch(row interface{ Scan(...interface{}) error }, extra ...interface{}) (*domain.Batch, error) {
+	b := &domain.Batch{}
+	dest := []interface{}{
+		&b.ID, &b.UserID, &b.Name, &b.Status, &b.CreatedAt, &b.UpdatedAt, &b.ArchivedAt, &b.ItemsPurgedAt,
+		&b.Client, &b.Region, &b.DeliveryFrom, &b.DeliveryTo, &b.Source, pq.Array(&b.Tags),
+	}
+	err := row.Scan(append(dest, extra...)...)
+	return b, err
+}
+
 func (r *batchRepository) CreateBatch(ctx context.Context, batch *domain.Batch) error {
 	query := `
-		INSERT INTO batches (id, user_id, name, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		INSERT INTO batches (id, user_id, name, status, client, region, delivery_from, delivery_to, source, tags,
+		                     created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')::date, NULLIF($8, '')::date, $9, $10,
+		        CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 		RETURNING id, created_at, updated_at
 	`
 	if batch.ID == uuid.Nil {
@@ -28,19 +58,17 @@ func (r *batchRepository) CreateBatch(ctx context.Context, batch *domain.Batch)
 
 	return r.db.QueryRowContext(ctx, query,
 		batch.ID, batch.UserID, batch.Name, batch.Status,
+		batch.Client, batch.Region, batch.DeliveryFrom, batch.DeliveryTo, batch.Source, pq.Array(nonNilStrings(batch.Tags)),
 	).Scan(&batch.ID, &batch.CreatedAt, &batch.UpdatedAt)
 }
 
 func (r *batchRepository) GetBatchByID(ctx context.Context, id uuid.UUID) (*domain.Batch, error) {
 	query := `
-		SELECT id, user_id, name, status, created_at, updated_at
+		SELECT ` + batchColumns + `
 		FROM batches
 		WHERE id = $1
 	`
-	b := &domain.Batch{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&b.ID, &b.UserID, &b.Name, &b.Status, &b.CreatedAt, &b.UpdatedAt,
-	)
+	b, err := scanBatch(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -50,30 +78,15 @@ func (r *batchRepository) GetBatchByID(ctx context.Context, id uuid.UUID) (*doma
 	return b, nil
 }
 
-func (r *batchRepository) GetBatchesByUserID(ctx context.Context, userID int64) ([]domain.Batch, error) {
-	query := `
-		SELECT id, user_id, name, status, created_at, updated_at
-		FROM batches
-		WHERE user_id = $1
-		ORDER BY created_at DESC
-	`
-	rows, err := r.db.QueryContext(ctx, query, userID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var batches []domain.Batch
-	for rows.Next() {
-		var b domain.Batch
-		if err := rows.Scan(
-			&b.ID, &b.UserID, &b.Name, &b.Status, &b.CreatedAt, &b.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		batches = append(batches, b)
-	}
-	return batches, rows.Err()
+func (r *batchRepository) UpdateBatchMetadata(ctx context.Context, id uuid.UUID, name string, meta domain.BatchMetadata) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE batches
+		SET name = $2, client = $3, region = $4,
+		    delivery_from = NULLIF($5, '')::date, delivery_to = NULLIF($6, '')::date,
+		    source = $7, tags = $8, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`,
+		id, name, meta.Client, meta.Region, meta.DeliveryFrom, meta.DeliveryTo, meta.Source, pq.Array(nonNilStrings(meta.Tags)))
+	return err
 }
 
 func (r *batchRepository) UpdateBatchStatus(ctx context.Context, id uuid.UUID, status domain.BatchStatus) error {
@@ -86,102 +99,103 @@ func (r *batchRepository) UpdateBatchStatus(ctx context.Context, id uuid.UUID, s
 	return err
 }
 
-func (r *batchRepository) UpsertBatchItems(ctx context.Context, items []domain.BatchItem) error {
-	if len(items) == 0 {
-		return nil
-	}
+func (r *batchRepository) GetBatchItemsByBatchID(ctx context.Context, batchID uuid.UUID) ([]domain.BatchItem, error) {
+	query := `
+		SELECT ` + batchItemColumns + `
+		FROM batch_items
+		WHERE batch_id = $1
+		ORDER BY created_at ASC
+	`
+	return r.queryBatchItems(ctx, query, batchID)
+}
+
+func (r *batchRepository) GetBatchItemsByBatchIDAndStatus(ctx context.Context, batchID uuid.UUID, status string) ([]domain.BatchItem, error) {
+	query := `
+		SELECT ` + batchItemColumns + `
+		FROM batch_items
+		WHERE batch_id = $1 AND geocode_status = $2
+		ORDER BY created_at ASC
+	`
+	return r.queryBatchItems(ctx, query, batchID, status)
+}
 
-	tx, err := r.db.BeginTx(ctx, nil)
+func (r *batchRepository) StreamBatchItems(ctx context.Context, batchID uuid.UUID, fn func(*domain.BatchItem) error) error {
+	query := `
+		SELECT ` + batchItemColumns + `
+		FROM batch_items
+		WHERE batch_id = $1
+		ORDER BY created_at ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, batchID)
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback()
-
-	for _, item := range items {
-		if item.ID == uuid.Nil {
-			item.ID = uuid.New()
-		}
+	defer rows.Close()
 
-		// Try update first — include courier_id so it is preserved on update
-		updateQuery := `
-			UPDATE batch_items
-			SET recipient_name = COALESCE(NULLIF($1, ''), recipient_name),
-				system_address = COALESCE(NULLIF($2, ''), system_address),
-				courier_id     = COALESCE(NULLIF($3, ''), courier_id),
-				system_lat     = COALESCE($4, system_lat),
-				system_lng     = COALESCE($5, system_lng),
-				field_lat      = COALESCE($6, field_lat),
-				field_lng      = COALESCE($7, field_lng),
-				distance_km    = COALESCE($8, distance_km),
-				accuracy_level = COALESCE(NULLIF($9, ''),  accuracy_level),
-				error          = COALESCE(NULLIF($10, ''), error),
-				geocode_status = COALESCE(NULLIF($11, ''), geocode_status),
-				updated_at     = CURRENT_TIMESTAMP
-			WHERE batch_id = $12 AND connote = $13
-		`
-
-		res, err := tx.ExecContext(ctx, updateQuery,
-			item.RecipientName, item.SystemAddress, item.CourierID,
-			item.SystemLat, item.SystemLng,
-			item.FieldLat, item.FieldLng,
-			item.DistanceKm, item.AccuracyLevel, item.Error, item.GeocodeStatus,
-			item.BatchID, item.Connote,
-		)
+	for rows.Next() {
+		item, err := scanBatchItem(rows)
 		if err != nil {
 			return err
 		}
-
-		rowsAffected, err := res.RowsAffected()
-		if err != nil {
+		if err := fn(&item); err != nil {
 			return err
 		}
-
-		if rowsAffected == 0 {
-			insertQuery := `
-				INSERT INTO batch_items (
-					id, batch_id, connote, recipient_name, system_address, courier_id,
-					system_lat, system_lng, field_lat, field_lng,
-					distance_km, accuracy_level, error, geocode_status
-				) VALUES (
-					$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
-				)
-			`
-			_, err = tx.ExecContext(ctx, insertQuery,
-				item.ID, item.BatchID, item.Connote, item.RecipientName, item.SystemAddress, item.CourierID,
-				item.SystemLat, item.SystemLng, item.FieldLat, item.FieldLng,
-				item.DistanceKm, item.AccuracyLevel, item.Error, item.GeocodeStatus,
-			)
-			if err != nil {
-				return err
-			}
-		}
 	}
-
-	return tx.Commit()
+	return rows.Err()
 }
 
-func (r *batchRepository) GetBatchItemsByBatchID(ctx context.Context, batchID uuid.UUID) ([]domain.BatchItem, error) {
+// MarkBatchItemsPending also clears the previous error and results. The
+// upsert keeps stored values over empty ones, so an item that succeeded
+// before and fails now would otherwise keep its old point and accuracy.
+func (r *batchRepository) MarkBatchItemsPending(ctx context.Context, batchID uuid.UUID, filter domain.ReprocessFilter) (int, error) {
 	query := `
-		SELECT id, batch_id, connote, recipient_name, system_address, courier_id,
-		       system_lat, system_lng, field_lat, field_lng,
-		       distance_km, accuracy_level, error, geocode_status, created_at, updated_at
-		FROM batch_items
-		WHERE batch_id = $1
-		ORDER BY created_at ASC
+		UPDATE batch_items
+		SET geocode_status = $1, error = '', updated_at = CURRENT_TIMESTAMP,
+		    system_lat = NULL, system_lng = NULL, distance_km = NULL,
+		    accuracy_level = '', reason_code = '', provider = '',
+		    from_cache = NULL, cache_status = '', geocoded_city = '', geocoded_province = ''
+		WHERE batch_id = $2
+		  AND (cardinality($3::text[]) = 0 OR COALESCE(geocode_status, '') = ANY($3))
+		  AND (cardinality($4::text[]) = 0 OR reason_code = ANY($4))
+		  AND (cardinality($5::text[]) = 0 OR provider = ANY($5))
+		  AND (cardinality($6::text[]) = 0 OR COALESCE(accuracy_level, '') = ANY($6))
 	`
-	return r.queryBatchItems(ctx, query, batchID)
+	res, err := r.db.ExecContext(ctx, query, domain.ItemStatusPending, batchID,
+		pq.Array(nonNilStrings(filter.GeocodeStatuses)), pq.Array(nonNilStrings(filter.ReasonCodes)),
+		pq.Array(nonNilStrings(filter.Providers)), pq.Array(nonNilStrings(filter.AccuracyLevels)),
+	)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
 }
 
-func (r *batchRepository) GetBatchItemsByBatchIDAndStatus(ctx context.Context, batchID uuid.UUID, status string) ([]domain.BatchItem, error) {
+// nonNilStrings makes pq.Array send '{}' rather than NULL for a nil slice.
+func nonNilStrings(a []string) []string {
+	if a == nil {
+		return []string{}
+	}
+	return a
+}
+
+func (r *batchRepository) GetPendingBatchItems(ctx context.Context, batchID uuid.UUID, limit int) ([]domain.BatchItem, error) {
 	query := `
-		SELECT id, batch_id, connote, recipient_name, system_address, courier_id,
-		       system_lat, system_lng, field_lat, field_lng,
-		       distance_km, accuracy_level, error, geocode_status, created_at, updated_at
+		SELECT ` + batchItemColumns + `
 		FROM batch_items
 		WHERE batch_id = $1 AND geocode_status = $2
-		ORDER BY created_at ASC
+		ORDER BY created_at ASC, id ASC
+		LIMIT $3
 	`
-	return r.queryBatchItems(ctx, query, batchID, status)
+	return r.queryBatchItems(ctx, query, batchID, domain.ItemStatusPending, limit)
+}
+
+func (r *batchRepository) CountBatchItemsByStatus(ctx context.Context, batchID uuid.UUID, status string) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM batch_items WHERE batch_id = $1 AND geocode_status = $2`, batchID, status,
+	).Scan(&n)
+	return n, err
 }
 
 func (r *batchRepository) queryBatchItems(ctx context.Context, query string, args ...interface{}) ([]domain.BatchItem, error) {
@@ -193,16 +207,37 @@ func (r *batchRepository) queryBatchItems(ctx context.Context, query string, arg
 
 	var items []domain.BatchItem
 	for rows.Next() {
-		var i domain.BatchItem
-		if err := rows.Scan(
-			&i.ID, &i.BatchID, &i.Connote, &i.RecipientName, &i.SystemAddress, &i.CourierID,
-			&i.SystemLat, &i.SystemLng, &i.FieldLat, &i.FieldLng,
-			&i.DistanceKm, &i.AccuracyLevel, &i.Error, &i.GeocodeStatus,
-			&i.CreatedAt, &i.UpdatedAt,
-		); err != nil {
+		i, err := scanBatchItem(rows)
+		if err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
 	return items, rows.Err()
 }
+
+// scanBatchItem reads one row selected with batchItemColumns followed by any
+// extra columns.
+func scanBatchItem(rows *sql.Rows, extra ...interface{}) (domain.BatchItem, error) {
+	var i domain.BatchItem
+	dest := []interface{}{
+		&i.ID, &i.BatchID, &i.Connote, &i.RecipientName, &i.SystemAddress, &i.CourierID,
+		&i.SystemLat, &i.SystemLng, &i.FieldLat, &i.FieldLng,
+		&i.DistanceKm, &i.AccuracyLevel, &i.Error, &i.GeocodeStatus,
+		&i.CreatedAt, &i.UpdatedAt,
+		&i.ReasonCode, &i.AddressQualityScore, pq.Array(&i.AddressQualityFlags),
+		&i.Provider, &i.AttemptCount, &i.FromCache,
+		&i.CacheStatus, &i.GeocodedCity, &i.GeocodedProvince, &i.ProcessedAt,
+		&i.ReviewStatus, &i.ReviewerID, &i.ReviewComment, &i.CorrectedLat, &i.CorrectedLng, &i.ReviewedAt,
+	}
+	err := rows.Scan(append(dest, extra...)...)
+	return i, err
+}
+
+// nullableStringArray maps a nil slice to SQL NULL so COALESCE keeps the stored value.
+func nullableStringArray(a []string) interface{} {
+	if a == nil {
+		return nil
+	}
+	return pq.Array(a)
+}
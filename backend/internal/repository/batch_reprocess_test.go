@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+func TestSaveCourierPerformance_ReprocessKeepsOneRow(t *testing.T) {
+	db := benchDB(t)
+	batchID := benchBatch(t, db)
+	batch, err := NewBatchRepository(db).GetBatchByID(context.Background(), batchID)
+	require.NoError(t, err)
+	repo := NewAnalyticsRepository(sqlx.NewDb(db, "postgres"))
+	ctx := context.Background()
+
+	save := func(batchRef, status string) {
+		require.NoError(t, repo.SaveCourierPerformance(ctx, &domain.CourierPerformance{
+			UserID: batch.UserID, BatchID: batchRef, CourierID: "K01", OrderID: "R1",
+			ReportedLat: -6.2, ReportedLng: 106.8,
+			AccuracyStatus: status, SLAStatus: "unknown", EventTimestamp: time.Now(),
+		}))
+	}
+	count := func(batchRef string) (n int, status string) {
+		require.NoError(t, db.QueryRowContext(ctx,
+			`SELECT COUNT(*), MAX(accuracy_status) FROM courier_performance WHERE batch_id = $1 AND order_id = 'R1'`,
+			batchRef).Scan(&n, &status))
+		return n, status
+	}
+
+	// Processing and then reprocessing the same item.
+	save(batchID.String(), "inaccurate")
+	save(batchID.String(), "accurate")
+	n, status := count(batchID.String())
+	assert.Equal(t, 1, n)
+	assert.Equal(t, "accurate", status)
+
+	// Webhook events for a free-text batch reference are history.
+	webhookRef := "WH-" + batchID.String()
+	save(webhookRef, "accurate")
+	save(webhookRef, "accurate")
+	n, _ = count(webhookRef)
+	assert.Equal(t, 2, n)
+}
+
+func TestMarkBatchItemsPending_FailedReprocessClearsResults(t *testing.T) {
+	db := benchDB(t)
+	batchID := benchBatch(t, db)
+	repo := NewBatchRepository(db)
+	ctx := context.Background()
+
+	lat, lng, dist, fromCache := -6.2, 106.8, 0.4, true
+	require.NoError(t, repo.UpsertBatchItems(ctx, []domain.BatchItem{{
+		BatchID: batchID, Connote: "R1", SystemAddress: "Jl. Sudirman 1",
+		SystemLat: &lat, SystemLng: &lng, DistanceKm: &dist, AccuracyLevel: "accurate",
+		GeocodeStatus: domain.ItemStatusCompleted, ReasonCode: domain.ReasonWithinAccurateThreshold,
+		Provider: "nominatim", AttemptCount: 1, FromCache: &fromCache,
+		CacheStatus: domain.CacheStatusExact, GeocodedCity: "Jakarta", GeocodedProvince: "DKI Jakarta",
+	}}))
+
+	n, err := repo.MarkBatchItemsPending(ctx, batchID, domain.ReprocessFilter{})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	// The reprocess fails: only status, error and the attempt are written.
+	require.NoError(t, repo.UpsertBatchItems(ctx, []domain.BatchItem{{
+		BatchID: batchID, Connote: "R1",
+		GeocodeStatus: domain.ItemStatusFailed, Error: "geocoding failed", AttemptCount: 2,
+	}}))
+
+	items, err := repo.GetBatchItemsByBatchID(ctx, batchID)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	item := items[0]
+	assert.Equal(t, domain.ItemStatusFailed, item.GeocodeStatus)
+	assert.Equal(t, "geocoding failed", item.Error)
+	assert.Nil(t, item.SystemLat)
+	assert.Nil(t, item.SystemLng)
+	assert.Nil(t, item.DistanceKm)
+	assert.Nil(t, item.FromCache)
+	assert.Empty(t, item.AccuracyLevel)
+	assert.Empty(t, item.ReasonCode)
+	assert.Empty(t, item.Provider)
+	assert.Empty(t, item.CacheStatus)
+	assert.Empty(t, item.GeocodedCity)
+	assert.Empty(t, item.GeocodedProvince)
+	assert.Equal(t, "Jl. Sudirman 1", item.SystemAddress)
+}
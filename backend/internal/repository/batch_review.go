@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+func (r *batchRepository) ListReviewQueue(ctx context.Context, batchID uuid.UUID, q domain.ReviewQueueQuery) (*domain.ReviewQueuePage, error) {
+	b := &queryBuilder{}
+	b.where("batch_id = %s", batchID)
+	b.where("accuracy_level = ANY(%s)", pq.Array(q.AccuracyLevels))
+	if q.ReviewerID != nil {
+		b.where("reviewer_id = %s", *q.ReviewerID)
+	}
+	cond := strings.Join(b.conds, " AND ")
+
+	// Counts ignore the status filter so the client can show every tab.
+	page := &domain.ReviewQueuePage{Items: []domain.BatchItem{}, Counts: map[domain.ReviewStatus]int{}}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT review_status, count(*) FROM batch_items WHERE `+cond+` GROUP BY 1`, b.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var status domain.ReviewStatus
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			return nil, err
+		}
+		page.Counts[status] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(q.Statuses) > 0 {
+		b.where("review_status = ANY(%s)", pq.Array(q.Statuses))
+	}
+	query := `SELECT ` + batchItemColumns + ` FROM batch_items WHERE ` + strings.Join(b.conds, " AND ") +
+		` ORDER BY distance_km DESC NULLS LAST, id LIMIT ` + b.arg(q.Limit) + ` OFFSET ` + b.arg(q.Offset)
+	items, err := r.queryBatchItems(ctx, query, b.args...)
+	if err != nil {
+		return nil, err
+	}
+	if items != nil {
+		page.Items = items
+	}
+	return page, nil
+}
+
+func (r *batchRepository) ListClaimedReviews(ctx context.Context, reviewerID int64, limit int) ([]domain.ReviewItem, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+qualifiedItemColumns("bi")+`, b.name
+		FROM batch_items bi
+		JOIN batches b ON b.id = bi.batch_id
+		WHERE bi.reviewer_id = $1 AND bi.review_status = $2
+		ORDER BY bi.reviewed_at, bi.id
+		LIMIT $3`, reviewerID, domain.ReviewInReview, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []domain.ReviewItem{}
+	for rows.Next() {
+		var it domain.ReviewItem
+		if it.BatchItem, err = scanBatchItem(rows, &it.BatchName); err != nil {
+			return nil, err
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// ApplyReview locks nothing up front: the WHERE clause re-checks the source
+// state, so of two reviewers claiming the same item only one gets it.
+func (r *batchRepository) ApplyReview(ctx context.Context, batchID uuid.UUID, u domain.ReviewUpdate) ([]uuid.UUID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Releasing an item clears the reviewer. An address correction without a
+	// coordinate takes the courier's reported one, so it needs one of them.
+	var reviewer, own interface{}
+	if u.To != domain.ReviewUnreviewed {
+		reviewer = u.ReviewerID
+	}
+	if u.RequireOwn {
+		own = u.ReviewerID
+	}
+	correcting := u.To == domain.ReviewAddressCorrected
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE batch_items
+		SET review_status = $4,
+		    reviewer_id = $5,
+		    review_comment = COALESCE(NULLIF($6::text, ''), review_comment),
+		    corrected_lat = CASE WHEN $7 THEN COALESCE($8::float8, field_lat) ELSE corrected_lat END,
+		    corrected_lng = CASE WHEN $7 THEN COALESCE($9::float8, field_lng) ELSE corrected_lng END,
+		    reviewed_at = CURRENT_TIMESTAMP,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE batch_id = $1 AND id = ANY($2) AND review_status = ANY($3)
+		  AND ($10::bigint IS NULL OR reviewer_id = $10)
+		  AND (NOT $7 OR $8::float8 IS NOT NULL OR field_lat IS NOT NULL)
+		RETURNING id, connote`,
+		batchID, pq.Array(u.ItemIDs), pq.Array(u.From), u.To, reviewer,
+		u.Comment, correcting, u.CorrectedLat, u.CorrectedLng, own)
+	if err != nil {
+		return nil, err
+	}
+	var ids []uuid.UUID
+	var connotes []string
+	for rows.Next() {
+		var id uuid.UUID
+		var connote string
+		if err := rows.Scan(&id, &connote); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+		connotes = append(connotes, connote)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(connotes) > 0 {
+		// courier_performance stores the batch ID as text.
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE courier_performance SET accuracy_override = NULLIF($3, '')
+			WHERE batch_id = $1 AND order_id = ANY($2)`,
+			batchID.String(), pq.Array(connotes), u.To.AccuracyOverride()); err != nil {
+			return nil, err
+		}
+	}
+	return ids, tx.Commit()
+}
+
+// qualifiedItemColumns prefixes batchItemColumns with a table alias.
+func qualifiedItemColumns(alias string) string {
+	cols := strings.Split(batchItemColumns, ",")
+	for i, c := range cols {
+		cols[i] = alias + "." + strings.TrimSpace(c)
+	}
+	return strings.Join(cols, ", ")
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+// GetBatchesByUserID lists the batches matching f that the user owns or has
+// been shared, newest first.
+func (r *batchRepository) GetBatchesByUserID(ctx context.Context, userID int64, f domain.BatchFilter) ([]domain.Batch, error) {
+	b := &queryBuilder{}
+	user := b.arg(userID)
+	shared := "b.id IN (SELECT s.batch_id FROM batch_shares s WHERE " + fmt.Sprintf(sharedWithUserCond, user) + ")"
+	switch f.Scope {
+	case domain.BatchScopeOwned:
+		b.conds = append(b.conds, "b.user_id = "+user)
+	case domain.BatchScopeShared:
+		b.conds = append(b.conds, "b.user_id <> "+user+" AND "+shared)
+	default:
+		b.conds = append(b.conds, "(b.user_id = "+user+" OR "+shared+")")
+	}
+	b.where("(archived_at IS NOT NULL) = %s", f.Archived)
+	if f.Query != "" {
+		p := b.arg(likeContains(f.Query))
+		b.conds = append(b.conds, "(name ILIKE "+p+" ESCAPE '\\' OR client ILIKE "+p+" ESCAPE '\\' OR region ILIKE "+p+" ESCAPE '\\')")
+	}
+	if f.Client != "" {
+		b.where("lower(client) = lower(%s)", f.Client)
+	}
+	if f.Region != "" {
+		b.where("lower(region) = lower(%s)", f.Region)
+	}
+	if len(f.Sources) > 0 {
+		b.where("source = ANY(%s)", pq.Array(f.Sources))
+	}
+	if len(f.Statuses) > 0 {
+		b.where("status = ANY(%s)", pq.Array(f.Statuses))
+	}
+	if len(f.Tags) > 0 {
+		b.where("tags @> %s::text[]", pq.Array(f.Tags))
+	}
+	// A batch with only one delivery date covers just that day.
+	if f.DeliveryFrom != "" {
+		b.where("COALESCE(delivery_to, delivery_from) >= %s::date", f.DeliveryFrom)
+	}
+	if f.DeliveryTo != "" {
+		b.where("COALESCE(delivery_from, delivery_to) <= %s::date", f.DeliveryTo)
+	}
+	if f.CreatedFrom != "" {
+		b.where("created_at >= %s::date", f.CreatedFrom)
+	}
+	if f.CreatedTo != "" {
+		b.where("created_at < %s::date + 1", f.CreatedTo)
+	}
+
+	access := `CASE WHEN b.user_id = ` + user + ` THEN 'owner' ELSE (
+			SELECT CASE WHEN bool_or(s.permission = 'editor') THEN 'editor' ELSE 'viewer' END
+			FROM batch_shares s
+			WHERE s.batch_id = b.id AND ` + fmt.Sprintf(sharedWithUserCond, user) + `) END`
+	query := `
+		SELECT ` + batchColumns + `, ` + access + `
+		FROM batches b
+		WHERE ` + strings.Join(b.conds, " AND ") + `
+		ORDER BY created_at DESC, id`
+	if f.Limit > 0 {
+		query += " LIMIT " + b.arg(f.Limit) + " OFFSET " + b.arg(f.Offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var batches []domain.Batch
+	for rows.Next() {
+		var access string
+		batch, err := scanBatch(rows, &access)
+		if err != nil {
+			return nil, err
+		}
+		batch.Access = domain.BatchPermission(access)
+		batches = append(batches, *batch)
+	}
+	return batches, rows.Err()
+}
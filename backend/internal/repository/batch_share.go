@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+// sharedWithUserCond matches the shares (alias s) granted to the user in
+// placeholder %[1]s, directly or through a team.
+const sharedWithUserCond = `(s.user_id = %[1]s OR s.team_id IN (
+			SELECT tm.team_id FROM team_members tm WHERE tm.user_id = %[1]s))`
+
+// accessibleBatchCond matches the batches (alias b) the user in placeholder
+// %[1]s owns or has been shared.
+const accessibleBatchCond = `(b.user_id = %[1]s OR b.id IN (SELECT s.batch_id FROM batch_shares s WHERE ` +
+	sharedWithUserCond + `))`
+
+func (r *batchRepository) GetBatchPermission(ctx context.Context, batchID uuid.UUID, userID int64) (domain.BatchPermission, error) {
+	var perm sql.NullString
+	err := r.db.QueryRowContext(ctx, `
+		SELECT CASE WHEN bool_or(s.permission = 'editor') THEN 'editor'
+		            WHEN count(*) > 0 THEN 'viewer' END
+		FROM batch_shares s
+		WHERE s.batch_id = $1 AND `+fmt.Sprintf(sharedWithUserCond, "$2"), batchID, userID).Scan(&perm)
+	if err != nil {
+		return "", err
+	}
+	return domain.BatchPermission(perm.String), nil
+}
+
+func (r *batchRepository) ListBatchShares(ctx context.Context, batchID uuid.UUID) ([]domain.BatchShare, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT s.id, s.batch_id, s.user_id, s.team_id, COALESCE(u.name, t.name, ''),
+		       s.permission, COALESCE(s.created_by, 0), s.created_at
+		FROM batch_shares s
+		LEFT JOIN users u ON u.id = s.user_id
+		LEFT JOIN teams t ON t.id = s.team_id
+		WHERE s.batch_id = $1
+		ORDER BY s.created_at, s.id`, batchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var shares []domain.BatchShare
+	for rows.Next() {
+		var sh domain.BatchShare
+		if err := rows.Scan(&sh.ID, &sh.BatchID, &sh.UserID, &sh.TeamID, &sh.Name,
+			&sh.Permission, &sh.CreatedBy, &sh.CreatedAt); err != nil {
+			return nil, err
+		}
+		shares = append(shares, sh)
+	}
+	return shares, rows.Err()
+}
+
+func (r *batchRepository) UpsertBatchShare(ctx context.Context, share *domain.BatchShare) error {
+	// The SELECT yields no row for an unknown user or team, so nothing is
+	// inserted and Scan reports sql.ErrNoRows.
+	query := `
+		INSERT INTO batch_shares (batch_id, user_id, permission, created_by)
+		SELECT $1, u.id, $3, $4 FROM users u WHERE u.id = $2
+		ON CONFLICT (batch_id, user_id) WHERE user_id IS NOT NULL
+		DO UPDATE SET permission = EXCLUDED.permission
+		RETURNING id, created_at, (SELECT name FROM users WHERE id = $2)`
+	target := share.UserID
+	if share.TeamID != nil {
+		query = `
+		INSERT INTO batch_shares (batch_id, team_id, permission, created_by)
+		SELECT $1, t.id, $3, $4 FROM teams t WHERE t.id = $2
+		ON CONFLICT (batch_id, team_id) WHERE team_id IS NOT NULL
+		DO UPDATE SET permission = EXCLUDED.permission
+		RETURNING id, created_at, (SELECT name FROM teams WHERE id = $2)`
+		target = share.TeamID
+	}
+
+	err := r.db.QueryRowContext(ctx, query, share.BatchID, *target, share.Permission, share.CreatedBy).
+		Scan(&share.ID, &share.CreatedAt, &share.Name)
+	if err == sql.ErrNoRows {
+		return domain.ErrShareTargetNotFound
+	}
+	return err
+}
+
+func (r *batchRepository) DeleteBatchShare(ctx context.Context, batchID uuid.UUID, shareID int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM batch_shares WHERE id = $1 AND batch_id = $2`, shareID, batchID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *batchRepository) TransferBatchOwnership(ctx context.Context, batchID uuid.UUID, fromUserID, toUserID int64, keepAccess bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE batches
+		SET user_id = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND user_id = $2 AND EXISTS (SELECT 1 FROM users WHERE id = $3)`,
+		batchID, fromUserID, toUserID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return domain.ErrShareTargetNotFound
+	}
+
+	// The new owner no longer needs a share.
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM batch_shares WHERE batch_id = $1 AND user_id = $2`, batchID, toUserID); err != nil {
+		return err
+	}
+	if keepAccess {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO batch_shares (batch_id, user_id, permission, created_by)
+			VALUES ($1, $2, 'editor', $2)
+			ON CONFLICT (batch_id, user_id) WHERE user_id IS NOT NULL
+			DO UPDATE SET permission = 'editor'`, batchID, fromUserID); err != nil {
+			return err
+		}
+	}
+	// courier_performance stores the batch ID as text.
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE courier_performance SET user_id = $2 WHERE batch_id = $1`, batchID.String(), toUserID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+func (r *batchRepository) GetBatchStats(ctx context.Context, batchID uuid.UUID) (*domain.BatchStats, error) {
+	stats := &domain.BatchStats{
+		BatchID:  batchID,
+		Statuses: map[string]int{},
+		Reviews:  map[domain.ReviewStatus]int{},
+		Accuracy: map[string]int{},
+	}
+
+	d := &stats.Distance
+	err := r.db.QueryRowContext(ctx, `
+		SELECT count(*),
+		       count(distance_km),
+		       avg(distance_km),
+		       percentile_cont(0.5)  WITHIN GROUP (ORDER BY distance_km),
+		       percentile_cont(0.9)  WITHIN GROUP (ORDER BY distance_km),
+		       percentile_cont(0.99) WITHIN GROUP (ORDER BY distance_km),
+		       min(distance_km),
+		       max(distance_km),
+		       count(from_cache),
+		       count(*) FILTER (WHERE from_cache)
+		FROM batch_items
+		WHERE batch_id = $1`, batchID,
+	).Scan(&stats.TotalItems, &d.Count, &d.MeanKm, &d.MedianKm, &d.P90Km, &d.P99Km, &d.MinKm, &d.MaxKm,
+		&stats.Cache.Lookups, &stats.Cache.Hits)
+	if err != nil {
+		return nil, err
+	}
+	if stats.Cache.Lookups > 0 {
+		ratio := float64(stats.Cache.Hits) / float64(stats.Cache.Lookups)
+		stats.Cache.HitRatio = &ratio
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT COALESCE(geocode_status, ''), count(*)
+		FROM batch_items
+		WHERE batch_id = $1
+		GROUP BY 1`, batchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var status string
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			return nil, err
+		}
+		stats.Statuses[status] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.reviewStats(ctx, stats); err != nil {
+		return nil, err
+	}
+	if stats.Providers, err = r.groupStats(ctx, batchID, "provider"); err != nil {
+		return nil, err
+	}
+	if stats.Couriers, err = r.groupStats(ctx, batchID, "courier_id"); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// reviewStats fills stats.Reviews and stats.Accuracy.
+func (r *batchRepository) reviewStats(ctx context.Context, stats *domain.BatchStats) error {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT review_status, COALESCE(accuracy_level, ''), count(*)
+		FROM batch_items
+		WHERE batch_id = $1
+		GROUP BY 1, 2`, stats.BatchID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var status domain.ReviewStatus
+		var level string
+		var n int
+		if err := rows.Scan(&status, &level, &n); err != nil {
+			return err
+		}
+		stats.Reviews[status] += n
+		if override := status.AccuracyOverride(); override != "" {
+			level = override
+		}
+		if level != "" {
+			stats.Accuracy[level] += n
+		}
+	}
+	return rows.Err()
+}
+
+// groupStats breaks a batch down by column, which must be a trusted column
+// name. Groups are ordered by size.
+func (r *batchRepository) groupStats(ctx context.Context, batchID uuid.UUID, column string) ([]domain.GroupStats, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT COALESCE(`+column+`, ''),
+		       count(*),
+		       count(*) FILTER (WHERE geocode_status = $2),
+		       count(*) FILTER (WHERE geocode_status = $3),
+		       count(*) FILTER (WHERE from_cache),
+		       avg(distance_km),
+		       percentile_cont(0.5) WITHIN GROUP (ORDER BY distance_km),
+		       percentile_cont(0.9) WITHIN GROUP (ORDER BY distance_km)
+		FROM batch_items
+		WHERE batch_id = $1
+		GROUP BY 1
+		ORDER BY 2 DESC, 1`, batchID, domain.ItemStatusCompleted, domain.ItemStatusFailed)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []domain.GroupStats{}
+	for rows.Next() {
+		var g domain.GroupStats
+		if err := rows.Scan(&g.Key, &g.Items, &g.Completed, &g.Failed, &g.CacheHits,
+			&g.MeanKm, &g.MedianKm, &g.P90Km); err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+	return groups, rows.Err()
+}
+
+func (r *batchRepository) CountDistanceBuckets(ctx context.Context, batchID uuid.UUID, edges []float64) (map[int]int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT width_bucket(distance_km, $2::float8[]), count(*)
+		FROM batch_items
+		WHERE batch_id = $1 AND distance_km IS NOT NULL
+		GROUP BY 1`, batchID, pq.Array(edges))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int]int)
+	for rows.Next() {
+		var bucket, n int
+		if err := rows.Scan(&bucket, &n); err != nil {
+			return nil, err
+		}
+		counts[bucket] = n
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+func (r *batchRepository) ReplaceUploadIssues(ctx context.Context, batchID uuid.UUID, source string, issues []domain.UploadIssue) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		`DELETE FROM batch_upload_issues WHERE batch_id = $1 AND source = $2`, batchID, source); err != nil {
+		return err
+	}
+
+	if len(issues) > 0 {
+		rows := make([]int64, len(issues))
+		connotes := make([]string, len(issues))
+		codes := make([]string, len(issues))
+		messages := make([]string, len(issues))
+		skipped := make([]bool, len(issues))
+		for i, is := range issues {
+			rows[i], connotes[i], codes[i], messages[i], skipped[i] = int64(is.Row), is.Connote, is.Code, is.Message, is.Skipped
+		}
+		// The report caps issues, so a single unnest insert is enough.
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO batch_upload_issues (batch_id, source, row_number, connote, code, message, skipped)
+			SELECT $1, $2, * FROM unnest($3::int[], $4::text[], $5::text[], $6::text[], $7::bool[])`,
+			batchID, source, pq.Array(rows), pq.Array(connotes), pq.Array(codes), pq.Array(messages), pq.Array(skipped),
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *batchRepository) ListUploadIssues(ctx context.Context, batchID uuid.UUID) ([]domain.BatchUploadIssue, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT batch_id, source, row_number, connote, code, message, skipped, created_at
+		FROM batch_upload_issues
+		WHERE batch_id = $1
+		ORDER BY source DESC, row_number, id`, batchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	issues := []domain.BatchUploadIssue{}
+	for rows.Next() {
+		var is domain.BatchUploadIssue
+		if err := rows.Scan(&is.BatchID, &is.Source, &is.Row, &is.Connote, &is.Code,
+			&is.Message, &is.Skipped, &is.CreatedAt); err != nil {
+			return nil, err
+		}
+		issues = append(issues, is)
+	}
+	return issues, rows.Err()
+}
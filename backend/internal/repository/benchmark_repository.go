@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+type benchmarkRepository struct {
+	db *sql.DB
+}
+
+func NewBenchmarkRepository(db *sql.DB) domain.BenchmarkRepository {
+	return &benchmarkRepository{db: db}
+}
+
+func (r *benchmarkRepository) CreateDataset(ctx context.Context, ds *domain.GroundTruthDataset, entries []domain.GroundTruthEntry) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO ground_truth_datasets (user_id, name) VALUES ($1, $2) RETURNING id, created_at`,
+		ds.UserID, ds.Name,
+	).Scan(&ds.ID, &ds.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx,
+		`INSERT INTO ground_truth_entries (dataset_id, address, province, lat, lng) VALUES ($1, $2, $3, $4, $5)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, e := range entries {
+		if _, err := stmt.ExecContext(ctx, ds.ID, e.Address, e.Province, e.Lat, e.Lng); err != nil {
+			return err
+		}
+	}
+	ds.EntryCount = len(entries)
+
+	return tx.Commit()
+}
+
+const datasetSelect = `
+	SELECT d.id, d.user_id, d.name, d.created_at,
+	       (SELECT COUNT(*) FROM ground_truth_entries e WHERE e.dataset_id = d.id)
+	FROM ground_truth_datasets d
+`
+
+func (r *benchmarkRepository) GetDataset(ctx context.Context, id, userID int64) (*domain.GroundTruthDataset, error) {
+	ds := &domain.GroundTruthDataset{}
+	err := r.db.QueryRowContext(ctx, datasetSelect+` WHERE d.id = $1 AND d.user_id = $2`, id, userID).Scan(
+		&ds.ID, &ds.UserID, &ds.Name, &ds.CreatedAt, &ds.EntryCount,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ds, nil
+}
+
+func (r *benchmarkRepository) ListDatasets(ctx context.Context, userID int64) ([]domain.GroundTruthDataset, error) {
+	rows, err := r.db.QueryContext(ctx, datasetSelect+` WHERE d.user_id = $1 ORDER BY d.created_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var datasets []domain.GroundTruthDataset
+	for rows.Next() {
+		var ds domain.GroundTruthDataset
+		if err := rows.Scan(&ds.ID, &ds.UserID, &ds.Name, &ds.CreatedAt, &ds.EntryCount); err != nil {
+			return nil, err
+		}
+		datasets = append(datasets, ds)
+	}
+	return datasets, rows.Err()
+}
+
+func (r *benchmarkRepository) DeleteDataset(ctx context.Context, id, userID int64) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM ground_truth_datasets WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *benchmarkRepository) GetEntries(ctx context.Context, datasetID int64) ([]domain.GroundTruthEntry, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, dataset_id, address, province, lat, lng
+		FROM ground_truth_entries
+		WHERE dataset_id = $1
+		ORDER BY id ASC
+	`, datasetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []domain.GroundTruthEntry
+	for rows.Next() {
+		var e domain.GroundTruthEntry
+		if err := rows.Scan(&e.ID, &e.DatasetID, &e.Address, &e.Province, &e.Lat, &e.Lng); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (r *benchmarkRepository) CreateReport(ctx context.Context, report *domain.BenchmarkReport) error {
+	return r.db.QueryRowContext(ctx, `
+		INSERT INTO benchmark_reports (user_id, dataset_id, status, providers, heartbeat_at)
+		VALUES ($1, $2, $3, $4, now())
+		RETURNING id, created_at
+	`, report.UserID, report.DatasetID, report.Status, pq.Array(report.Providers)).Scan(&report.ID, &report.CreatedAt)
+}
+
+func (r *benchmarkRepository) FinishReport(ctx context.Context, id int64, status domain.BenchmarkStatus, result *domain.BenchmarkResult, errMsg string) error {
+	var resultJSON []byte
+	if result != nil {
+		var err error
+		if resultJSON, err = json.Marshal(result); err != nil {
+			return err
+		}
+	}
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE benchmark_reports
+		SET status = $1, result = $2, error = $3, completed_at = $4
+		WHERE id = $5
+	`, status, nullableJSON(resultJSON), errMsg, time.Now(), id)
+	return err
+}
+
+func (r *benchmarkRepository) HeartbeatReport(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE benchmark_reports SET heartbeat_at = now()
+		WHERE id = $1 AND status = 'running'
+	`, id)
+	return err
+}
+
+func (r *benchmarkRepository) FailStaleReports(ctx context.Context, staleAfter time.Duration, errMsg string) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE benchmark_reports
+		SET status = 'failed', error = $1, completed_at = now()
+		WHERE status = 'running'
+		  AND COALESCE(heartbeat_at, created_at) < now() - make_interval(secs => $2)
+	`, errMsg, staleAfter.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+const reportSelect = `
+	SELECT id, user_id, dataset_id, status, providers, result, error, created_at, completed_at
+	FROM benchmark_reports
+`
+
+func (r *benchmarkRepository) GetReport(ctx context.Context, id, userID int64) (*domain.BenchmarkReport, error) {
+	rows, err := r.db.QueryContext(ctx, reportSelect+` WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	return scanBenchmarkReport(rows)
+}
+
+func (r *benchmarkRepository) ListReports(ctx context.Context, datasetID, userID int64) ([]domain.BenchmarkReport, error) {
+	rows, err := r.db.QueryContext(ctx, reportSelect+` WHERE dataset_id = $1 AND user_id = $2 ORDER BY created_at DESC`, datasetID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []domain.BenchmarkReport
+	for rows.Next() {
+		rep, err := scanBenchmarkReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, *rep)
+	}
+	return reports, rows.Err()
+}
+
+func scanBenchmarkReport(rows *sql.Rows) (*domain.BenchmarkReport, error) {
+	var rep domain.BenchmarkReport
+	var resultJSON []byte
+	if err := rows.Scan(
+		&rep.ID, &rep.UserID, &rep.DatasetID, &rep.Status, pq.Array(&rep.Providers),
+		&resultJSON, &rep.Error, &rep.CreatedAt, &rep.CompletedAt,
+	); err != nil {
+		return nil, err
+	}
+	if len(resultJSON) > 0 {
+		rep.Result = &domain.BenchmarkResult{}
+		if err := json.Unmarshal(resultJSON, rep.Result); err != nil {
+			return nil, err
+		}
+	}
+	return &rep, nil
+}
+
+// nullableJSON maps an empty document to SQL NULL.
+func nullableJSON(b []byte) interface{} {
+	if len(b) == 0 {
+		return nil
+	}
+	return string(b)
+}
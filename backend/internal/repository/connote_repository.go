@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+// connoteEventSources are unioned into a connote's timeline. Each selects
+// origin, source, occurred_at, batch_ref, batch_name, courier_id, lat, lng,
+// system_lat, system_lng, distance_km, accuracy, geocode_status, reason_code
+// and review_status for connote $1 as visible to user $2. A new kind of
+// ingest is added to the timeline by adding its query here.
+var connoteEventSources = []string{
+	// Items of batches the user owns or that are shared with them.
+	`SELECT '` + domain.ConnoteOriginBatchItem + `', b.source, COALESCE(bi.processed_at, bi.created_at), b.id::text, b.name,
+	        COALESCE(bi.courier_id, ''), bi.field_lat, bi.field_lng, bi.system_lat, bi.system_lng,
+	        bi.distance_km, COALESCE(bi.accuracy_level, ''), COALESCE(bi.geocode_status, ''),
+	        COALESCE(bi.reason_code, ''), bi.review_status
+	 FROM batch_items bi
+	 JOIN batches b ON b.id = bi.batch_id
+	 WHERE bi.connote = $1
+	   AND ` + fmt.Sprintf(accessibleBatchCond, "$2"),
+
+	// Webhook and ERP points. Processing a batch also records courier events:
+	// while the batch item exists they repeat it and are left out; once the
+	// items were purged they stand in for it and are labelled from the batch.
+	`SELECT CASE WHEN b.id IS NULL THEN '` + domain.ConnoteOriginCourierEvent + `'
+	             ELSE '` + domain.ConnoteOriginBatchItem + `' END,
+	        CASE WHEN b.id IS NOT NULL THEN b.source
+	             WHEN cp.batch_id LIKE '` + domain.ERPSyncBatchPrefix + `%' THEN '` + domain.BatchSourceERP + `'
+	             ELSE '` + domain.BatchSourceWebhook + `' END,
+	        cp.event_timestamp, cp.batch_id, COALESCE(b.name, ''),
+	        cp.courier_id, cp.reported_lat, cp.reported_lng, cp.actual_lat, cp.actual_lng,
+	        cp.distance_variance_meters / 1000, COALESCE(cp.accuracy_override, cp.accuracy_status), '',
+	        '', ''
+	 FROM courier_performance cp
+	 LEFT JOIN batches b ON b.id = CASE WHEN cp.` + batchEventPredicate + ` THEN cp.batch_id::uuid END
+	 WHERE cp.order_id = $1
+	   AND ((b.id IS NULL AND cp.user_id = $2) OR (b.id IS NOT NULL AND ` + fmt.Sprintf(accessibleBatchCond, "$2") + `))
+	   AND NOT EXISTS (SELECT 1 FROM batch_items bi WHERE bi.batch_id = b.id AND bi.connote = cp.order_id)`,
+}
+
+type connoteRepository struct {
+	db *sql.DB
+}
+
+func NewConnoteRepository(db *sql.DB) domain.ConnoteRepository {
+	return &connoteRepository{db: db}
+}
+
+func (r *connoteRepository) ListConnoteEvents(ctx context.Context, userID int64, connote string, limit int) ([]domain.ConnoteEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT * FROM (`+strings.Join(connoteEventSources, "\n\tUNION ALL\n\t")+`) e
+		ORDER BY 3 DESC
+		LIMIT $3`, connote, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []domain.ConnoteEvent
+	for rows.Next() {
+		var e domain.ConnoteEvent
+		if err := rows.Scan(&e.Origin, &e.Source, &e.OccurredAt, &e.BatchRef, &e.BatchName,
+			&e.CourierID, &e.Lat, &e.Lng, &e.SystemLat, &e.SystemLng,
+			&e.DistanceKm, &e.Accuracy, &e.GeocodeStatus, &e.ReasonCode, &e.ReviewStatus); err != nil {
+			return nil, err
+		}
+		if override := e.ReviewStatus.AccuracyOverride(); override != "" {
+			e.Accuracy = override
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
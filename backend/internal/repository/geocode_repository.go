@@ -4,11 +4,16 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/lib/pq"
+
 	"geoaccuracy-backend/internal/domain"
 )
 
 type GeocodeRepository interface {
 	GetCachedResult(ctx context.Context, addressHash string) (*domain.GeocodeCache, error)
+	// GetCachedResults looks up many hashes in one query; misses are absent
+	// from the returned map.
+	GetCachedResults(ctx context.Context, addressHashes []string) (map[string]*domain.GeocodeCache, error)
 	SaveResult(ctx context.Context, cache *domain.GeocodeCache) error
 }
 
@@ -22,7 +27,7 @@ func NewGeocodeRepository(db *sql.DB) GeocodeRepository {
 
 func (r *postgresGeocodeRepository) GetCachedResult(ctx context.Context, addressHash string) (*domain.GeocodeCache, error) {
 	query := `
-		SELECT id, address_hash, original_address, city, province, lat, lng, provider, created_at, expires_at
+		SELECT id, address_hash, original_address, city, province, lat, lng, provider, precision_level, created_at, expires_at
 		FROM geocode_cache
 		WHERE address_hash = $1 AND expires_at > now()
 	`
@@ -30,7 +35,7 @@ func (r *postgresGeocodeRepository) GetCachedResult(ctx context.Context, address
 	var c domain.GeocodeCache
 	err := r.db.QueryRowContext(ctx, query, addressHash).Scan(
 		&c.ID, &c.AddressHash, &c.OriginalAddress, &c.City, &c.Province,
-		&c.Lat, &c.Lng, &c.Provider, &c.CreatedAt, &c.ExpiresAt,
+		&c.Lat, &c.Lng, &c.Provider, &c.Precision, &c.CreatedAt, &c.ExpiresAt,
 	)
 
 	if err != nil {
@@ -44,10 +49,40 @@ func (r *postgresGeocodeRepository) GetCachedResult(ctx context.Context, address
 	return &c, nil
 }
 
+func (r *postgresGeocodeRepository) GetCachedResults(ctx context.Context, addressHashes []string) (map[string]*domain.GeocodeCache, error) {
+	results := make(map[string]*domain.GeocodeCache, len(addressHashes))
+	if len(addressHashes) == 0 {
+		return results, nil
+	}
+
+	query := `
+		SELECT id, address_hash, original_address, city, province, lat, lng, provider, precision_level, created_at, expires_at
+		FROM geocode_cache
+		WHERE address_hash = ANY($1) AND expires_at > now()
+	`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(addressHashes))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c domain.GeocodeCache
+		if err := rows.Scan(
+			&c.ID, &c.AddressHash, &c.OriginalAddress, &c.City, &c.Province,
+			&c.Lat, &c.Lng, &c.Provider, &c.Precision, &c.CreatedAt, &c.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		results[c.AddressHash] = &c
+	}
+	return results, rows.Err()
+}
+
 func (r *postgresGeocodeRepository) SaveResult(ctx context.Context, c *domain.GeocodeCache) error {
 	query := `
-		INSERT INTO geocode_cache (address_hash, original_address, city, province, lat, lng, provider, expires_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO geocode_cache (address_hash, original_address, city, province, lat, lng, provider, precision_level, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		ON CONFLICT (address_hash) DO UPDATE SET
 			original_address = EXCLUDED.original_address,
 			city = EXCLUDED.city,
@@ -55,11 +90,12 @@ func (r *postgresGeocodeRepository) SaveResult(ctx context.Context, c *domain.Ge
 			lat = EXCLUDED.lat,
 			lng = EXCLUDED.lng,
 			provider = EXCLUDED.provider,
+			precision_level = EXCLUDED.precision_level,
 			expires_at = EXCLUDED.expires_at
 		RETURNING id, created_at
 	`
 	err := r.db.QueryRowContext(ctx, query,
-		c.AddressHash, c.OriginalAddress, c.City, c.Province, c.Lat, c.Lng, c.Provider, c.ExpiresAt,
+		c.AddressHash, c.OriginalAddress, c.City, c.Province, c.Lat, c.Lng, c.Provider, c.Precision, c.ExpiresAt,
 	).Scan(&c.ID, &c.CreatedAt)
 
 	return err
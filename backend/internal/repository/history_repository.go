@@ -21,10 +21,10 @@ func NewHistoryRepository(db *sql.DB) *HistoryRepository {
 func (r *HistoryRepository) Save(s *domain.ComparisonSession) error {
 	return r.db.QueryRow(
 		`INSERT INTO comparison_sessions
-		 (user_id, total_count, accurate_count, fairly_count, inaccurate_count, error_count)
-		 VALUES ($1,$2,$3,$4,$5,$6)
+		 (user_id, total_count, accurate_count, fairly_count, inaccurate_count, error_count, unverifiable_count)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7)
 		 RETURNING id, created_at`,
-		s.UserID, s.TotalCount, s.AccurateCount, s.FairlyCount, s.InaccurateCount, s.ErrorCount,
+		s.UserID, s.TotalCount, s.AccurateCount, s.FairlyCount, s.InaccurateCount, s.ErrorCount, s.UnverifiableCount,
 	).Scan(&s.ID, &s.CreatedAt)
 }
 
@@ -39,7 +39,7 @@ func (r *HistoryRepository) ListByUserID(userID, page, pageSize int) ([]domain.C
 
 	offset := (page - 1) * pageSize
 	rows, err := r.db.Query(
-		`SELECT id, user_id, total_count, accurate_count, fairly_count, inaccurate_count, error_count, created_at
+		`SELECT id, user_id, total_count, accurate_count, fairly_count, inaccurate_count, error_count, unverifiable_count, created_at
 		 FROM comparison_sessions
 		 WHERE user_id = $1
 		 ORDER BY created_at DESC
@@ -55,7 +55,7 @@ func (r *HistoryRepository) ListByUserID(userID, page, pageSize int) ([]domain.C
 	for rows.Next() {
 		var s domain.ComparisonSession
 		if err := rows.Scan(
-			&s.ID, &s.UserID, &s.TotalCount, &s.AccurateCount, &s.FairlyCount, &s.InaccurateCount, &s.ErrorCount, &s.CreatedAt,
+			&s.ID, &s.UserID, &s.TotalCount, &s.AccurateCount, &s.FairlyCount, &s.InaccurateCount, &s.ErrorCount, &s.UnverifiableCount, &s.CreatedAt,
 		); err != nil {
 			return nil, 0, fmt.Errorf("history repository scan: %w", err)
 		}
@@ -79,7 +79,8 @@ func (r *HistoryRepository) GetAnalytics(userID int) (*domain.AnalyticsData, err
 			COALESCE(SUM(accurate_count), 0),
 			COALESCE(SUM(fairly_count), 0),
 			COALESCE(SUM(inaccurate_count), 0),
-			COALESCE(SUM(error_count), 0)
+			COALESCE(SUM(error_count), 0),
+			COALESCE(SUM(unverifiable_count), 0)
 		 FROM comparison_sessions 
 		 WHERE user_id = $1`,
 		userID,
@@ -90,19 +91,21 @@ func (r *HistoryRepository) GetAnalytics(userID int) (*domain.AnalyticsData, err
 		&agg.TotalFairly,
 		&agg.TotalInaccurate,
 		&agg.TotalError,
+		&agg.TotalUnverifiable,
 	)
 
 	if err != nil {
 		return nil, fmt.Errorf("history repo GetAnalytics aggregate: %w", err)
 	}
 
-	if agg.TotalRecords > 0 {
-		agg.AvgAccuracyRate = int((float64(agg.TotalAccurate) / float64(agg.TotalRecords)) * 100)
+	// Unverifiable records carry no accuracy signal, so they are left out of the rate.
+	if verifiable := agg.TotalRecords - agg.TotalUnverifiable; verifiable > 0 {
+		agg.AvgAccuracyRate = int((float64(agg.TotalAccurate) / float64(verifiable)) * 100)
 	}
 
 	// 2. Fetch last 10 sessions for trend chart
 	rows, err := r.db.Query(
-		`SELECT id, user_id, total_count, accurate_count, fairly_count, inaccurate_count, error_count, created_at
+		`SELECT id, user_id, total_count, accurate_count, fairly_count, inaccurate_count, error_count, unverifiable_count, created_at
 		 FROM comparison_sessions
 		 WHERE user_id = $1
 		 ORDER BY created_at DESC
@@ -118,7 +121,7 @@ func (r *HistoryRepository) GetAnalytics(userID int) (*domain.AnalyticsData, err
 	for rows.Next() {
 		var s domain.ComparisonSession
 		if err := rows.Scan(
-			&s.ID, &s.UserID, &s.TotalCount, &s.AccurateCount, &s.FairlyCount, &s.InaccurateCount, &s.ErrorCount, &s.CreatedAt,
+			&s.ID, &s.UserID, &s.TotalCount, &s.AccurateCount, &s.FairlyCount, &s.InaccurateCount, &s.ErrorCount, &s.UnverifiableCount, &s.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("history repo scan recent sessions: %w", err)
 		}
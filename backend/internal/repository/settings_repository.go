@@ -12,6 +12,8 @@ import (
 type SettingsRepository interface {
 	GetByUserID(userID int) (*domain.UserSettings, error)
 	Upsert(userID int, mapsKey, geoapifyKey, positionStackKey string) error
+	UpsertQualityPolicy(userID int, skipPremiumLowQuality bool, minPremiumQualityScore int) error
+	UpsertRetentionPolicy(userID int, itemRetentionDays, batchRetentionDays int) error
 }
 
 type postgresSettingsRepository struct {
@@ -26,14 +28,20 @@ func NewSettingsRepository(db *sql.DB) SettingsRepository {
 // GetByUserID returns the settings for a given user, or default empty settings if none exist.
 func (r *postgresSettingsRepository) GetByUserID(userID int) (*domain.UserSettings, error) {
 	s := &domain.UserSettings{
-		UserID:    userID,
-		MapsKey:   "",
-		UpdatedAt: time.Now(),
+		UserID:                 userID,
+		MapsKey:                "",
+		UpdatedAt:              time.Now(),
+		MinPremiumQualityScore: domain.DefaultMinPremiumQualityScore,
 	}
 
 	err := r.db.QueryRow(
-		`SELECT user_id, maps_key, geoapify_key, position_stack_key, updated_at FROM user_settings WHERE user_id = $1`, userID,
-	).Scan(&s.UserID, &s.MapsKey, &s.GeoapifyKey, &s.PositionStackKey, &s.UpdatedAt)
+		`SELECT user_id, maps_key, geoapify_key, position_stack_key, updated_at,
+		        skip_premium_low_quality, min_premium_quality_score,
+		        item_retention_days, batch_retention_days
+		 FROM user_settings WHERE user_id = $1`, userID,
+	).Scan(&s.UserID, &s.MapsKey, &s.GeoapifyKey, &s.PositionStackKey, &s.UpdatedAt,
+		&s.SkipPremiumLowQuality, &s.MinPremiumQualityScore,
+		&s.ItemRetentionDays, &s.BatchRetentionDays)
 
 	if err == sql.ErrNoRows {
 		return s, nil // return defaults — not an error
@@ -61,3 +69,37 @@ func (r *postgresSettingsRepository) Upsert(userID int, mapsKey, geoapifyKey, po
 	}
 	return nil
 }
+
+// UpsertQualityPolicy stores the address quality policy without touching API keys.
+func (r *postgresSettingsRepository) UpsertQualityPolicy(userID int, skipPremiumLowQuality bool, minPremiumQualityScore int) error {
+	_, err := r.db.Exec(
+		`INSERT INTO user_settings (user_id, skip_premium_low_quality, min_premium_quality_score, updated_at)
+		 VALUES ($1, $2, $3, NOW())
+		 ON CONFLICT (user_id) DO UPDATE
+		   SET skip_premium_low_quality = EXCLUDED.skip_premium_low_quality,
+		       min_premium_quality_score = EXCLUDED.min_premium_quality_score,
+		       updated_at = NOW()`,
+		userID, skipPremiumLowQuality, minPremiumQualityScore,
+	)
+	if err != nil {
+		return fmt.Errorf("settings repository UpsertQualityPolicy: %w", err)
+	}
+	return nil
+}
+
+// UpsertRetentionPolicy stores the data retention policy without touching other settings.
+func (r *postgresSettingsRepository) UpsertRetentionPolicy(userID int, itemRetentionDays, batchRetentionDays int) error {
+	_, err := r.db.Exec(
+		`INSERT INTO user_settings (user_id, item_retention_days, batch_retention_days, updated_at)
+		 VALUES ($1, $2, $3, NOW())
+		 ON CONFLICT (user_id) DO UPDATE
+		   SET item_retention_days = EXCLUDED.item_retention_days,
+		       batch_retention_days = EXCLUDED.batch_retention_days,
+		       updated_at = NOW()`,
+		userID, itemRetentionDays, batchRetentionDays,
+	)
+	if err != nil {
+		return fmt.Errorf("settings repository UpsertRetentionPolicy: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+type teamRepository struct {
+	db *sql.DB
+}
+
+func NewTeamRepository(db *sql.DB) domain.TeamRepository {
+	return &teamRepository{db: db}
+}
+
+func (r *teamRepository) CreateTeam(ctx context.Context, team *domain.Team) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO teams (name, owner_id) VALUES ($1, $2) RETURNING id, created_at`,
+		team.Name, team.OwnerID,
+	).Scan(&team.ID, &team.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO team_members (team_id, user_id) VALUES ($1, $2)`, team.ID, team.OwnerID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *teamRepository) GetTeam(ctx context.Context, id int64) (*domain.Team, error) {
+	t := &domain.Team{}
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, name, owner_id, created_at FROM teams WHERE id = $1`, id,
+	).Scan(&t.ID, &t.Name, &t.OwnerID, &t.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT m.user_id, u.name, u.email, m.added_at
+		FROM team_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.team_id = $1
+		ORDER BY m.added_at, m.user_id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m domain.TeamMember
+		if err := rows.Scan(&m.UserID, &m.Name, &m.Email, &m.AddedAt); err != nil {
+			return nil, err
+		}
+		t.Members = append(t.Members, m)
+	}
+	return t, rows.Err()
+}
+
+func (r *teamRepository) ListTeamsByMember(ctx context.Context, userID int64) ([]domain.Team, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT t.id, t.name, t.owner_id, t.created_at
+		FROM teams t
+		JOIN team_members m ON m.team_id = t.id
+		WHERE m.user_id = $1
+		ORDER BY t.name, t.id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var teams []domain.Team
+	for rows.Next() {
+		var t domain.Team
+		if err := rows.Scan(&t.ID, &t.Name, &t.OwnerID, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		teams = append(teams, t)
+	}
+	return teams, rows.Err()
+}
+
+// DeleteTeam also removes the team's members and batch shares.
+func (r *teamRepository) DeleteTeam(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM teams WHERE id = $1`, id)
+	return err
+}
+
+func (r *teamRepository) AddTeamMember(ctx context.Context, teamID, userID int64) error {
+	var exists bool
+	if err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, userID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrShareTargetNotFound
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO team_members (team_id, user_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`, teamID, userID)
+	return err
+}
+
+func (r *teamRepository) RemoveTeamMember(ctx context.Context, teamID, userID int64) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM team_members WHERE team_id = $1 AND user_id = $2`, teamID, userID)
+	return err
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"regexp"
+	"strings"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+// Component patterns for free-text Indonesian addresses. Each captures the
+// value up to the next comma so "Jl. Sudirman No. 5, Kel. Karet" yields
+// street "Sudirman" rather than the whole line.
+var (
+	streetRe      = regexp.MustCompile(`(?i)\b(?:jl|jln|jalan|gg|gang|lorong|komplek|komp|perum|perumahan)\b\.?\s*([^,]+)`)
+	houseNumberRe = regexp.MustCompile(`(?i)\b(?:no|nomor|nomer|kav|blok)\b\.?\s*([0-9]+[a-z]?(?:[-/][0-9a-z]+)?|[a-z][0-9]+)`)
+	rtRwRe        = regexp.MustCompile(`(?i)\brt\b\.?\s*0*([0-9]{1,3})(?:\s*/\s*|\s+)(?:rw\b\.?\s*)?0*([0-9]{1,3})`)
+	villageRe     = regexp.MustCompile(`(?i)\b(?:kel|kelurahan|desa|ds)\b\.?\s*([^,]+)`)
+	districtRe    = regexp.MustCompile(`(?i)\b(?:kec|kecamatan)\b\.?\s*([^,]+)`)
+	cityRe        = regexp.MustCompile(`(?i)\b(?:kota|kab|kabupaten)\b\.?\s*([^,]+)`)
+	postalCodeRe  = regexp.MustCompile(`\b[1-9][0-9]{4}\b`)
+
+	// streetNumberSuffixRe strips a trailing "No. 5" from a captured street.
+	streetNumberSuffixRe = regexp.MustCompile(`(?i)\s+(?:no|nomor|nomer|kav|blok|rt)\b.*$`)
+
+	// landmarkRe matches directions relative to a landmark ("depan masjid",
+	// "rumah pak RT") that couriers understand but geocoders cannot resolve.
+	landmarkRe = regexp.MustCompile(`(?i)\b(?:depan|dpn|belakang|blkg|samping|sebelah|sblh|seberang|dekat|pojok|ujung|rumah (?:pak|bu|bpk|ibu)|masjid|musholl?a|gereja|warung|pos ronda|pangkalan)\b`)
+)
+
+// wellKnownCities are recognised without a "Kota"/"Kab." prefix.
+var wellKnownCities = []string{
+	"jakarta", "surabaya", "bandung", "medan", "semarang", "makassar", "palembang",
+	"tangerang", "bekasi", "depok", "bogor", "yogyakarta", "malang", "denpasar", "batam",
+}
+
+const (
+	minAddressLength  = 12
+	goodAddressLength = 25
+
+	// landmarkOnlyMaxScore caps addresses that are only directions to a landmark.
+	landmarkOnlyMaxScore = 15
+)
+
+// ScoreAddress estimates how likely an address is to geocode precisely. It
+// is a cheap, offline heuristic meant to run on every uploaded row.
+//
+// Points: street 30, house number 15, village/district 10, city 10,
+// postal code 15, RT/RW 5, length up to 15.
+func ScoreAddress(address string) domain.AddressQuality {
+	address = strings.TrimSpace(address)
+	q := domain.AddressQuality{Flags: []domain.AddressQualityFlag{}}
+	c := &q.Components
+
+	if m := streetRe.FindStringSubmatch(address); m != nil {
+		c.Street = strings.TrimSpace(streetNumberSuffixRe.ReplaceAllString(m[1], ""))
+	}
+	if m := houseNumberRe.FindStringSubmatch(address); m != nil {
+		c.HouseNumber = m[1]
+	}
+	if m := rtRwRe.FindStringSubmatch(address); m != nil {
+		c.RTRW = m[1] + "/" + m[2]
+	}
+	if m := villageRe.FindStringSubmatch(address); m != nil {
+		c.Village = strings.TrimSpace(m[1])
+	}
+	if m := districtRe.FindStringSubmatch(address); m != nil {
+		c.District = strings.TrimSpace(m[1])
+	}
+	if m := cityRe.FindStringSubmatch(address); m != nil {
+		c.City = strings.TrimSpace(postalCodeRe.ReplaceAllString(m[1], ""))
+	} else {
+		lower := strings.ToLower(address)
+		for _, city := range wellKnownCities {
+			if strings.Contains(lower, city) {
+				c.City = city
+				break
+			}
+		}
+	}
+	if m := postalCodeRe.FindString(address); m != "" {
+		c.PostalCode = m
+	}
+
+	if c.Street != "" {
+		q.Score += 30
+	} else {
+		q.Flags = append(q.Flags, domain.AddressFlagMissingStreet)
+	}
+	if c.HouseNumber != "" {
+		q.Score += 15
+	} else {
+		q.Flags = append(q.Flags, domain.AddressFlagMissingHouseNumber)
+	}
+	if c.Village != "" || c.District != "" {
+		q.Score += 10
+	}
+	if c.City != "" {
+		q.Score += 10
+	}
+	if c.Village == "" && c.District == "" && c.City == "" {
+		q.Flags = append(q.Flags, domain.AddressFlagMissingAdminArea)
+	}
+	if c.PostalCode != "" {
+		q.Score += 15
+	} else {
+		q.Flags = append(q.Flags, domain.AddressFlagMissingPostalCode)
+	}
+	if c.RTRW != "" {
+		q.Score += 5
+	}
+
+	switch n := len([]rune(address)); {
+	case n >= goodAddressLength:
+		q.Score += 15
+	case n >= minAddressLength:
+		q.Score += 8
+	default:
+		q.Flags = append(q.Flags, domain.AddressFlagTooShort)
+	}
+
+	if c.Street == "" && c.HouseNumber == "" && landmarkRe.MatchString(address) {
+		q.Flags = append(q.Flags, domain.AddressFlagLandmarkOnly)
+		if q.Score > landmarkOnlyMaxScore {
+			q.Score = landmarkOnlyMaxScore
+		}
+	}
+
+	return q
+}
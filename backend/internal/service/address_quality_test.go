@@ -0,0 +1,83 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+func TestScoreAddress(t *testing.T) {
+	tests := []struct {
+		name      string
+		address   string
+		minScore  int
+		maxScore  int
+		wantFlags []domain.AddressQualityFlag
+		noFlags   []domain.AddressQualityFlag
+	}{
+		{
+			name:     "complete address",
+			address:  "Jl. Jend. Sudirman No. 5 RT 003/RW 002, Kel. Karet, Kec. Setiabudi, Kota Jakarta Selatan 12920",
+			minScore: 90, maxScore: 100,
+			noFlags: []domain.AddressQualityFlag{domain.AddressFlagMissingStreet, domain.AddressFlagMissingPostalCode, domain.AddressFlagLandmarkOnly},
+		},
+		{
+			name:     "street without number or postcode",
+			address:  "Jalan Tunjungan, Surabaya",
+			minScore: 40, maxScore: 70,
+			wantFlags: []domain.AddressQualityFlag{domain.AddressFlagMissingHouseNumber, domain.AddressFlagMissingPostalCode},
+		},
+		{
+			name:     "landmark only",
+			address:  "depan masjid al ikhlas",
+			minScore: 0, maxScore: 15,
+			wantFlags: []domain.AddressQualityFlag{domain.AddressFlagLandmarkOnly, domain.AddressFlagMissingStreet},
+		},
+		{
+			name:     "rumah pak RT",
+			address:  "rumah pak RT",
+			minScore: 0, maxScore: 15,
+			wantFlags: []domain.AddressQualityFlag{domain.AddressFlagLandmarkOnly},
+		},
+		{
+			name:     "too short",
+			address:  "Bogor",
+			minScore: 0, maxScore: 20,
+			wantFlags: []domain.AddressQualityFlag{domain.AddressFlagTooShort, domain.AddressFlagMissingStreet},
+		},
+		{
+			name:     "empty",
+			address:  "",
+			minScore: 0, maxScore: 0,
+			wantFlags: []domain.AddressQualityFlag{domain.AddressFlagTooShort, domain.AddressFlagMissingAdminArea},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := ScoreAddress(tt.address)
+			assert.GreaterOrEqual(t, q.Score, tt.minScore)
+			assert.LessOrEqual(t, q.Score, tt.maxScore)
+			for _, f := range tt.wantFlags {
+				assert.Contains(t, q.Flags, f)
+			}
+			for _, f := range tt.noFlags {
+				assert.NotContains(t, q.Flags, f)
+			}
+		})
+	}
+}
+
+func TestScoreAddress_Components(t *testing.T) {
+	q := ScoreAddress("Jl. Jend. Sudirman No. 5 RT 003/RW 002, Kel. Karet, Kec. Setiabudi, Kota Jakarta Selatan 12920")
+
+	assert.Equal(t, "Jend. Sudirman", q.Components.Street)
+	assert.Equal(t, "5", q.Components.HouseNumber)
+	assert.Equal(t, "3/2", q.Components.RTRW)
+	assert.Equal(t, "Karet", q.Components.Village)
+	assert.Equal(t, "Setiabudi", q.Components.District)
+	assert.Equal(t, "Jakarta Selatan", q.Components.City)
+	assert.Equal(t, "12920", q.Components.PostalCode)
+}
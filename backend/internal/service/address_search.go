@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+const (
+	defaultAddressSearchItems = 100
+	maxAddressSearchItems     = 500
+	maxAddressSearchCache     = 20
+	minAddressQueryLength     = 3
+	maxAddressQueryLength     = 200
+)
+
+// ErrInvalidSearch is returned for a query that is too short or too long or
+// an out-of-range limit.
+var ErrInvalidSearch = errors.New("invalid search")
+
+type addressSearchService struct {
+	repo domain.AddressSearchRepository
+}
+
+func NewAddressSearchService(repo domain.AddressSearchRepository) domain.AddressSearchService {
+	return &addressSearchService{repo: repo}
+}
+
+// SearchAddresses returns up to limit matching items (default 100) grouped by
+// batch, and the best matching geocode cache entries.
+func (s *addressSearchService) SearchAddresses(ctx context.Context, userID int64, isAdmin bool, query string, limit int) (*domain.AddressSearchResult, error) {
+	query = strings.Join(strings.Fields(query), " ")
+	if n := utf8.RuneCountInString(query); n < minAddressQueryLength || n > maxAddressQueryLength {
+		return nil, fmt.Errorf("%w: query must be %d to %d characters", ErrInvalidSearch, minAddressQueryLength, maxAddressQueryLength)
+	}
+	if limit == 0 {
+		limit = defaultAddressSearchItems
+	}
+	if limit < 0 || limit > maxAddressSearchItems {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidSearch, maxAddressSearchItems)
+	}
+
+	// One extra item tells whether the results were cut off.
+	items, err := s.repo.SearchBatchItems(ctx, userID, query, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	cached, err := s.repo.SearchGeocodeCache(ctx, userID, isAdmin, query, maxAddressSearchCache)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.AddressSearchResult{
+		Query:   query,
+		Batches: []domain.AddressSearchBatch{},
+		Cache:   []domain.CachedAddressMatch{},
+	}
+	if len(items) > limit {
+		items = items[:limit]
+		result.Truncated = true
+	}
+
+	terms := searchTerms(query)
+	// Items arrive best first, so batches end up ordered by their best match.
+	index := make(map[uuid.UUID]int)
+	for _, m := range items {
+		m.RecipientHighlight = highlightTerms(m.RecipientName, terms)
+		m.AddressHighlight = highlightTerms(m.SystemAddress, terms)
+		i, ok := index[m.BatchID]
+		if !ok {
+			i = len(result.Batches)
+			index[m.BatchID] = i
+			result.Batches = append(result.Batches, domain.AddressSearchBatch{
+				BatchID:   m.BatchID,
+				BatchName: m.BatchName,
+				CreatedAt: m.BatchCreatedAt,
+			})
+		}
+		result.Batches[i].Matches = append(result.Batches[i].Matches, m)
+	}
+	for _, c := range cached {
+		c.Highlight = highlightTerms(c.OriginalAddress, terms)
+		result.Cache = append(result.Cache, c)
+	}
+	return result, nil
+}
+
+// searchTerms lower-cases the words of query, dropping punctuation.
+func searchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// highlightTerms splits text into segments, marking the words that match a
+// term: equal, starting with a term of three or more characters, or within
+// a small edit distance of a longer term ("keboon" ~ "kebon"). Matched words
+// and the separators between them form one segment.
+func highlightTerms(text string, terms []string) []domain.HighlightSegment {
+	type token struct {
+		text        string
+		word, match bool
+	}
+	isWord := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+
+	var tokens []token
+	for rest := text; rest != ""; {
+		r, _ := utf8.DecodeRuneInString(rest)
+		word := isWord(r)
+		end := strings.IndexFunc(rest, func(c rune) bool { return isWord(c) != word })
+		if end < 0 {
+			end = len(rest)
+		}
+		t := token{text: rest[:end], word: word}
+		t.match = word && wordMatchesTerm(strings.ToLower(t.text), terms)
+		tokens = append(tokens, t)
+		rest = rest[end:]
+	}
+
+	segments := []domain.HighlightSegment{}
+	for i, t := range tokens {
+		match := t.match || (!t.word && i > 0 && i+1 < len(tokens) && tokens[i-1].match && tokens[i+1].match)
+		if n := len(segments); n > 0 && segments[n-1].Match == match {
+			segments[n-1].Text += t.text
+			continue
+		}
+		segments = append(segments, domain.HighlightSegment{Text: t.text, Match: match})
+	}
+	return segments
+}
+
+func wordMatchesTerm(word string, terms []string) bool {
+	for _, t := range terms {
+		switch {
+		case word == t:
+			return true
+		case utf8.RuneCountInString(t) >= 3 && strings.HasPrefix(word, t):
+			return true
+		case utf8.RuneCountInString(t) >= 4 && editDistanceWithin(word, t, maxTypos(t)):
+			return true
+		}
+	}
+	return false
+}
+
+func maxTypos(term string) int {
+	if utf8.RuneCountInString(term) >= 8 {
+		return 2
+	}
+	return 1
+}
+
+// editDistanceWithin reports whether the Levenshtein distance between a and
+// b is at most k.
+func editDistanceWithin(a, b string, k int) bool {
+	ra, rb := []rune(a), []rune(b)
+	if d := len(ra) - len(rb); d > k || -d > k {
+		return false
+	}
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if cur[j] < rowMin {
+				rowMin = cur[j]
+			}
+		}
+		if rowMin > k {
+			return false
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)] <= k
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"geoaccuracy-backend/internal/domain"
+	"geoaccuracy-backend/internal/repository"
+)
+
+func TestHighlightTerms(t *testing.T) {
+	terms := searchTerms("Jl. Keboon Jeruk 12")
+	assert.Equal(t, []domain.HighlightSegment{
+		{Text: "Jalan "},
+		{Text: "Kebon Jeruk", Match: true},
+		{Text: " No. "},
+		{Text: "12", Match: true},
+		{Text: ", Jakarta Barat"},
+	}, highlightTerms("Jalan Kebon Jeruk No. 12, Jakarta Barat", terms))
+
+	assert.Equal(t, []domain.HighlightSegment{{Text: "Budi Santoso"}}, highlightTerms("Budi Santoso", terms))
+	assert.Empty(t, highlightTerms("", terms))
+}
+
+func TestSearchAddresses_GroupsByBatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	svc := NewAddressSearchService(repository.NewAddressSearchRepository(db))
+
+	b1, b2 := uuid.New(), uuid.New()
+	now := time.Date(2026, 9, 1, 8, 0, 0, 0, time.UTC)
+	itemRow := func(batchID uuid.UUID, name, address string, score float64) []driver.Value {
+		return []driver.Value{uuid.New(), batchID, name, now, "R1", "Budi", address, "accurate", score}
+	}
+	columns := []string{"id", "batch_id", "name", "created_at", "connote", "recipient_name", "system_address", "accuracy_level", "score"}
+	mock.ExpectQuery(regexp.QuoteMeta(`$1 <% bi.system_address`)).
+		WithArgs("Kebon Jeruk 12", int64(7), 4).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(itemRow(b1, "Agustus", "Jl. Kebon Jeruk 12", 1)...).
+			AddRow(itemRow(b2, "September", "Jl Kebon Jeruk No 12", 0.9)...).
+			AddRow(itemRow(b1, "Agustus", "Kebon Jeruk Raya 12A", 0.7)...))
+	mock.ExpectQuery(regexp.QuoteMeta(`FROM geocode_cache gc`)).
+		WithArgs("Kebon Jeruk 12", int64(7), false, maxAddressSearchCache).
+		WillReturnRows(sqlmock.NewRows([]string{"original_address", "city", "province", "lat", "lng", "provider", "created_at", "score"}))
+
+	result, err := svc.SearchAddresses(context.Background(), 7, false, "  Kebon   Jeruk 12 ", 3)
+	require.NoError(t, err)
+	assert.False(t, result.Truncated)
+	require.Len(t, result.Batches, 2)
+	assert.Equal(t, b1, result.Batches[0].BatchID)
+	assert.Len(t, result.Batches[0].Matches, 2)
+	assert.Equal(t, "September", result.Batches[1].BatchName)
+	assert.Empty(t, result.Cache)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	for _, q := range []string{"", "Jl", string(make([]byte, maxAddressQueryLength+1))} {
+		_, err := svc.SearchAddresses(context.Background(), 7, false, q, 0)
+		assert.ErrorIs(t, err, ErrInvalidSearch)
+	}
+}
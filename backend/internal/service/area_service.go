@@ -16,6 +16,7 @@ type AreaService interface {
 	ListAreas(ctx context.Context) ([]domain.Area, error)
 	DeleteArea(ctx context.Context, id uuid.UUID) error
 	GetAreasContainingPoint(ctx context.Context, lat float64, lng float64) ([]domain.Area, error)
+	IsOutsideServiceAreas(ctx context.Context, lat float64, lng float64) (bool, error)
 }
 
 type areaService struct {
@@ -74,3 +75,7 @@ func (s *areaService) DeleteArea(ctx context.Context, id uuid.UUID) error {
 func (s *areaService) GetAreasContainingPoint(ctx context.Context, lat float64, lng float64) ([]domain.Area, error) {
 	return s.areaRepo.CheckPointInArea(ctx, lat, lng)
 }
+
+func (s *areaService) IsOutsideServiceAreas(ctx context.Context, lat float64, lng float64) (bool, error) {
+	return s.areaRepo.IsOutsideAllAreas(ctx, lat, lng)
+}
@@ -58,6 +58,11 @@ func (m *mockAreaRepo) CheckPointInArea(ctx context.Context, lat float64, lng fl
 	return res, args.Error(1)
 }
 
+func (m *mockAreaRepo) IsOutsideAllAreas(ctx context.Context, lat float64, lng float64) (bool, error) {
+	args := m.Called(ctx, lat, lng)
+	return args.Bool(0), args.Error(1)
+}
+
 func TestCreateArea_Success(t *testing.T) {
 	mockRepo := new(mockAreaRepo)
 	svc := NewAreaService(mockRepo)
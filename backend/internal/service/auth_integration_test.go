@@ -87,17 +87,18 @@ func TestAuthAndIsolation_MockedIntegration(t *testing.T) {
 			COALESCE(SUM(accurate_count), 0),
 			COALESCE(SUM(fairly_count), 0),
 			COALESCE(SUM(inaccurate_count), 0),
-			COALESCE(SUM(error_count), 0)
+			COALESCE(SUM(error_count), 0),
+			COALESCE(SUM(unverifiable_count), 0)
 		 FROM comparison_sessions 
 		 WHERE user_id = $1`)).
 		WithArgs(1). // Ensure user ID 1 is explicitly passed
-		WillReturnRows(sqlmock.NewRows([]string{"count", "total_records", "total_accurate", "total_fairly", "total_inaccurate", "total_error"}).
-			AddRow(1, 10, 8, 2, 0, 0))
+		WillReturnRows(sqlmock.NewRows([]string{"count", "total_records", "total_accurate", "total_fairly", "total_inaccurate", "total_error", "total_unverifiable"}).
+			AddRow(1, 10, 8, 2, 0, 0, 0))
 
 	// Also it executes the last 10 trend sessions
-	sqlMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, user_id, total_count, accurate_count, fairly_count, inaccurate_count, error_count, created_at FROM comparison_sessions WHERE user_id = $1 ORDER BY created_at DESC LIMIT 10`)).
+	sqlMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, user_id, total_count, accurate_count, fairly_count, inaccurate_count, error_count, unverifiable_count, created_at FROM comparison_sessions WHERE user_id = $1 ORDER BY created_at DESC LIMIT 10`)).
 		WithArgs(1).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "total_count", "accurate_count", "fairly_count", "inaccurate_count", "error_count", "created_at"}))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "total_count", "accurate_count", "fairly_count", "inaccurate_count", "error_count", "unverifiable_count", "created_at"}))
 
 	historyA, err := historyRepo.GetAnalytics(int(resA.User.ID))
 	require.NoError(t, err)
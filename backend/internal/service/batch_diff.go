@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/google/uuid"
+
+	"geoaccuracy-backend/internal/domain"
+	"geoaccuracy-backend/pkg/utils"
+)
+
+const (
+	// maxDiffListItems bounds each list in a BatchDiff; the summary counts all.
+	maxDiffListItems = 1000
+	// diffToleranceKm ignores float noise: distances and coordinates closer
+	// than a metre are treated as unchanged.
+	diffToleranceKm = 0.001
+)
+
+// ErrSameBatch is returned when a batch is compared with itself.
+var ErrSameBatch = errors.New("cannot compare a batch with itself")
+
+// accuracyRank orders accuracy levels from worst to best. Unverifiable and
+// missing levels rank lowest: neither says anything good about the geocode.
+var accuracyRank = map[string]int{
+	"inaccurate":      1,
+	"fairly_accurate": 2,
+	"accurate":        3,
+}
+
+func (s *batchService) DiffBatches(ctx context.Context, userID int64, baseID, targetID uuid.UUID) (*domain.BatchDiff, error) {
+	if baseID == targetID {
+		return nil, ErrSameBatch
+	}
+	if err := s.verifyBatchAccess(ctx, baseID, userID, domain.BatchPermissionViewer); err != nil {
+		return nil, err
+	}
+	if err := s.verifyBatchAccess(ctx, targetID, userID, domain.BatchPermissionViewer); err != nil {
+		return nil, err
+	}
+
+	d := newBatchDiffBuilder(baseID, targetID)
+	if err := s.batchRepo.DiffBatchItems(ctx, baseID, targetID, func(base, target *domain.BatchDiffSide) error {
+		d.add(base, target)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return d.result(), nil
+}
+
+// batchDiffBuilder accumulates a BatchDiff one connote at a time.
+type batchDiffBuilder struct {
+	diff domain.BatchDiff
+
+	distancePairs              int
+	baseDistSum, targetDistSum float64
+}
+
+func newBatchDiffBuilder(baseID, targetID uuid.UUID) *batchDiffBuilder {
+	return &batchDiffBuilder{diff: domain.BatchDiff{
+		BaseBatchID:   baseID,
+		TargetBatchID: targetID,
+		Summary: domain.BatchDiffSummary{
+			BaseAccuracy:   map[string]int{},
+			TargetAccuracy: map[string]int{},
+		},
+		Changed:      []domain.BatchItemChange{},
+		OnlyInBase:   []domain.BatchDiffSide{},
+		OnlyInTarget: []domain.BatchDiffSide{},
+	}}
+}
+
+func (b *batchDiffBuilder) add(base, target *domain.BatchDiffSide) {
+	sum := &b.diff.Summary
+	switch {
+	case target == nil:
+		sum.OnlyInBase++
+		b.diff.OnlyInBase = b.appendSide(b.diff.OnlyInBase, *base)
+		return
+	case base == nil:
+		sum.OnlyInTarget++
+		b.diff.OnlyInTarget = b.appendSide(b.diff.OnlyInTarget, *target)
+		return
+	}
+
+	sum.Matched++
+	if base.AccuracyLevel != "" {
+		sum.BaseAccuracy[base.AccuracyLevel]++
+	}
+	if target.AccuracyLevel != "" {
+		sum.TargetAccuracy[target.AccuracyLevel]++
+	}
+	if base.DistanceKm != nil && target.DistanceKm != nil {
+		b.distancePairs++
+		b.baseDistSum += *base.DistanceKm
+		b.targetDistSum += *target.DistanceKm
+	}
+
+	change, ok := compareDiffSides(*base, *target)
+	if !ok {
+		sum.Unchanged++
+		return
+	}
+	sum.Changed++
+	switch change.Direction {
+	case domain.DiffImproved:
+		sum.Improved++
+	case domain.DiffRegressed:
+		sum.Regressed++
+	}
+	if len(b.diff.Changed) < maxDiffListItems {
+		b.diff.Changed = append(b.diff.Changed, change)
+	} else {
+		b.diff.Truncated = true
+	}
+}
+
+func (b *batchDiffBuilder) appendSide(list []domain.BatchDiffSide, side domain.BatchDiffSide) []domain.BatchDiffSide {
+	if len(list) >= maxDiffListItems {
+		b.diff.Truncated = true
+		return list
+	}
+	return append(list, side)
+}
+
+func (b *batchDiffBuilder) result() *domain.BatchDiff {
+	if n := float64(b.distancePairs); n > 0 {
+		baseMean, targetMean := b.baseDistSum/n, b.targetDistSum/n
+		delta := targetMean - baseMean
+		b.diff.Summary.BaseMeanDistanceKm = &baseMean
+		b.diff.Summary.TargetMeanDistanceKm = &targetMean
+		b.diff.Summary.MeanDistanceDeltaKm = &delta
+	}
+	return &b.diff
+}
+
+// compareDiffSides reports what changed between two versions of an item and
+// whether the result got better or worse. A better accuracy level wins; with
+// the same level, a shorter distance is an improvement.
+func compareDiffSides(base, target domain.BatchDiffSide) (domain.BatchItemChange, bool) {
+	change := domain.BatchItemChange{Connote: base.Connote, Base: base, Target: target, Direction: domain.DiffChanged}
+
+	if base.AccuracyLevel != target.AccuracyLevel {
+		change.Fields = append(change.Fields, domain.DiffFieldAccuracy)
+	}
+
+	if base.DistanceKm != nil && target.DistanceKm != nil {
+		delta := *target.DistanceKm - *base.DistanceKm
+		if math.Abs(delta) >= diffToleranceKm {
+			change.Fields = append(change.Fields, domain.DiffFieldDistance)
+			change.DistanceDeltaKm = &delta
+		}
+	} else if (base.DistanceKm == nil) != (target.DistanceKm == nil) {
+		change.Fields = append(change.Fields, domain.DiffFieldDistance)
+	}
+
+	baseHasCoord := base.SystemLat != nil && base.SystemLng != nil
+	targetHasCoord := target.SystemLat != nil && target.SystemLng != nil
+	if baseHasCoord && targetHasCoord {
+		shift := utils.CalculateDistance(*base.SystemLat, *base.SystemLng, *target.SystemLat, *target.SystemLng)
+		if shift >= diffToleranceKm {
+			change.Fields = append(change.Fields, domain.DiffFieldCoordinates)
+			change.CoordinateShiftKm = &shift
+		}
+	} else if baseHasCoord != targetHasCoord {
+		change.Fields = append(change.Fields, domain.DiffFieldCoordinates)
+	}
+
+	if len(change.Fields) == 0 {
+		return change, false
+	}
+
+	baseRank, targetRank := accuracyRank[base.AccuracyLevel], accuracyRank[target.AccuracyLevel]
+	switch {
+	case targetRank > baseRank:
+		change.Direction = domain.DiffImproved
+	case targetRank < baseRank:
+		change.Direction = domain.DiffRegressed
+	case change.DistanceDeltaKm != nil && *change.DistanceDeltaKm < 0:
+		change.Direction = domain.DiffImproved
+	case change.DistanceDeltaKm != nil:
+		change.Direction = domain.DiffRegressed
+	}
+	return change, true
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+type diffBatchRepo struct {
+	domain.BatchRepository
+	owner int64
+	pairs [][2]*domain.BatchDiffSide
+}
+
+func (r *diffBatchRepo) GetBatchByID(ctx context.Context, id uuid.UUID) (*domain.Batch, error) {
+	return &domain.Batch{ID: id, UserID: r.owner}, nil
+}
+
+// GetBatchPermission reports no shares, so only the owner has access.
+func (r *diffBatchRepo) GetBatchPermission(ctx context.Context, batchID uuid.UUID, userID int64) (domain.BatchPermission, error) {
+	return "", nil
+}
+
+func (r *diffBatchRepo) DiffBatchItems(ctx context.Context, baseID, targetID uuid.UUID, fn func(base, target *domain.BatchDiffSide) error) error {
+	for _, p := range r.pairs {
+		if err := fn(p[0], p[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func diffSide(connote, level string, dist, lat, lng float64) *domain.BatchDiffSide {
+	return &domain.BatchDiffSide{Connote: connote, AccuracyLevel: level, DistanceKm: &dist, SystemLat: &lat, SystemLng: &lng}
+}
+
+func TestDiffBatches(t *testing.T) {
+	repo := &diffBatchRepo{owner: 7, pairs: [][2]*domain.BatchDiffSide{
+		// Re-geocoded closer to the field point.
+		{diffSide("R1", "inaccurate", 0.8, -6.2, 106.8), diffSide("R1", "accurate", 0.02, -6.2072, 106.8)},
+		// Same level, but the distance grew.
+		{diffSide("R2", "inaccurate", 0.3, -6.2, 106.8), diffSide("R2", "inaccurate", 0.5, -6.2018, 106.8)},
+		// Identical apart from float noise.
+		{diffSide("R3", "accurate", 0.01, -6.2, 106.8), diffSide("R3", "accurate", 0.0100001, -6.2, 106.8)},
+		{diffSide("R4", "accurate", 0.01, -6.2, 106.8), nil},
+		{nil, diffSide("R5", "accurate", 0.01, -6.2, 106.8)},
+	}}
+	svc := &batchService{batchRepo: repo}
+
+	diff, err := svc.DiffBatches(context.Background(), 7, uuid.New(), uuid.New())
+	require.NoError(t, err)
+
+	sum := diff.Summary
+	assert.Equal(t, 3, sum.Matched)
+	assert.Equal(t, 1, sum.OnlyInBase)
+	assert.Equal(t, 1, sum.OnlyInTarget)
+	assert.Equal(t, 1, sum.Unchanged)
+	assert.Equal(t, 2, sum.Changed)
+	assert.Equal(t, 1, sum.Improved)
+	assert.Equal(t, 1, sum.Regressed)
+	assert.Equal(t, map[string]int{"inaccurate": 2, "accurate": 1}, sum.BaseAccuracy)
+	require.NotNil(t, sum.MeanDistanceDeltaKm)
+	assert.InDelta(t, (0.02+0.5+0.0100001-0.8-0.3-0.01)/3, *sum.MeanDistanceDeltaKm, 1e-9)
+
+	require.Len(t, diff.Changed, 2)
+	r1 := diff.Changed[0]
+	assert.Equal(t, domain.DiffImproved, r1.Direction)
+	assert.Equal(t, []string{domain.DiffFieldAccuracy, domain.DiffFieldDistance, domain.DiffFieldCoordinates}, r1.Fields)
+	require.NotNil(t, r1.CoordinateShiftKm)
+	assert.InDelta(t, 0.8, *r1.CoordinateShiftKm, 0.01)
+	assert.Equal(t, domain.DiffRegressed, diff.Changed[1].Direction)
+
+	assert.Equal(t, "R4", diff.OnlyInBase[0].Connote)
+	assert.Equal(t, "R5", diff.OnlyInTarget[0].Connote)
+	assert.False(t, diff.Truncated)
+}
+
+func TestDiffBatches_RequiresOwnershipOfBoth(t *testing.T) {
+	svc := &batchService{batchRepo: &diffBatchRepo{owner: 8}}
+	_, err := svc.DiffBatches(context.Background(), 7, uuid.New(), uuid.New())
+	assert.Equal(t, errAccessDenied, err)
+
+	id := uuid.New()
+	_, err = svc.DiffBatches(context.Background(), 8, id, id)
+	assert.ErrorIs(t, err, ErrSameBatch)
+}
@@ -0,0 +1,435 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xuri/excelize/v2"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+// Export formats and locales accepted by ExportBatch.
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+
+	ExportLocaleID = "id"
+	ExportLocaleEN = "en"
+)
+
+// ErrInvalidExport is returned for an unknown format, locale or column key.
+var ErrInvalidExport = errors.New("invalid export options")
+
+// exportColumn is one selectable column of a batch export. value returns a
+// string, a float64, an int or nil for an empty cell.
+type exportColumn struct {
+	key      string
+	labelEN  string
+	labelID  string
+	decimals int // for float64 values
+	value    func(it *domain.BatchItem) interface{}
+}
+
+func floatOrNil(v *float64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+var exportColumns = []exportColumn{
+	{key: "connote", labelEN: "Connote", labelID: "No. Resi",
+		value: func(it *domain.BatchItem) interface{} { return it.Connote }},
+	{key: "recipient_name", labelEN: "Recipient", labelID: "Nama Penerima",
+		value: func(it *domain.BatchItem) interface{} { return it.RecipientName }},
+	{key: "system_address", labelEN: "System Address", labelID: "Alamat Sistem",
+		value: func(it *domain.BatchItem) interface{} { return it.SystemAddress }},
+	{key: "courier_id", labelEN: "Courier", labelID: "Kurir",
+		value: func(it *domain.BatchItem) interface{} { return it.CourierID }},
+	{key: "system_lat", labelEN: "System Lat", labelID: "Lat Sistem", decimals: 6,
+		value: func(it *domain.BatchItem) interface{} { return floatOrNil(it.SystemLat) }},
+	{key: "system_lng", labelEN: "System Lng", labelID: "Lng Sistem", decimals: 6,
+		value: func(it *domain.BatchItem) interface{} { return floatOrNil(it.SystemLng) }},
+	{key: "field_lat", labelEN: "Field Lat", labelID: "Lat Lapangan", decimals: 6,
+		value: func(it *domain.BatchItem) interface{} { return floatOrNil(it.FieldLat) }},
+	{key: "field_lng", labelEN: "Field Lng", labelID: "Lng Lapangan", decimals: 6,
+		value: func(it *domain.BatchItem) interface{} { return floatOrNil(it.FieldLng) }},
+	{key: "distance_km", labelEN: "Distance (km)", labelID: "Jarak (km)", decimals: 3,
+		value: func(it *domain.BatchItem) interface{} { return floatOrNil(it.DistanceKm) }},
+	{key: "distance_m", labelEN: "Distance (m)", labelID: "Jarak (m)", decimals: 1,
+		value: func(it *domain.BatchItem) interface{} {
+			if it.DistanceKm == nil {
+				return nil
+			}
+			return *it.DistanceKm * 1000
+		}},
+	{key: "accuracy_level", labelEN: "Accuracy", labelID: "Akurasi",
+		value: func(it *domain.BatchItem) interface{} { return it.AccuracyLevel }},
+	{key: "reason_code", labelEN: "Reason Code", labelID: "Kode Alasan",
+		value: func(it *domain.BatchItem) interface{} { return string(it.ReasonCode) }},
+	{key: "explanation", labelEN: "Explanation", labelID: "Keterangan",
+		value: nil}, // locale dependent, see batchExport.value
+	{key: "geocode_status", labelEN: "Geocode Status", labelID: "Status Geocode",
+		value: func(it *domain.BatchItem) interface{} { return it.GeocodeStatus }},
+	{key: "error", labelEN: "Error", labelID: "Galat",
+		value: func(it *domain.BatchItem) interface{} { return it.Error }},
+	{key: "address_quality_score", labelEN: "Address Quality", labelID: "Kualitas Alamat",
+		value: func(it *domain.BatchItem) interface{} {
+			if it.AddressQualityScore == nil {
+				return nil
+			}
+			return *it.AddressQualityScore
+		}},
+	{key: "provider", labelEN: "Provider", labelID: "Provider",
+		value: func(it *domain.BatchItem) interface{} { return it.Provider }},
+	{key: "attempt_count", labelEN: "Attempts", labelID: "Jumlah Percobaan",
+		value: func(it *domain.BatchItem) interface{} { return it.AttemptCount }},
+	{key: "cache_status", labelEN: "Cache Status", labelID: "Status Cache",
+		value: func(it *domain.BatchItem) interface{} { return it.CacheStatus }},
+	{key: "geocoded_city", labelEN: "Geocoded City", labelID: "Kota Geocode",
+		value: func(it *domain.BatchItem) interface{} { return it.GeocodedCity }},
+	{key: "geocoded_province", labelEN: "Geocoded Province", labelID: "Provinsi Geocode",
+		value: func(it *domain.BatchItem) interface{} { return it.GeocodedProvince }},
+	{key: "processed_at", labelEN: "Processed At", labelID: "Diproses",
+		value: func(it *domain.BatchItem) interface{} {
+			if it.ProcessedAt == nil {
+				return nil
+			}
+			return it.ProcessedAt.UTC().Format(time.RFC3339)
+		}},
+	{key: "review_status", labelEN: "Review Status", labelID: "Status Tinjauan",
+		value: func(it *domain.BatchItem) interface{} { return string(it.ReviewStatus) }},
+	{key: "review_comment", labelEN: "Review Comment", labelID: "Catatan Tinjauan",
+		value: func(it *domain.BatchItem) interface{} { return it.ReviewComment }},
+	{key: "corrected_lat", labelEN: "Corrected Lat", labelID: "Lat Koreksi", decimals: 6,
+		value: func(it *domain.BatchItem) interface{} { return floatOrNil(it.CorrectedLat) }},
+	{key: "corrected_lng", labelEN: "Corrected Lng", labelID: "Lng Koreksi", decimals: 6,
+		value: func(it *domain.BatchItem) interface{} { return floatOrNil(it.CorrectedLng) }},
+	{key: "updated_at", labelEN: "Updated At", labelID: "Diperbarui",
+		value: func(it *domain.BatchItem) interface{} { return it.UpdatedAt.UTC().Format(time.RFC3339) }},
+}
+
+// defaultExportColumns is used when the request names no columns.
+var defaultExportColumns = []string{
+	"connote", "recipient_name", "system_address", "courier_id",
+	"system_lat", "system_lng", "field_lat", "field_lng",
+	"distance_m", "accuracy_level", "reason_code", "explanation",
+}
+
+// batchExport holds the validated options of one export.
+type batchExport struct {
+	format  string
+	locale  string
+	columns []exportColumn
+}
+
+// ParseExportColumns splits a comma-separated column list from a query string.
+func ParseExportColumns(raw string) []string {
+	var cols []string
+	for _, c := range strings.Split(raw, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			cols = append(cols, c)
+		}
+	}
+	return cols
+}
+
+func newBatchExport(opts domain.ExportOptions) (*batchExport, error) {
+	e := &batchExport{format: strings.ToLower(opts.Format), locale: strings.ToLower(opts.Locale)}
+	if e.format == "" {
+		e.format = ExportFormatCSV
+	}
+	if e.format != ExportFormatCSV && e.format != ExportFormatXLSX && !isGeoExportFormat(e.format) {
+		return nil, fmt.Errorf("%w: unknown format %q", ErrInvalidExport, opts.Format)
+	}
+	if e.locale == "" {
+		e.locale = ExportLocaleID
+	}
+	if e.locale != ExportLocaleID && e.locale != ExportLocaleEN {
+		return nil, fmt.Errorf("%w: unknown locale %q", ErrInvalidExport, opts.Locale)
+	}
+
+	keys := opts.Columns
+	if len(keys) == 0 {
+		keys = defaultExportColumns
+	}
+	byKey := make(map[string]exportColumn, len(exportColumns))
+	for _, c := range exportColumns {
+		byKey[c.key] = c
+	}
+	for _, k := range keys {
+		c, ok := byKey[k]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidExport, k)
+		}
+		e.columns = append(e.columns, c)
+	}
+	return e, nil
+}
+
+func (e *batchExport) header() []string {
+	h := make([]string, len(e.columns))
+	for i, c := range e.columns {
+		if e.locale == ExportLocaleID {
+			h[i] = c.labelID
+		} else {
+			h[i] = c.labelEN
+		}
+	}
+	return h
+}
+
+func (e *batchExport) value(c exportColumn, it *domain.BatchItem) interface{} {
+	if c.key == "explanation" {
+		if it.ReasonCode == "" {
+			return ""
+		}
+		if e.locale == ExportLocaleID {
+			return it.ReasonCode.Explain().ID
+		}
+		return it.ReasonCode.Explain().EN
+	}
+	return c.value(it)
+}
+
+// formatCSV renders a cell for CSV. The Indonesian locale uses a decimal
+// comma, which is why its CSV delimiter is ';'.
+func (e *batchExport) formatCSV(c exportColumn, v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		s := strconv.FormatFloat(v, 'f', c.decimals, 64)
+		if e.locale == ExportLocaleID {
+			s = strings.Replace(s, ".", ",", 1)
+		}
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// exportSummary accumulates totals while items stream past.
+type exportSummary struct {
+	total         int
+	withDistance  int
+	distanceSumKm float64
+	byAccuracy    map[string]int
+	byReason      map[string]int
+}
+
+func newExportSummary() *exportSummary {
+	return &exportSummary{byAccuracy: map[string]int{}, byReason: map[string]int{}}
+}
+
+func (s *exportSummary) add(it *domain.BatchItem) {
+	s.total++
+	if it.DistanceKm != nil {
+		s.withDistance++
+		s.distanceSumKm += *it.DistanceKm
+	}
+	level := it.AccuracyLevel
+	if level == "" {
+		level = "pending"
+	}
+	s.byAccuracy[level]++
+	if it.ReasonCode != "" {
+		s.byReason[string(it.ReasonCode)]++
+	}
+}
+
+// ExportBatch streams every item of the batch to w as CSV, XLSX or one of the
+// geospatial formats. Options and ownership are checked before anything is
+// written.
+func (s *batchService) ExportBatch(ctx context.Context, userID int64, batchID uuid.UUID, w io.Writer, opts domain.ExportOptions) error {
+	exp, err := newBatchExport(opts)
+	if err != nil {
+		return err
+	}
+	if err := s.verifyBatchAccess(ctx, batchID, userID, domain.BatchPermissionViewer); err != nil {
+		return err
+	}
+	switch {
+	case exp.format == ExportFormatXLSX:
+		return s.exportXLSX(ctx, batchID, w, exp)
+	case isGeoExportFormat(exp.format):
+		return s.exportGeo(ctx, batchID, w, exp.format)
+	}
+	return s.exportCSV(ctx, batchID, w, exp)
+}
+
+func (s *batchService) exportCSV(ctx context.Context, batchID uuid.UUID, w io.Writer, exp *batchExport) error {
+	bw := bufio.NewWriter(w)
+	// The BOM makes Excel open the file as UTF-8 instead of the ANSI code page.
+	if _, err := bw.WriteString("\ufeff"); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(bw)
+	if exp.locale == ExportLocaleID {
+		cw.Comma = ';'
+	}
+	if err := cw.Write(exp.header()); err != nil {
+		return err
+	}
+
+	record := make([]string, len(exp.columns))
+	err := s.batchRepo.StreamBatchItems(ctx, batchID, func(it *domain.BatchItem) error {
+		for i, c := range exp.columns {
+			record[i] = exp.formatCSV(c, exp.value(c, it))
+		}
+		return cw.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// exportXLSX writes the items through excelize's stream writer, which spills
+// rows to a temp file instead of keeping the sheet in memory, then adds a
+// summary sheet built from totals gathered on the way.
+func (s *batchService) exportXLSX(ctx context.Context, batchID uuid.UUID, w io.Writer, exp *batchExport) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	resultsSheet, summarySheet := "Results", "Summary"
+	if exp.locale == ExportLocaleID {
+		resultsSheet, summarySheet = "Hasil", "Ringkasan"
+	}
+	if err := f.SetSheetName("Sheet1", resultsSheet); err != nil {
+		return err
+	}
+
+	headerStyle, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+	// Cells keep numeric values; the number format only controls display, so
+	// Excel applies the viewer's own decimal separator.
+	decimalStyles := map[int]int{}
+	for _, c := range exp.columns {
+		if c.decimals == 0 || decimalStyles[c.decimals] != 0 {
+			continue
+		}
+		format := "0." + strings.Repeat("0", c.decimals)
+		id, err := f.NewStyle(&excelize.Style{CustomNumFmt: &format})
+		if err != nil {
+			return err
+		}
+		decimalStyles[c.decimals] = id
+	}
+
+	sw, err := f.NewStreamWriter(resultsSheet)
+	if err != nil {
+		return err
+	}
+	header := exp.header()
+	headerRow := make([]interface{}, len(header))
+	for i, h := range header {
+		headerRow[i] = excelize.Cell{StyleID: headerStyle, Value: h}
+	}
+	if err := sw.SetRow("A1", headerRow, excelize.RowOpts{Height: 18}); err != nil {
+		return err
+	}
+
+	summary := newExportSummary()
+	rowNum := 1
+	row := make([]interface{}, len(exp.columns))
+	err = s.batchRepo.StreamBatchItems(ctx, batchID, func(it *domain.BatchItem) error {
+		summary.add(it)
+		for i, c := range exp.columns {
+			v := exp.value(c, it)
+			if num, ok := v.(float64); ok {
+				row[i] = excelize.Cell{StyleID: decimalStyles[c.decimals], Value: num}
+			} else {
+				row[i] = v
+			}
+		}
+		rowNum++
+		cell, err := excelize.CoordinatesToCellName(1, rowNum)
+		if err != nil {
+			return err
+		}
+		return sw.SetRow(cell, row)
+	})
+	if err != nil {
+		return err
+	}
+	if err := sw.Flush(); err != nil {
+		return err
+	}
+
+	if err := writeExportSummary(f, summarySheet, exp.locale, summary); err != nil {
+		return err
+	}
+	_, err = f.WriteTo(w)
+	return err
+}
+
+func writeExportSummary(f *excelize.File, sheet, locale string, s *exportSummary) error {
+	if _, err := f.NewSheet(sheet); err != nil {
+		return err
+	}
+	label := func(en, id string) string {
+		if locale == ExportLocaleID {
+			return id
+		}
+		return en
+	}
+
+	rows := [][]interface{}{
+		{label("Total items", "Total item"), s.total},
+		{label("Items with distance", "Item dengan jarak"), s.withDistance},
+	}
+	if s.withDistance > 0 {
+		rows = append(rows, []interface{}{label("Average distance (m)", "Rata-rata jarak (m)"), s.distanceSumKm / float64(s.withDistance) * 1000})
+	}
+	rows = append(rows, []interface{}{}, []interface{}{label("Accuracy", "Akurasi"), label("Count", "Jumlah")})
+	for _, k := range sortedKeys(s.byAccuracy) {
+		rows = append(rows, []interface{}{k, s.byAccuracy[k]})
+	}
+	rows = append(rows, []interface{}{}, []interface{}{label("Reason Code", "Kode Alasan"), label("Count", "Jumlah")})
+	for _, k := range sortedKeys(s.byReason) {
+		rows = append(rows, []interface{}{k, s.byReason[k]})
+	}
+
+	for i, r := range rows {
+		if len(r) == 0 {
+			continue
+		}
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return err
+		}
+		if err := f.SetSheetRow(sheet, cell, &r); err != nil {
+			return err
+		}
+	}
+	return f.SetColWidth(sheet, "A", "A", 28)
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+
+	"geoaccuracy-backend/internal/domain"
+	"geoaccuracy-backend/pkg/geoexport"
+)
+
+// Geospatial export formats. They ignore the column and locale options: every
+// feature carries the same fixed set of properties.
+const (
+	ExportFormatGeoJSON    = "geojson"
+	ExportFormatKML        = "kml"
+	ExportFormatGeoPackage = "gpkg"
+)
+
+// Layer names; in a GeoPackage each one is a table.
+const (
+	geoLayerSystem     = "system_points"
+	geoLayerField      = "field_points"
+	geoLayerConnection = "connections"
+)
+
+var geoExportFields = []geoexport.Field{
+	{Name: "connote", Type: geoexport.FieldText},
+	{Name: "accuracy_level", Type: geoexport.FieldText},
+	{Name: "distance_m", Type: geoexport.FieldReal},
+	{Name: "courier_id", Type: geoexport.FieldText},
+	{Name: "reason_code", Type: geoexport.FieldText},
+	{Name: "provider", Type: geoexport.FieldText},
+	{Name: "cache_status", Type: geoexport.FieldText},
+}
+
+var geoExportLayers = []geoexport.Layer{
+	{Name: geoLayerSystem, GeometryType: geoexport.GeometryPoint, Fields: geoExportFields},
+	{Name: geoLayerField, GeometryType: geoexport.GeometryPoint, Fields: geoExportFields},
+	{Name: geoLayerConnection, GeometryType: geoexport.GeometryLineString, Fields: geoExportFields},
+}
+
+// kmlAccuracyStyles colour placemarks by accuracy level (KML aabbggrr).
+var kmlAccuracyStyles = []geoexport.KMLStyle{
+	{ID: "accurate", Color: "ff00c800"},
+	{ID: "fairly_accurate", Color: "ff00d7ff"},
+	{ID: "inaccurate", Color: "ff0000ff"},
+	{ID: domain.AccuracyUnverifiable, Color: "ff808080"},
+	{ID: "pending", Color: "ffc8c8c8"},
+}
+
+func isGeoExportFormat(format string) bool {
+	return format == ExportFormatGeoJSON || format == ExportFormatKML || format == ExportFormatGeoPackage
+}
+
+// exportGeo writes, per item, the geocoded system point, the field point and
+// the line between them, skipping whichever coordinates are missing.
+func (s *batchService) exportGeo(ctx context.Context, batchID uuid.UUID, w io.Writer, format string) error {
+	var (
+		gw  geoexport.Writer
+		err error
+	)
+	switch format {
+	case ExportFormatGeoJSON:
+		gw, err = geoexport.NewGeoJSONWriter(w, geoExportLayers)
+	case ExportFormatKML:
+		gw, err = geoexport.NewKMLWriter(w, "Batch "+batchID.String(), geoExportLayers, kmlAccuracyStyles)
+	default:
+		gw, err = geoexport.NewGeoPackageWriter(w, geoExportLayers)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = s.batchRepo.StreamBatchItems(ctx, batchID, func(it *domain.BatchItem) error {
+		for _, f := range itemFeatures(it) {
+			if err := gw.WriteFeature(f); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// Close still releases the writer's resources (the GeoPackage temp file).
+		gw.Close()
+		return err
+	}
+	return gw.Close()
+}
+
+func itemFeatures(it *domain.BatchItem) []geoexport.Feature {
+	props := map[string]interface{}{
+		"connote":        it.Connote,
+		"accuracy_level": it.AccuracyLevel,
+		"courier_id":     it.CourierID,
+		"reason_code":    string(it.ReasonCode),
+		"provider":       it.Provider,
+		"cache_status":   it.CacheStatus,
+	}
+	if it.DistanceKm != nil {
+		props["distance_m"] = *it.DistanceKm * 1000
+	}
+	style := it.AccuracyLevel
+	if style == "" {
+		style = "pending"
+	}
+
+	var features []geoexport.Feature
+	hasSystem := it.SystemLat != nil && it.SystemLng != nil
+	hasField := it.FieldLat != nil && it.FieldLng != nil
+	if hasSystem {
+		features = append(features, geoexport.Feature{
+			Layer: geoLayerSystem, Name: it.Connote + " system", Style: style,
+			Geometry: geoexport.Point(*it.SystemLng, *it.SystemLat), Properties: props,
+		})
+	}
+	if hasField {
+		features = append(features, geoexport.Feature{
+			Layer: geoLayerField, Name: it.Connote + " field", Style: style,
+			Geometry: geoexport.Point(*it.FieldLng, *it.FieldLat), Properties: props,
+		})
+	}
+	if hasSystem && hasField {
+		features = append(features, geoexport.Feature{
+			Layer: geoLayerConnection, Name: it.Connote, Style: style,
+			Geometry:   geoexport.LineString([2]float64{*it.SystemLng, *it.SystemLat}, [2]float64{*it.FieldLng, *it.FieldLat}),
+			Properties: props,
+		})
+	}
+	return features
+}
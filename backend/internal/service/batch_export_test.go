@@ -0,0 +1,138 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+// exportBatchRepo serves a fixed item list; methods the export does not use
+// panic through the nil embedded interface.
+type exportBatchRepo struct {
+	domain.BatchRepository
+	batch *domain.Batch
+	items []domain.BatchItem
+}
+
+func (r *exportBatchRepo) GetBatchByID(ctx context.Context, id uuid.UUID) (*domain.Batch, error) {
+	return r.batch, nil
+}
+
+func (r *exportBatchRepo) GetBatchPermission(ctx context.Context, batchID uuid.UUID, userID int64) (domain.BatchPermission, error) {
+	return "", nil
+}
+
+func (r *exportBatchRepo) StreamBatchItems(ctx context.Context, batchID uuid.UUID, fn func(*domain.BatchItem) error) error {
+	for i := range r.items {
+		if err := fn(&r.items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newExportTestService() *batchService {
+	lat, lng, dist := -6.2088, 106.8456, 0.0425
+	sysLat, sysLng := -6.2090, 106.8459
+	return &batchService{batchRepo: &exportBatchRepo{
+		batch: &domain.Batch{ID: uuid.New(), UserID: 7},
+		items: []domain.BatchItem{
+			{Connote: "R1", SystemLat: &sysLat, SystemLng: &sysLng, FieldLat: &lat, FieldLng: &lng, DistanceKm: &dist,
+				AccuracyLevel: "accurate", ReasonCode: domain.ReasonWithinAccurateThreshold},
+			{Connote: "R2", SystemAddress: "Jl. Sudirman; No. 5"},
+		},
+	}}
+}
+
+func TestExportBatch_CSVLocales(t *testing.T) {
+	svc := newExportTestService()
+	cols := []string{"connote", "system_address", "field_lat", "distance_m", "accuracy_level"}
+
+	var id bytes.Buffer
+	err := svc.ExportBatch(context.Background(), 7, uuid.New(), &id, domain.ExportOptions{Format: "csv", Columns: cols, Locale: "id"})
+	require.NoError(t, err)
+	assert.Equal(t, "\ufeffNo. Resi;Alamat Sistem;Lat Lapangan;Jarak (m);Akurasi\n"+
+		"R1;;-6,208800;42,5;accurate\n"+
+		"R2;\"Jl. Sudirman; No. 5\";;;\n", id.String())
+
+	var en bytes.Buffer
+	err = svc.ExportBatch(context.Background(), 7, uuid.New(), &en, domain.ExportOptions{Format: "csv", Columns: cols, Locale: "en"})
+	require.NoError(t, err)
+	assert.Equal(t, "\ufeffConnote,System Address,Field Lat,Distance (m),Accuracy\n"+
+		"R1,,-6.208800,42.5,accurate\n"+
+		"R2,Jl. Sudirman; No. 5,,,\n", en.String())
+}
+
+func TestExportBatch_XLSXSummary(t *testing.T) {
+	svc := newExportTestService()
+
+	var buf bytes.Buffer
+	err := svc.ExportBatch(context.Background(), 7, uuid.New(), &buf, domain.ExportOptions{Format: "xlsx", Locale: "en"})
+	require.NoError(t, err)
+
+	f, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer f.Close()
+	assert.Equal(t, []string{"Results", "Summary"}, f.GetSheetList())
+
+	rows, err := f.GetRows("Results")
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, "Connote", rows[0][0])
+
+	summary, err := f.GetRows("Summary")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Total items", "2"}, summary[0])
+	assert.Contains(t, summary, []string{"accurate", "1"})
+	assert.Contains(t, summary, []string{"pending", "1"})
+}
+
+func TestExportBatch_GeoJSON(t *testing.T) {
+	svc := newExportTestService()
+
+	var buf bytes.Buffer
+	err := svc.ExportBatch(context.Background(), 7, uuid.New(), &buf, domain.ExportOptions{Format: "geojson"})
+	require.NoError(t, err)
+
+	var fc struct {
+		Features []struct {
+			Geometry struct {
+				Type string `json:"type"`
+			} `json:"geometry"`
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &fc))
+	// R1 has both points and the line between them; R2 has no coordinates.
+	require.Len(t, fc.Features, 3)
+	assert.Equal(t, geoLayerSystem, fc.Features[0].Properties["layer"])
+	assert.Equal(t, geoLayerField, fc.Features[1].Properties["layer"])
+	assert.Equal(t, "LineString", fc.Features[2].Geometry.Type)
+	assert.Equal(t, "accurate", fc.Features[2].Properties["accuracy_level"])
+	assert.InDelta(t, 42.5, fc.Features[2].Properties["distance_m"], 1e-9)
+}
+
+func TestExportBatch_RejectsBeforeWriting(t *testing.T) {
+	svc := newExportTestService()
+
+	var buf bytes.Buffer
+	err := svc.ExportBatch(context.Background(), 7, uuid.New(), &buf, domain.ExportOptions{Format: "pdf"})
+	assert.ErrorIs(t, err, ErrInvalidExport)
+
+	err = svc.ExportBatch(context.Background(), 7, uuid.New(), &buf, domain.ExportOptions{Columns: []string{"connote", "password"}})
+	assert.ErrorIs(t, err, ErrInvalidExport)
+	assert.True(t, strings.Contains(err.Error(), "password"))
+
+	err = svc.ExportBatch(context.Background(), 8, uuid.New(), &buf, domain.ExportOptions{})
+	assert.ErrorIs(t, err, errAccessDenied)
+	assert.Zero(t, buf.Len())
+}
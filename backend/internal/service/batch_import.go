@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"geoaccuracy-backend/internal/domain"
+	"geoaccuracy-backend/pkg/spreadsheet"
+)
+
+const (
+	// importChunkSize rows are upserted per transaction while the file streams.
+	importChunkSize = 1000
+	// maxReportedRowErrors bounds the report; later errors are only counted.
+	maxReportedRowErrors = 1000
+)
+
+// ErrInvalidImport is returned when the file itself (not a single row) cannot
+// be imported: unknown format, empty file, or missing mapped columns.
+var ErrInvalidImport = errors.New("invalid import file")
+
+// importField is one target field of an import and the headers it may come from.
+type importField struct {
+	name     string
+	mapped   string // explicit header from the ColumnMapping; must exist when set
+	aliases  []string
+	required bool
+}
+
+func systemImportFields(m domain.ColumnMapping) []importField {
+	return []importField{
+		{name: "connote", mapped: m.Connote, aliases: []string{"connote", "no_resi", "resi", "awb", "no_awb"}, required: true},
+		{name: "recipient_name", mapped: m.RecipientName, aliases: []string{"recipient_name", "recipient", "nama_penerima", "penerima"}},
+		{name: "address", mapped: m.Address, aliases: []string{"system_address", "address", "alamat"}, required: true},
+	}
+}
+
+func fieldImportFields(m domain.ColumnMapping) []importField {
+	return []importField{
+		{name: "connote", mapped: m.Connote, aliases: []string{"connote", "no_resi", "resi", "awb", "no_awb"}, required: true},
+		{name: "lat", mapped: m.Lat, aliases: []string{"field_lat", "lat", "latitude"}, required: true},
+		{name: "lng", mapped: m.Lng, aliases: []string{"field_lng", "lng", "lon", "long", "longitude"}, required: true},
+		{name: "reported_by", mapped: m.ReportedBy, aliases: []string{"reported_by", "courier_id", "kurir"}},
+		{name: "report_date", mapped: m.ReportDate, aliases: []string{"report_date", "tanggal", "date"}},
+	}
+}
+
+func (s *batchService) ImportSystemFile(ctx context.Context, userID int64, batchID uuid.UUID, src io.Reader, opts domain.ImportOptions) (*domain.ImportReport, error) {
+	if err := s.verifyBatchWritable(ctx, batchID, userID); err != nil {
+		return nil, err
+	}
+
+	quality := s.newUploadReport(int(userID))
+	report, err := importRows(src, opts, systemImportFields(opts.Mapping),
+		func(get func(string) string) (domain.SystemRecord, *domain.RowError) {
+			rec := domain.SystemRecord{
+				Connote:       get("connote"),
+				RecipientName: get("recipient_name"),
+				SystemAddress: get("address"),
+			}
+			if rec.Connote == "" {
+				return rec, &domain.RowError{Column: "connote", Message: "connote is empty"}
+			}
+			return rec, nil
+		},
+		func(records []domain.SystemRecord) error {
+			return s.storeSystemRecords(ctx, batchID, records, quality)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	report.Quality = quality
+	return report, nil
+}
+
+func (s *batchService) ImportFieldFile(ctx context.Context, userID int64, batchID uuid.UUID, src io.Reader, opts domain.ImportOptions) (*domain.ImportReport, error) {
+	if err := s.verifyBatchWritable(ctx, batchID, userID); err != nil {
+		return nil, err
+	}
+
+	return importRows(src, opts, fieldImportFields(opts.Mapping),
+		func(get func(string) string) (domain.FieldRecord, *domain.RowError) {
+			rec := domain.FieldRecord{
+				Connote:    get("connote"),
+				ReportedBy: get("reported_by"),
+				ReportDate: get("report_date"),
+			}
+			if rec.Connote == "" {
+				return rec, &domain.RowError{Column: "connote", Message: "connote is empty"}
+			}
+			lat, err := parseCoordinate(get("lat"), 90)
+			if err != nil {
+				return rec, &domain.RowError{Column: "lat", Message: err.Error()}
+			}
+			lng, err := parseCoordinate(get("lng"), 180)
+			if err != nil {
+				return rec, &domain.RowError{Column: "lng", Message: err.Error()}
+			}
+			rec.FieldLat, rec.FieldLng = lat, lng
+			return rec, nil
+		},
+		func(records []domain.FieldRecord) error {
+			return s.storeFieldRecords(ctx, batchID, records)
+		},
+	)
+}
+
+// importRows streams src, maps each row through parse and hands valid records
+// to flush in chunks of importChunkSize. Row-level problems are collected in
+// the report; only file-level problems and flush failures abort the import.
+func importRows[T any](
+	src io.Reader,
+	opts domain.ImportOptions,
+	fields []importField,
+	parse func(get func(string) string) (T, *domain.RowError),
+	flush func([]T) error,
+) (*domain.ImportReport, error) {
+	rd, err := spreadsheet.Open(src, opts.Filename, spreadsheet.Options{Delimiter: opts.Delimiter})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	defer rd.Close()
+
+	header, _, err := rd.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("%w: file is empty", ErrInvalidImport)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot read header: %v", ErrInvalidImport, err)
+	}
+	cols, err := resolveColumns(header, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.ImportReport{
+		Format:    rd.Format(),
+		RowErrors: []domain.RowError{},
+	}
+	if rd.Format() == "csv" {
+		report.Encoding = rd.Encoding()
+		report.Delimiter = string(rd.Delimiter())
+	}
+	addError := func(e domain.RowError) {
+		report.Rejected++
+		if len(report.RowErrors) < maxReportedRowErrors {
+			report.RowErrors = append(report.RowErrors, e)
+		} else {
+			report.RowErrorsTruncated = true
+		}
+	}
+
+	chunk := make([]T, 0, importChunkSize)
+	flushChunk := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		if err := flush(chunk); err != nil {
+			return err
+		}
+		report.Imported += len(chunk)
+		chunk = chunk[:0]
+		return nil
+	}
+
+	for {
+		row, line, err := rd.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var rowErr *spreadsheet.RowError
+			if errors.As(err, &rowErr) {
+				report.TotalRows++
+				addError(domain.RowError{Row: rowErr.Line, Message: rowErr.Err.Error()})
+				continue
+			}
+			return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+		}
+		report.TotalRows++
+
+		get := func(name string) string {
+			i, ok := cols[name]
+			if !ok || i >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		}
+		rec, rowErr := parse(get)
+		if rowErr != nil {
+			rowErr.Row = line
+			addError(*rowErr)
+			continue
+		}
+
+		chunk = append(chunk, rec)
+		if len(chunk) == importChunkSize {
+			if err := flushChunk(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := flushChunk(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// resolveColumns maps each field to a header index. Explicitly mapped headers
+// must exist; otherwise the first matching alias is used.
+func resolveColumns(header []string, fields []importField) (map[string]int, error) {
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		key := normalizeHeader(h)
+		if _, dup := index[key]; !dup {
+			index[key] = i
+		}
+	}
+
+	cols := make(map[string]int, len(fields))
+	for _, f := range fields {
+		if f.mapped != "" {
+			i, ok := index[normalizeHeader(f.mapped)]
+			if !ok {
+				return nil, fmt.Errorf("%w: mapped column %q for %s not found in header", ErrInvalidImport, f.mapped, f.name)
+			}
+			cols[f.name] = i
+			continue
+		}
+		for _, alias := range f.aliases {
+			if i, ok := index[alias]; ok {
+				cols[f.name] = i
+				break
+			}
+		}
+		if _, ok := cols[f.name]; !ok && f.required {
+			return nil, fmt.Errorf("%w: no column for %s; add it to the column mapping", ErrInvalidImport, f.name)
+		}
+	}
+	return cols, nil
+}
+
+// normalizeHeader makes "No. Resi", "no_resi" and "NO RESI" compare equal.
+func normalizeHeader(h string) string {
+	h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+	h = strings.NewReplacer(".", "", "-", "_", " ", "_").Replace(h)
+	return strings.Trim(h, "_")
+}
+
+// parseCoordinate accepts both "-6.2088" and the decimal comma "-6,2088"
+// that Indonesian-locale spreadsheets produce.
+func parseCoordinate(raw string, limit float64) (float64, error) {
+	if raw == "" {
+		return 0, errors.New("coordinate is empty")
+	}
+	if !strings.Contains(raw, ".") {
+		raw = strings.Replace(raw, ",", ".", 1)
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid coordinate %q", raw)
+	}
+	if v < -limit || v > limit {
+		return 0, fmt.Errorf("coordinate %v out of range", v)
+	}
+	return v, nil
+}
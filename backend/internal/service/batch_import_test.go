@@ -0,0 +1,90 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+func importFieldCSV(t *testing.T, data string, mapping domain.ColumnMapping) (*domain.ImportReport, []domain.FieldRecord, error) {
+	t.Helper()
+	var stored []domain.FieldRecord
+	report, err := importRows(strings.NewReader(data), domain.ImportOptions{Filename: "field.csv", Mapping: mapping},
+		fieldImportFields(mapping),
+		func(get func(string) string) (domain.FieldRecord, *domain.RowError) {
+			rec := domain.FieldRecord{Connote: get("connote"), ReportedBy: get("reported_by")}
+			lat, err := parseCoordinate(get("lat"), 90)
+			if err != nil {
+				return rec, &domain.RowError{Column: "lat", Message: err.Error()}
+			}
+			lng, err := parseCoordinate(get("lng"), 180)
+			if err != nil {
+				return rec, &domain.RowError{Column: "lng", Message: err.Error()}
+			}
+			rec.FieldLat, rec.FieldLng = lat, lng
+			return rec, nil
+		},
+		func(records []domain.FieldRecord) error {
+			assert.LessOrEqual(t, len(records), importChunkSize)
+			stored = append(stored, records...)
+			return nil
+		},
+	)
+	return report, stored, err
+}
+
+func TestImportRows_MappingAndRowErrors(t *testing.T) {
+	data := "No Resi;Lintang;Bujur;Kurir\n" +
+		"R1;-6,2088;106,8456;K01\n" +
+		"R2;abc;106.8;K02\n" +
+		"R3;-6.3;200;K03\n" +
+		"R4;-7.25;112.75;K04\n"
+	mapping := domain.ColumnMapping{Lat: "lintang", Lng: "BUJUR", ReportedBy: "Kurir"}
+
+	report, stored, err := importFieldCSV(t, data, mapping)
+
+	require.NoError(t, err)
+	assert.Equal(t, "csv", report.Format)
+	assert.Equal(t, ";", report.Delimiter)
+	assert.Equal(t, 4, report.TotalRows)
+	assert.Equal(t, 2, report.Imported)
+	assert.Equal(t, 2, report.Rejected)
+	require.Len(t, report.RowErrors, 2)
+	assert.Equal(t, domain.RowError{Row: 3, Column: "lat", Message: `invalid coordinate "abc"`}, report.RowErrors[0])
+	assert.Equal(t, 4, report.RowErrors[1].Row)
+	assert.Equal(t, "lng", report.RowErrors[1].Column)
+
+	require.Len(t, stored, 2)
+	assert.Equal(t, "R1", stored[0].Connote)
+	assert.Equal(t, -6.2088, stored[0].FieldLat)
+	assert.Equal(t, 106.8456, stored[0].FieldLng)
+	assert.Equal(t, "K01", stored[0].ReportedBy)
+}
+
+func TestImportRows_MissingMappedColumn(t *testing.T) {
+	_, _, err := importFieldCSV(t, "connote,lat,lng\nR1,1,1\n", domain.ColumnMapping{ReportedBy: "driver"})
+	assert.ErrorIs(t, err, ErrInvalidImport)
+	assert.Contains(t, err.Error(), `"driver"`)
+
+	_, _, err = importFieldCSV(t, "connote,lat\nR1,1\n", domain.ColumnMapping{})
+	assert.ErrorIs(t, err, ErrInvalidImport)
+}
+
+func TestImportRows_FlushesInChunks(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("connote,lat,lng\n")
+	for i := 0; i < importChunkSize*2+5; i++ {
+		fmt.Fprintf(&sb, "R%d,1,1\n", i)
+	}
+
+	report, stored, err := importFieldCSV(t, sb.String(), domain.ColumnMapping{})
+
+	require.NoError(t, err)
+	assert.Equal(t, importChunkSize*2+5, report.Imported)
+	assert.Len(t, stored, importChunkSize*2+5)
+}
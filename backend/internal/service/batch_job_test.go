@@ -0,0 +1,365 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"geoaccuracy-backend/internal/domain"
+	"geoaccuracy-backend/internal/repository"
+)
+
+// memBatchRepo keeps one batch's items in memory and applies upserts the way
+// the SQL repository does: empty fields leave the stored value alone.
+type memBatchRepo struct {
+	domain.BatchRepository
+	batch  *domain.Batch
+	items  []domain.BatchItem
+	status domain.BatchStatus
+}
+
+func (r *memBatchRepo) GetBatchByID(ctx context.Context, id uuid.UUID) (*domain.Batch, error) {
+	return r.batch, nil
+}
+
+func (r *memBatchRepo) GetBatchPermission(ctx context.Context, batchID uuid.UUID, userID int64) (domain.BatchPermission, error) {
+	return "", nil
+}
+
+func (r *memBatchRepo) CountBatchItemsByStatus(ctx context.Context, batchID uuid.UUID, status string) (int, error) {
+	n := 0
+	for _, it := range r.items {
+		if it.GeocodeStatus == status {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *memBatchRepo) GetPendingBatchItems(ctx context.Context, batchID uuid.UUID, limit int) ([]domain.BatchItem, error) {
+	var out []domain.BatchItem
+	for _, it := range r.items {
+		if it.GeocodeStatus == domain.ItemStatusPending && len(out) < limit {
+			out = append(out, it)
+		}
+	}
+	return out, nil
+}
+
+func (r *memBatchRepo) UpsertBatchItems(ctx context.Context, items []domain.BatchItem) error {
+	for _, u := range items {
+		for i := range r.items {
+			if r.items[i].Connote != u.Connote {
+				continue
+			}
+			if u.GeocodeStatus != "" {
+				r.items[i].GeocodeStatus = u.GeocodeStatus
+			}
+			if u.DistanceKm != nil {
+				r.items[i].DistanceKm = u.DistanceKm
+			}
+			if u.AccuracyLevel != "" {
+				r.items[i].AccuracyLevel = u.AccuracyLevel
+			}
+			if u.AttemptCount > 0 {
+				r.items[i].AttemptCount = u.AttemptCount
+			}
+		}
+	}
+	return nil
+}
+
+func (r *memBatchRepo) MarkBatchItemsPending(ctx context.Context, batchID uuid.UUID, filter domain.ReprocessFilter) (int, error) {
+	n := 0
+	for i := range r.items {
+		if len(filter.GeocodeStatuses) > 0 && !containsString(filter.GeocodeStatuses, r.items[i].GeocodeStatus) {
+			continue
+		}
+		r.items[i].GeocodeStatus = domain.ItemStatusPending
+		n++
+	}
+	return n, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memBatchRepo) UpdateBatchStatus(ctx context.Context, id uuid.UUID, status domain.BatchStatus) error {
+	r.status = status
+	return nil
+}
+
+func (r *memBatchRepo) StreamBatchItems(ctx context.Context, batchID uuid.UUID, fn func(*domain.BatchItem) error) error {
+	for i := range r.items {
+		if err := fn(&r.items[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newJobTestService(t *testing.T, repo domain.BatchRepository, geo GeocodeService) *batchService {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return &batchService{
+		batchRepo:      repo,
+		geoService:     geo,
+		historyService: NewHistoryService(repository.NewHistoryRepository(db)),
+		classifier:     resultClassifier{minPrecision: domain.PrecisionStreet},
+	}
+}
+
+func TestRunBatchJob_ResumesFromCheckpoint(t *testing.T) {
+	batchID := uuid.New()
+	fieldLat, fieldLng := -6.2, 106.8
+	repo := &memBatchRepo{}
+	for i := 0; i < 250; i++ {
+		repo.items = append(repo.items, domain.BatchItem{
+			BatchID: batchID, Connote: fmt.Sprintf("R%03d", i), SystemAddress: fmt.Sprintf("addr-%d", i),
+			FieldLat: &fieldLat, FieldLng: &fieldLng, GeocodeStatus: domain.ItemStatusPending,
+		})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	geo := &stubGeocodeService{GeocodeFunc: func(_ context.Context, userID int, address string) (*domain.GeocodeResponse, error) {
+		calls++
+		if calls == 120 {
+			cancel() // simulated shutdown in the middle of the second chunk
+		}
+		return &domain.GeocodeResponse{Lat: -6.2, Lng: 106.8, Precision: domain.PrecisionRooftop}, nil
+	}}
+	svc := newJobTestService(t, repo, geo)
+
+	job := &domain.BatchJob{ID: 1, BatchID: batchID, UserID: 7}
+	var checkpoints [][2]int
+	checkpoint := func(processed, total int) error {
+		checkpoints = append(checkpoints, [2]int{processed, total})
+		job.Processed = processed
+		return nil
+	}
+
+	err := svc.RunBatchJob(ctx, job, checkpoint)
+	require.ErrorIs(t, err, context.Canceled)
+	// The item whose geocode saw the cancellation stays pending.
+	assert.Equal(t, [][2]int{{0, 250}, {100, 250}, {119, 250}}, checkpoints)
+	pending, _ := repo.CountBatchItemsByStatus(ctx, batchID, domain.ItemStatusPending)
+	assert.Equal(t, 131, pending)
+	assert.NotEqual(t, domain.BatchStatusCompleted, repo.status)
+
+	checkpoints = nil
+	require.NoError(t, svc.RunBatchJob(context.Background(), job, checkpoint))
+	assert.Equal(t, [2]int{119, 250}, checkpoints[0])
+	assert.Equal(t, [2]int{250, 250}, checkpoints[len(checkpoints)-1])
+	assert.Equal(t, 250+1, calls) // only the interrupted item is geocoded twice
+	assert.Equal(t, domain.BatchStatusCompleted, repo.status)
+	for _, it := range repo.items {
+		assert.Equal(t, domain.ItemStatusCompleted, it.GeocodeStatus)
+		assert.Equal(t, "accurate", it.AccuracyLevel)
+	}
+}
+
+// transitionJobRepo lets every Transition succeed and records it.
+type transitionJobRepo struct {
+	domain.BatchJobRepository
+	transitions []domain.BatchJobStatus
+	latest      *domain.BatchJob
+	enqueued    []*domain.BatchJob
+}
+
+func (r *transitionJobRepo) GetLatestByBatchID(ctx context.Context, batchID uuid.UUID) (*domain.BatchJob, error) {
+	return r.latest, nil
+}
+
+func (r *transitionJobRepo) Enqueue(ctx context.Context, batchID uuid.UUID, userID int64, bypassCache bool) (*domain.BatchJob, error) {
+	job := &domain.BatchJob{BatchID: batchID, UserID: userID, Status: domain.BatchJobQueued, BypassCache: bypassCache}
+	r.enqueued = append(r.enqueued, job)
+	return job, nil
+}
+
+func (r *transitionJobRepo) Transition(ctx context.Context, batchID uuid.UUID, from []domain.BatchJobStatus, to domain.BatchJobStatus) (*domain.BatchJob, error) {
+	r.transitions = append(r.transitions, to)
+	return &domain.BatchJob{BatchID: batchID, Status: to}, nil
+}
+
+func TestPauseBatch_StopsRunningJobAfterCurrentItem(t *testing.T) {
+	batchID := uuid.New()
+	repo := &memBatchRepo{batch: &domain.Batch{ID: batchID, UserID: 7}}
+	for i := 0; i < 10; i++ {
+		repo.items = append(repo.items, domain.BatchItem{
+			BatchID: batchID, Connote: fmt.Sprintf("R%d", i), SystemAddress: fmt.Sprintf("addr-%d", i),
+			GeocodeStatus: domain.ItemStatusPending,
+		})
+	}
+	jobs := &transitionJobRepo{}
+
+	var svc *batchService
+	calls := 0
+	geo := &stubGeocodeService{GeocodeFunc: func(ctx context.Context, userID int, address string) (*domain.GeocodeResponse, error) {
+		calls++
+		if calls == 5 {
+			require.NoError(t, svc.PauseBatch(context.Background(), 7, batchID))
+		}
+		return &domain.GeocodeResponse{Lat: -6.2, Lng: 106.8, Precision: domain.PrecisionRooftop}, nil
+	}}
+	svc = newJobTestService(t, repo, geo)
+	svc.jobRepo = jobs
+
+	var last int
+	err := svc.RunBatchJob(context.Background(), &domain.BatchJob{BatchID: batchID, UserID: 7},
+		func(processed, total int) error { last = processed; return nil })
+
+	require.ErrorIs(t, err, domain.ErrBatchJobStopped)
+	assert.Equal(t, 5, calls)
+	assert.Equal(t, 4, last)
+	assert.Equal(t, domain.BatchStatusPaused, repo.status)
+	pending, _ := repo.CountBatchItemsByStatus(context.Background(), batchID, domain.ItemStatusPending)
+	assert.Equal(t, 6, pending)
+
+	require.NoError(t, svc.ResumeBatch(context.Background(), 7, batchID))
+	assert.Equal(t, []domain.BatchJobStatus{domain.BatchJobPaused, domain.BatchJobQueued}, jobs.transitions)
+	assert.Equal(t, domain.BatchStatusProcessing, repo.status)
+
+	err = svc.CancelBatch(context.Background(), 8, batchID)
+	assert.ErrorIs(t, err, errAccessDenied)
+}
+
+func TestReprocessBatch_OnlyMatchingItems(t *testing.T) {
+	batchID := uuid.New()
+	repo := &memBatchRepo{batch: &domain.Batch{ID: batchID, UserID: 7}}
+	for i, status := range []string{domain.ItemStatusCompleted, domain.ItemStatusFailed, domain.ItemStatusFailed, domain.ItemStatusSkipped} {
+		repo.items = append(repo.items, domain.BatchItem{
+			BatchID: batchID, Connote: fmt.Sprintf("R%d", i), SystemAddress: fmt.Sprintf("addr-%d", i),
+			GeocodeStatus: status, AttemptCount: 1,
+		})
+	}
+	jobs := &transitionJobRepo{latest: &domain.BatchJob{Status: domain.BatchJobCompleted}}
+	var geocoded []string
+	geo := &stubGeocodeService{GeocodeFunc: func(ctx context.Context, userID int, address string) (*domain.GeocodeResponse, error) {
+		geocoded = append(geocoded, address)
+		return &domain.GeocodeResponse{Lat: -6.2, Lng: 106.8, Provider: "stub"}, nil
+	}}
+	svc := newJobTestService(t, repo, geo)
+	svc.jobRepo = jobs
+
+	_, err := svc.ReprocessBatch(context.Background(), 7, batchID, domain.ReprocessRequest{})
+	require.ErrorIs(t, err, ErrEmptyReprocessFilter)
+
+	req := domain.ReprocessRequest{
+		ReprocessFilter: domain.ReprocessFilter{GeocodeStatuses: []string{domain.ItemStatusFailed}},
+		BypassCache:     true,
+	}
+	queued, err := svc.ReprocessBatch(context.Background(), 7, batchID, req)
+	require.NoError(t, err)
+	assert.Equal(t, 2, queued)
+	require.Len(t, jobs.enqueued, 1)
+	assert.True(t, jobs.enqueued[0].BypassCache)
+
+	require.NoError(t, svc.RunBatchJob(context.Background(), jobs.enqueued[0], func(int, int) error { return nil }))
+	assert.Equal(t, []string{"addr-1", "addr-2"}, geocoded)
+	assert.Equal(t, 1, repo.items[0].AttemptCount)
+	assert.Equal(t, 2, repo.items[1].AttemptCount)
+	assert.Equal(t, domain.ItemStatusCompleted, repo.items[2].GeocodeStatus)
+
+	jobs.latest = &domain.BatchJob{Status: domain.BatchJobPaused}
+	_, err = svc.ReprocessBatch(context.Background(), 7, batchID, req)
+	assert.ErrorIs(t, err, domain.ErrBatchJobActive)
+}
+
+func TestChunkResult_CacheStatus(t *testing.T) {
+	s := &batchService{}
+	memCache := map[string]*domain.GeocodeResponse{}
+	results := map[string]GeocodeResult{
+		"Jl. Sudirman 1":    {Response: &domain.GeocodeResponse{Address: "Jl. Sudirman 1", City: "Jakarta Pusat", FromCache: true}},
+		"Jl Thamrin No 2":   {Response: &domain.GeocodeResponse{Address: "Jl. Thamrin No. 2", FromCache: true}},
+		"Jl. Gatot Subroto": {Response: &domain.GeocodeResponse{Address: "Jl. Gatot Subroto"}},
+	}
+
+	// In order: the last address reuses the job's result for the third.
+	for _, tc := range []struct{ address, want string }{
+		{"Jl. Sudirman 1", domain.CacheStatusExact},
+		{"Jl Thamrin No 2", domain.CacheStatusFuzzy},
+		{"Jl. Gatot Subroto", domain.CacheStatusMiss},
+		{"jl. gatot subroto", domain.CacheStatusFuzzy},
+	} {
+		geo, ok := s.chunkResult(domain.BatchItem{SystemAddress: tc.address}, results, memCache)
+		require.True(t, ok, tc.address)
+		assert.Equal(t, tc.want, geo.cacheStatus, tc.address)
+	}
+
+	item, _ := s.processItem(context.Background(), &domain.BatchJob{},
+		domain.BatchItem{SystemAddress: "Jl. Sudirman 1"},
+		batchGeocode{res: results["Jl. Sudirman 1"].Response, fromCache: true, cacheStatus: domain.CacheStatusExact})
+	assert.Equal(t, domain.CacheStatusExact, item.CacheStatus)
+	assert.Equal(t, "Jakarta Pusat", item.GeocodedCity)
+	assert.NotNil(t, item.ProcessedAt)
+}
+
+// orderedJobRepo records the batch status each job was queued under.
+type orderedJobRepo struct {
+	transitionJobRepo
+	batches    *memBatchRepo
+	queuedWith []domain.BatchStatus
+}
+
+func (r *orderedJobRepo) Enqueue(ctx context.Context, batchID uuid.UUID, userID int64, bypassCache bool) (*domain.BatchJob, error) {
+	r.queuedWith = append(r.queuedWith, r.batches.status)
+	return r.transitionJobRepo.Enqueue(ctx, batchID, userID, bypassCache)
+}
+
+func TestProcessBatch_ActiveJobKeepsItems(t *testing.T) {
+	batchID := uuid.New()
+	repo := &memBatchRepo{batch: &domain.Batch{ID: batchID, UserID: 7, Status: domain.BatchStatusPaused}}
+	repo.items = []domain.BatchItem{{BatchID: batchID, Connote: "R1", GeocodeStatus: domain.ItemStatusCompleted}}
+	jobs := &orderedJobRepo{batches: repo}
+	jobs.latest = &domain.BatchJob{BatchID: batchID, Status: domain.BatchJobPaused}
+	svc := newJobTestService(t, repo, nil)
+	svc.jobRepo = jobs
+
+	err := svc.ProcessBatch(context.Background(), 7, batchID)
+	require.ErrorIs(t, err, domain.ErrBatchJobActive)
+	assert.Equal(t, domain.ItemStatusCompleted, repo.items[0].GeocodeStatus)
+	assert.Empty(t, jobs.enqueued)
+	assert.Empty(t, repo.status)
+
+	// The batch is processing before a worker can claim the job.
+	jobs.latest.Status = domain.BatchJobCompleted
+	require.NoError(t, svc.ProcessBatch(context.Background(), 7, batchID))
+	assert.Equal(t, domain.ItemStatusPending, repo.items[0].GeocodeStatus)
+	assert.Equal(t, []domain.BatchStatus{domain.BatchStatusProcessing}, jobs.queuedWith)
+}
+
+// stoppedJobRepo is a job that was paused while its worker finished it.
+type stoppedJobRepo struct {
+	domain.BatchJobRepository
+	released []int64
+}
+
+func (r *stoppedJobRepo) Complete(ctx context.Context, jobID int64, workerID string) error {
+	return domain.ErrBatchJobLeaseLost
+}
+
+func (r *stoppedJobRepo) Release(ctx context.Context, jobID int64, workerID string) error {
+	r.released = append(r.released, jobID)
+	return nil
+}
+
+func TestWorkerFinish_JobStoppedMeanwhileIsReleased(t *testing.T) {
+	jobs := &stoppedJobRepo{}
+	pool := NewBatchWorkerPool(jobs, nil, 1)
+
+	pool.finish(context.Background(), &domain.BatchJob{ID: 3}, "w1", nil, false)
+	assert.Equal(t, []int64{3}, jobs.released)
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+// retentionPageSize batches are listed per query while enforcing retention.
+const retentionPageSize = 100
+
+// verifyBatchWritable is verifyBatchAccess for operations that change a
+// batch's items or queue work on them: it needs an editor, and archived
+// batches are read-only.
+func (s *batchService) verifyBatchWritable(ctx context.Context, batchID uuid.UUID, userID int64) error {
+	_, err := s.writableBatch(ctx, batchID, userID)
+	return err
+}
+
+// writableBatch is verifyBatchWritable returning the batch.
+func (s *batchService) writableBatch(ctx context.Context, batchID uuid.UUID, userID int64) (*domain.Batch, error) {
+	batch, err := s.batchWithAccess(ctx, batchID, userID, domain.BatchPermissionEditor)
+	if err != nil {
+		return nil, err
+	}
+	if batch.ArchivedAt != nil {
+		return nil, domain.ErrBatchArchived
+	}
+	return batch, nil
+}
+
+// ensureNoActiveJob returns domain.ErrBatchJobActive while the batch has a
+// queued, running or paused job.
+func (s *batchService) ensureNoActiveJob(ctx context.Context, batchID uuid.UUID) error {
+	latest, err := s.jobRepo.GetLatestByBatchID(ctx, batchID)
+	if err != nil {
+		return err
+	}
+	if latest != nil && isActiveJobStatus(latest.Status) {
+		return domain.ErrBatchJobActive
+	}
+	return nil
+}
+
+// ArchiveBatch refuses batches with an active job: a paused job could
+// otherwise be resumed into a read-only batch.
+func (s *batchService) ArchiveBatch(ctx context.Context, userID int64, batchID uuid.UUID) error {
+	if err := s.verifyBatchAccess(ctx, batchID, userID, domain.BatchPermissionEditor); err != nil {
+		return err
+	}
+	if err := s.ensureNoActiveJob(ctx, batchID); err != nil {
+		return err
+	}
+	return s.batchRepo.SetBatchArchived(ctx, batchID, true)
+}
+
+func (s *batchService) UnarchiveBatch(ctx context.Context, userID int64, batchID uuid.UUID) error {
+	batch, err := s.batchWithAccess(ctx, batchID, userID, domain.BatchPermissionEditor)
+	if err != nil {
+		return err
+	}
+	if batch.ItemsPurgedAt != nil {
+		return domain.ErrBatchPurged
+	}
+	return s.batchRepo.SetBatchArchived(ctx, batchID, false)
+}
+
+// DeleteBatch is limited to the owner. It checks for an active job up front
+// for a clear error; the repository checks again in the same statement as
+// the delete.
+func (s *batchService) DeleteBatch(ctx context.Context, userID int64, batchID uuid.UUID) error {
+	if err := s.verifyBatchAccess(ctx, batchID, userID, domain.BatchPermissionOwner); err != nil {
+		return err
+	}
+	if err := s.ensureNoActiveJob(ctx, batchID); err != nil {
+		return err
+	}
+	return s.batchRepo.DeleteBatch(ctx, batchID)
+}
+
+// EnforceRetention first deletes batches past their owner's batch retention,
+// then reduces the remaining expired batches to a summary. A failure on one
+// batch is logged and the run moves on; the batch is retried next run.
+func (s *batchService) EnforceRetention(ctx context.Context) (*domain.RetentionResult, error) {
+	result := &domain.RetentionResult{}
+
+	err := s.forEachRetentionBatch(ctx, s.batchRepo.ListBatchesForDeletion, func(id uuid.UUID) error {
+		if err := s.batchRepo.DeleteBatch(ctx, id); err != nil {
+			return err
+		}
+		result.BatchesDeleted++
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	err = s.forEachRetentionBatch(ctx, s.batchRepo.ListBatchesForItemPurge, func(id uuid.UUID) error {
+		summary, err := s.computeBatchStats(ctx, id, append([]float64(nil), defaultHistogramEdges...))
+		if err != nil {
+			return err
+		}
+		purged, err := s.batchRepo.PurgeBatchItems(ctx, id, summary)
+		if err != nil {
+			return err
+		}
+		if purged {
+			result.ItemsPurged++
+		}
+		return nil
+	})
+	return result, err
+}
+
+// forEachRetentionBatch pages through list until it comes back short. Batches
+// that failed are skipped so one bad batch cannot stall the run.
+func (s *batchService) forEachRetentionBatch(ctx context.Context, list func(context.Context, int) ([]uuid.UUID, error), fn func(uuid.UUID) error) error {
+	failed := map[uuid.UUID]bool{}
+	for {
+		limit := retentionPageSize + len(failed)
+		ids, err := list(ctx, limit)
+		if err != nil {
+			return err
+		}
+		progressed := false
+		for _, id := range ids {
+			if failed[id] {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := fn(id); err != nil {
+				log.Printf("[Retention] batch %v: %v", id, err)
+				failed[id] = true
+				continue
+			}
+			progressed = true
+		}
+		if !progressed || len(ids) < limit {
+			return nil
+		}
+	}
+}
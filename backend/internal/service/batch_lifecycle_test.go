@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+// retentionBatchRepo serves the retention lists from memory; deleting or
+// purging a batch removes it from the list, as the SQL predicates would.
+type retentionBatchRepo struct {
+	statsBatchRepo
+	toDelete, toPurge []uuid.UUID
+	failPurge         uuid.UUID
+	busy              uuid.UUID // got a job between listing and purging
+	deleted           []uuid.UUID
+	summaries         map[uuid.UUID]*domain.BatchStats
+	archived          *bool
+}
+
+func (r *retentionBatchRepo) ListBatchesForDeletion(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	return firstN(r.toDelete, limit), nil
+}
+
+func (r *retentionBatchRepo) ListBatchesForItemPurge(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	return firstN(r.toPurge, limit), nil
+}
+
+func (r *retentionBatchRepo) DeleteBatch(ctx context.Context, id uuid.UUID) error {
+	r.deleted = append(r.deleted, id)
+	r.toDelete = without(r.toDelete, id)
+	return nil
+}
+
+func (r *retentionBatchRepo) PurgeBatchItems(ctx context.Context, id uuid.UUID, summary *domain.BatchStats) (bool, error) {
+	if id == r.failPurge {
+		return false, errors.New("disk full")
+	}
+	if id == r.busy {
+		r.toPurge = without(r.toPurge, id)
+		return false, nil
+	}
+	if r.summaries == nil {
+		r.summaries = map[uuid.UUID]*domain.BatchStats{}
+	}
+	r.summaries[id] = summary
+	r.toPurge = without(r.toPurge, id)
+	return true, nil
+}
+
+func (r *retentionBatchRepo) GetBatchSummary(ctx context.Context, id uuid.UUID) (*domain.BatchStats, error) {
+	return r.summaries[id], nil
+}
+
+func (r *retentionBatchRepo) SetBatchArchived(ctx context.Context, id uuid.UUID, archived bool) error {
+	r.archived = &archived
+	return nil
+}
+
+func firstN(ids []uuid.UUID, n int) []uuid.UUID {
+	if len(ids) > n {
+		ids = ids[:n]
+	}
+	return append([]uuid.UUID(nil), ids...)
+}
+
+func without(ids []uuid.UUID, id uuid.UUID) []uuid.UUID {
+	out := ids[:0]
+	for _, v := range ids {
+		if v != id {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func TestEnforceRetention(t *testing.T) {
+	repo := &retentionBatchRepo{statsBatchRepo: statsBatchRepo{counts: map[int]int{1: 3}}}
+	for i := 0; i < retentionPageSize+5; i++ {
+		repo.toPurge = append(repo.toPurge, uuid.New())
+	}
+	repo.toDelete = []uuid.UUID{uuid.New(), uuid.New()}
+	repo.failPurge = repo.toPurge[2]
+	repo.busy = repo.toPurge[3]
+	svc := &batchService{batchRepo: repo}
+
+	result, err := svc.EnforceRetention(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.BatchesDeleted)
+	assert.Equal(t, retentionPageSize+3, result.ItemsPurged, "every batch but the failing and the busy one, across pages")
+	assert.Equal(t, []uuid.UUID{repo.failPurge}, repo.toPurge)
+
+	for _, summary := range repo.summaries {
+		assert.Len(t, summary.Histogram, len(defaultHistogramEdges))
+	}
+	assert.Equal(t, defaultHistogramEdges, repo.edges)
+}
+
+func TestGetBatchStats_PurgedBatchReturnsSummary(t *testing.T) {
+	batchID := uuid.New()
+	purgedAt := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	repo := &retentionBatchRepo{
+		statsBatchRepo: statsBatchRepo{batch: &domain.Batch{ID: batchID, UserID: 7, ItemsPurgedAt: &purgedAt}},
+		summaries:      map[uuid.UUID]*domain.BatchStats{batchID: {BatchID: batchID, TotalItems: 42, ItemsPurgedAt: &purgedAt}},
+	}
+	svc := &batchService{batchRepo: repo}
+
+	stats, err := svc.GetBatchStats(context.Background(), 7, batchID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 42, stats.TotalItems)
+	assert.Nil(t, repo.edges, "items are not queried")
+
+	_, err = svc.GetBatchStats(context.Background(), 7, batchID, []float64{0, 1})
+	assert.ErrorIs(t, err, ErrInvalidHistogram)
+}
+
+func TestBatchArchiveLifecycle(t *testing.T) {
+	batchID := uuid.New()
+	now := time.Now()
+
+	t.Run("archive refuses an active job", func(t *testing.T) {
+		repo := &retentionBatchRepo{statsBatchRepo: statsBatchRepo{batch: &domain.Batch{ID: batchID, UserID: 7}}}
+		jobs := &transitionJobRepo{latest: &domain.BatchJob{Status: domain.BatchJobPaused}}
+		svc := &batchService{batchRepo: repo, jobRepo: jobs}
+
+		assert.ErrorIs(t, svc.ArchiveBatch(context.Background(), 7, batchID), domain.ErrBatchJobActive)
+		assert.ErrorIs(t, svc.DeleteBatch(context.Background(), 7, batchID), domain.ErrBatchJobActive)
+		assert.Nil(t, repo.archived)
+		assert.Empty(t, repo.deleted)
+
+		jobs.latest.Status = domain.BatchJobCompleted
+		require.NoError(t, svc.ArchiveBatch(context.Background(), 7, batchID))
+		assert.True(t, *repo.archived)
+		require.NoError(t, svc.DeleteBatch(context.Background(), 7, batchID))
+		assert.Equal(t, []uuid.UUID{batchID}, repo.deleted)
+	})
+
+	t.Run("archived batches are read-only", func(t *testing.T) {
+		repo := &retentionBatchRepo{statsBatchRepo: statsBatchRepo{batch: &domain.Batch{ID: batchID, UserID: 7, ArchivedAt: &now}}}
+		svc := &batchService{batchRepo: repo, jobRepo: &transitionJobRepo{}}
+
+		_, err := svc.UploadFieldData(context.Background(), 7, batchID, nil, domain.UploadModeLenient)
+		assert.ErrorIs(t, err, domain.ErrBatchArchived)
+		assert.ErrorIs(t, svc.ProcessBatch(context.Background(), 7, batchID), domain.ErrBatchArchived)
+
+		require.NoError(t, svc.UnarchiveBatch(context.Background(), 7, batchID))
+		assert.False(t, *repo.archived)
+	})
+
+	t.Run("purged batches stay archived", func(t *testing.T) {
+		repo := &retentionBatchRepo{statsBatchRepo: statsBatchRepo{batch: &domain.Batch{ID: batchID, UserID: 7, ArchivedAt: &now, ItemsPurgedAt: &now}}}
+		svc := &batchService{batchRepo: repo}
+
+		assert.ErrorIs(t, svc.UnarchiveBatch(context.Background(), 7, batchID), domain.ErrBatchPurged)
+		assert.ErrorIs(t, svc.DeleteBatch(context.Background(), 8, batchID), errAccessDenied)
+	})
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+const (
+	maxBatchTags      = 20
+	maxBatchTagLength = 50
+	maxBatchPageSize  = 500
+)
+
+// ErrInvalidBatchMetadata is returned for batch metadata or list filters that
+// cannot be stored or satisfied.
+var ErrInvalidBatchMetadata = errors.New("invalid batch metadata")
+
+// UpdateBatchMetadata renames and re-describes a batch. Archived batches stay
+// editable: metadata is how they are found again.
+func (s *batchService) UpdateBatchMetadata(ctx context.Context, userID int64, batchID uuid.UUID, name string, meta domain.BatchMetadata) (*domain.Batch, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidBatchMetadata)
+	}
+	if err := normalizeBatchMetadata(&meta); err != nil {
+		return nil, err
+	}
+	if err := s.verifyBatchAccess(ctx, batchID, userID, domain.BatchPermissionEditor); err != nil {
+		return nil, err
+	}
+	if err := s.batchRepo.UpdateBatchMetadata(ctx, batchID, name, meta); err != nil {
+		return nil, err
+	}
+	return s.batchWithAccess(ctx, batchID, userID, domain.BatchPermissionEditor)
+}
+
+// normalizeBatchMetadata trims every field, defaults the source to CSV and
+// lower-cases and de-duplicates the tags.
+func normalizeBatchMetadata(m *domain.BatchMetadata) error {
+	m.Client = strings.TrimSpace(m.Client)
+	m.Region = strings.TrimSpace(m.Region)
+	m.Source = strings.ToLower(strings.TrimSpace(m.Source))
+	if m.Source == "" {
+		m.Source = domain.BatchSourceCSV
+	}
+	if !isBatchSource(m.Source) {
+		return fmt.Errorf("%w: unknown source %q", ErrInvalidBatchMetadata, m.Source)
+	}
+
+	if err := checkDateRange(&m.DeliveryFrom, &m.DeliveryTo, "delivery"); err != nil {
+		return err
+	}
+
+	tags, err := normalizeTags(m.Tags)
+	if err != nil {
+		return err
+	}
+	m.Tags = tags
+	return nil
+}
+
+// normalizeBatchFilter validates f the way normalizeBatchMetadata validates
+// what it is matched against.
+func normalizeBatchFilter(f *domain.BatchFilter) error {
+	switch f.Scope {
+	case domain.BatchScopeAll, domain.BatchScopeOwned, domain.BatchScopeShared:
+	default:
+		return fmt.Errorf("%w: scope must be owned or shared", ErrInvalidBatchMetadata)
+	}
+	f.Query = strings.TrimSpace(f.Query)
+	f.Client = strings.TrimSpace(f.Client)
+	f.Region = strings.TrimSpace(f.Region)
+	for i, src := range f.Sources {
+		f.Sources[i] = strings.ToLower(src)
+		if !isBatchSource(f.Sources[i]) {
+			return fmt.Errorf("%w: unknown source %q", ErrInvalidBatchMetadata, src)
+		}
+	}
+	for _, st := range f.Statuses {
+		switch domain.BatchStatus(st) {
+		case domain.BatchStatusDraft, domain.BatchStatusProcessing, domain.BatchStatusCompleted, domain.BatchStatusFailed,
+			domain.BatchStatusPaused, domain.BatchStatusCancelled:
+		default:
+			return fmt.Errorf("%w: unknown status %q", ErrInvalidBatchMetadata, st)
+		}
+	}
+	tags, err := normalizeTags(f.Tags)
+	if err != nil {
+		return err
+	}
+	f.Tags = tags
+
+	if err := checkDateRange(&f.DeliveryFrom, &f.DeliveryTo, "delivery"); err != nil {
+		return err
+	}
+	if err := checkDateRange(&f.CreatedFrom, &f.CreatedTo, "created"); err != nil {
+		return err
+	}
+
+	if f.Limit < 0 || f.Limit > maxBatchPageSize {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidBatchMetadata, maxBatchPageSize)
+	}
+	if f.Offset < 0 {
+		return fmt.Errorf("%w: offset must not be negative", ErrInvalidBatchMetadata)
+	}
+	return nil
+}
+
+func isBatchSource(s string) bool {
+	switch s {
+	case domain.BatchSourceCSV, domain.BatchSourceETL, domain.BatchSourceWebhook, domain.BatchSourceERP:
+		return true
+	}
+	return false
+}
+
+func normalizeTags(raw []string) ([]string, error) {
+	seen := make(map[string]bool, len(raw))
+	tags := make([]string, 0, len(raw))
+	for _, t := range raw {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		if len([]rune(t)) > maxBatchTagLength {
+			return nil, fmt.Errorf("%w: tag %q is longer than %d characters", ErrInvalidBatchMetadata, t, maxBatchTagLength)
+		}
+		seen[t] = true
+		tags = append(tags, t)
+	}
+	if len(tags) > maxBatchTags {
+		return nil, fmt.Errorf("%w: at most %d tags", ErrInvalidBatchMetadata, maxBatchTags)
+	}
+	return tags, nil
+}
+
+// checkDateRange trims two optional YYYY-MM-DD bounds and checks from <= to.
+func checkDateRange(from, to *string, field string) error {
+	var bounds [2]time.Time
+	for i, p := range []*string{from, to} {
+		*p = strings.TrimSpace(*p)
+		if *p == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", *p)
+		if err != nil {
+			return fmt.Errorf("%w: %s dates must be YYYY-MM-DD", ErrInvalidBatchMetadata, field)
+		}
+		bounds[i] = t
+	}
+	if *from != "" && *to != "" && bounds[0].After(bounds[1]) {
+		return fmt.Errorf("%w: %s_from is after %s_to", ErrInvalidBatchMetadata, field, field)
+	}
+	return nil
+}
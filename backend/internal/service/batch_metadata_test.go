@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"geoaccuracy-backend/internal/domain"
+	"geoaccuracy-backend/internal/repository"
+)
+
+var batchTestColumns = []string{
+	"id", "user_id", "name", "status", "created_at", "updated_at", "archived_at", "items_purged_at",
+	"client", "region", "delivery_from", "delivery_to", "source", "tags", "access",
+}
+
+func TestListUserBatches_Filter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	svc := &batchService{batchRepo: repository.NewBatchRepository(db)}
+
+	now := time.Date(2026, 9, 15, 8, 0, 0, 0, time.UTC)
+	id := uuid.New()
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE (b.user_id = $1 OR b.id IN (SELECT s.batch_id FROM batch_shares s`)+`.*`+
+		regexp.QuoteMeta(`AND (archived_at IS NOT NULL) = $2`)+`.*`+
+		regexp.QuoteMeta(`(name ILIKE $3 ESCAPE '\' OR client ILIKE $3 ESCAPE '\' OR region ILIKE $3 ESCAPE '\')`)+`.*`+
+		regexp.QuoteMeta(`tags @> $5::text[]`)+`.*`+
+		regexp.QuoteMeta(`COALESCE(delivery_to, delivery_from) >= $6::date AND COALESCE(delivery_from, delivery_to) <= $7::date`)+`.*`+
+		regexp.QuoteMeta(`LIMIT $8 OFFSET $9`)).
+		WithArgs(int64(7), false, `%50\%%`, "Surabaya", `{"priority","cod"}`, "2026-08-01", "2026-08-31", 20, 0).
+		WillReturnRows(sqlmock.NewRows(batchTestColumns).
+			AddRow(id, 7, "Agustus", "completed", now, now, nil, nil,
+				"PT Maju", "Surabaya", "2026-08-01", "2026-08-15", "erp", `{cod,priority}`, "viewer"))
+
+	batches, err := svc.ListUserBatches(context.Background(), 7, domain.BatchFilter{
+		Query:        " 50% ",
+		Region:       "Surabaya",
+		Tags:         []string{"Priority", "cod", "priority"},
+		DeliveryFrom: "2026-08-01",
+		DeliveryTo:   "2026-08-31",
+		Limit:        20,
+	})
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+	assert.Equal(t, domain.BatchMetadata{
+		Client: "PT Maju", Region: "Surabaya", DeliveryFrom: "2026-08-01", DeliveryTo: "2026-08-15",
+		Source: domain.BatchSourceERP, Tags: []string{"cod", "priority"},
+	}, batches[0].BatchMetadata)
+	assert.Equal(t, domain.BatchPermissionViewer, batches[0].Access)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBatchMetadata_Validation(t *testing.T) {
+	meta := domain.BatchMetadata{Client: " PT Maju ", Tags: []string{" COD", "cod", ""}}
+	require.NoError(t, normalizeBatchMetadata(&meta))
+	assert.Equal(t, "PT Maju", meta.Client)
+	assert.Equal(t, domain.BatchSourceCSV, meta.Source)
+	assert.Equal(t, []string{"cod"}, meta.Tags)
+
+	long := make([]string, maxBatchTags+1)
+	for i := range long {
+		long[i] = uuid.NewString()
+	}
+	for name, m := range map[string]domain.BatchMetadata{
+		"source": {Source: "ftp"},
+		"date":   {DeliveryFrom: "01/08/2026"},
+		"range":  {DeliveryFrom: "2026-08-31", DeliveryTo: "2026-08-01"},
+		"tags":   {Tags: long},
+	} {
+		assert.ErrorIs(t, normalizeBatchMetadata(&m), ErrInvalidBatchMetadata, name)
+	}
+
+	for name, f := range map[string]domain.BatchFilter{
+		"status":  {Statuses: []string{"done"}},
+		"created": {CreatedFrom: "2026-13-01"},
+		"limit":   {Limit: maxBatchPageSize + 1},
+	} {
+		_, err := (&batchService{}).ListUserBatches(context.Background(), 7, f)
+		assert.ErrorIs(t, err, ErrInvalidBatchMetadata, name)
+	}
+}
+
+func TestUpdateBatchMetadata(t *testing.T) {
+	batchID := uuid.New()
+	archived := time.Now()
+	repo := &metadataBatchRepo{batch: &domain.Batch{ID: batchID, UserID: 7, ArchivedAt: &archived}}
+	svc := &batchService{batchRepo: repo}
+
+	_, err := svc.UpdateBatchMetadata(context.Background(), 8, batchID, "Agustus", domain.BatchMetadata{})
+	assert.ErrorIs(t, err, errAccessDenied)
+
+	batch, err := svc.UpdateBatchMetadata(context.Background(), 7, batchID, " Agustus ", domain.BatchMetadata{Region: "Surabaya"})
+	require.NoError(t, err, "archived batches stay editable")
+	assert.Equal(t, "Agustus", batch.Name)
+	assert.Equal(t, "Surabaya", batch.Region)
+	assert.Equal(t, domain.BatchSourceCSV, batch.Source)
+}
+
+type metadataBatchRepo struct {
+	domain.BatchRepository
+	batch *domain.Batch
+}
+
+func (r *metadataBatchRepo) GetBatchByID(ctx context.Context, id uuid.UUID) (*domain.Batch, error) {
+	return r.batch, nil
+}
+
+func (r *metadataBatchRepo) GetBatchPermission(ctx context.Context, batchID uuid.UUID, userID int64) (domain.BatchPermission, error) {
+	return "", nil
+}
+
+func (r *metadataBatchRepo) UpdateBatchMetadata(ctx context.Context, id uuid.UUID, name string, meta domain.BatchMetadata) error {
+	r.batch.Name, r.batch.BatchMetadata = name, meta
+	return nil
+}
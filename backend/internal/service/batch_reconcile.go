@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+// maxReconcileListItems bounds each orphan list in a ReconciliationReport.
+const maxReconcileListItems = 1000
+
+// connoteMatcher is one way two connotes may refer to the same shipment.
+// System connotes are expanded into candidate keys and looked up among the
+// field connotes' keys.
+type connoteMatcher struct {
+	reason     string
+	score      float64
+	fieldKey   func(string) string
+	systemKeys func(string) []string
+}
+
+func sameKey(key func(string) string) func(string) []string {
+	return func(c string) []string { return []string{key(c)} }
+}
+
+func leadingZerosKey(c string) string { return strings.TrimLeft(normalizeConnote(c), "0") }
+
+func prefixKey(c string) string {
+	return strings.TrimLeft(strings.TrimLeftFunc(normalizeConnote(c), unicode.IsLetter), "0")
+}
+
+// connoteMatchers are tried strongest first.
+var connoteMatchers = []connoteMatcher{
+	{domain.MatchNormalized, 0.95, normalizeConnote, sameKey(normalizeConnote)},
+	{domain.MatchLeadingZeros, 0.9, leadingZerosKey, sameKey(leadingZerosKey)},
+	{domain.MatchPrefix, 0.8, prefixKey, sameKey(prefixKey)},
+	{domain.MatchTransposed, 0.7, normalizeConnote, func(c string) []string {
+		return adjacentTranspositions(normalizeConnote(c))
+	}},
+}
+
+func (s *batchService) ReconcileBatch(ctx context.Context, userID int64, batchID uuid.UUID) (*domain.ReconciliationReport, error) {
+	if err := s.verifyBatchAccess(ctx, batchID, userID, domain.BatchPermissionViewer); err != nil {
+		return nil, err
+	}
+	systemOnly, fieldOnly, err := s.batchRepo.ListOrphanBatchItems(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.ReconciliationReport{
+		BatchID:         batchID,
+		SystemOnlyCount: len(systemOnly),
+		FieldOnlyCount:  len(fieldOnly),
+		SystemOnly:      capOrphans(systemOnly),
+		FieldOnly:       capOrphans(fieldOnly),
+		Suggestions:     suggestConnoteMatches(systemOnly, fieldOnly),
+	}
+	report.Truncated = len(systemOnly) > maxReconcileListItems || len(fieldOnly) > maxReconcileListItems
+	return report, nil
+}
+
+func capOrphans(items []domain.OrphanItem) []domain.OrphanItem {
+	if len(items) > maxReconcileListItems {
+		return items[:maxReconcileListItems]
+	}
+	if items == nil {
+		return []domain.OrphanItem{}
+	}
+	return items
+}
+
+// AcceptConnoteMatches merges each pair; pairs that are no longer orphans
+// (already merged, or re-uploaded meanwhile) are reported, not fatal. Merged
+// items are left pending for the next (re)process run.
+func (s *batchService) AcceptConnoteMatches(ctx context.Context, userID int64, batchID uuid.UUID, matches []domain.ConnoteMatch) (*domain.AcceptMatchesResult, error) {
+	if err := s.verifyBatchWritable(ctx, batchID, userID); err != nil {
+		return nil, err
+	}
+	// A running job could be geocoding the field item being deleted.
+	if err := s.ensureNoActiveJob(ctx, batchID); err != nil {
+		return nil, err
+	}
+
+	result := &domain.AcceptMatchesResult{Rejected: []domain.RejectedMatch{}}
+	for _, m := range matches {
+		err := s.batchRepo.MergeOrphanBatchItems(ctx, batchID, m.SystemItemID, m.FieldItemID)
+		if errors.Is(err, domain.ErrNotOrphanPair) {
+			result.Rejected = append(result.Rejected, domain.RejectedMatch{ConnoteMatch: m, Error: err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Merged++
+	}
+	return result, nil
+}
+
+// suggestConnoteMatches pairs system-only with field-only items whose
+// connotes differ by formatting, leading zeros, a carrier prefix or one
+// adjacent transposition. Each item is suggested at most once, strongest
+// reason first; a connote that fits several field items is left alone.
+func suggestConnoteMatches(systemOnly, fieldOnly []domain.OrphanItem) []domain.ConnoteMatch {
+	matches := []domain.ConnoteMatch{}
+	usedSystem := make(map[uuid.UUID]bool)
+	usedField := make(map[uuid.UUID]bool)
+
+	for _, m := range connoteMatchers {
+		fieldIndex := make(map[string][]domain.OrphanItem)
+		for _, f := range fieldOnly {
+			if k := m.fieldKey(f.Connote); k != "" && !usedField[f.ItemID] {
+				fieldIndex[k] = append(fieldIndex[k], f)
+			}
+		}
+
+		for _, sys := range systemOnly {
+			if usedSystem[sys.ItemID] {
+				continue
+			}
+			var found []domain.OrphanItem
+			for _, k := range m.systemKeys(sys.Connote) {
+				for _, f := range fieldIndex[k] {
+					if !usedField[f.ItemID] {
+						found = append(found, f)
+					}
+				}
+			}
+			if len(found) != 1 {
+				continue
+			}
+			f := found[0]
+			usedSystem[sys.ItemID], usedField[f.ItemID] = true, true
+			matches = append(matches, domain.ConnoteMatch{
+				SystemItemID:  sys.ItemID,
+				FieldItemID:   f.ItemID,
+				SystemConnote: sys.Connote,
+				FieldConnote:  f.Connote,
+				Reason:        m.reason,
+				Score:         m.score,
+			})
+		}
+	}
+	return matches
+}
+
+// normalizeConnote upper-cases c and drops everything but letters and digits.
+func normalizeConnote(c string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, c)
+}
+
+// adjacentTranspositions returns c with each pair of adjacent, different
+// characters swapped.
+func adjacentTranspositions(c string) []string {
+	r := []rune(c)
+	out := make([]string, 0, len(r))
+	for i := 0; i+1 < len(r); i++ {
+		if r[i] == r[i+1] {
+			continue
+		}
+		r[i], r[i+1] = r[i+1], r[i]
+		out = append(out, string(r))
+		r[i], r[i+1] = r[i+1], r[i]
+	}
+	return out
+}
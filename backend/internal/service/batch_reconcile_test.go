@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+type reconcileBatchRepo struct {
+	domain.BatchRepository
+	merged [][2]uuid.UUID
+}
+
+func (r *reconcileBatchRepo) GetBatchByID(ctx context.Context, id uuid.UUID) (*domain.Batch, error) {
+	return &domain.Batch{ID: id, UserID: 7}, nil
+}
+
+func (r *reconcileBatchRepo) MergeOrphanBatchItems(ctx context.Context, batchID, systemItemID, fieldItemID uuid.UUID) error {
+	for _, m := range r.merged {
+		if m[1] == fieldItemID {
+			return domain.ErrNotOrphanPair
+		}
+	}
+	r.merged = append(r.merged, [2]uuid.UUID{systemItemID, fieldItemID})
+	return nil
+}
+
+func orphans(connotes ...string) []domain.OrphanItem {
+	items := make([]domain.OrphanItem, len(connotes))
+	for i, c := range connotes {
+		items[i] = domain.OrphanItem{ItemID: uuid.New(), Connote: c}
+	}
+	return items
+}
+
+func TestSuggestConnoteMatches(t *testing.T) {
+	system := orphans("jne-0042", "000777", "JNE123456", "TX1234", "AMB01", "AMB02", "NOMATCH")
+	field := orphans("JNE0042", "777", "123456", "TX2134", "AMB10", "XYZ")
+
+	got := map[string][2]string{}
+	for _, m := range suggestConnoteMatches(system, field) {
+		got[m.SystemConnote] = [2]string{m.FieldConnote, m.Reason}
+	}
+	assert.Equal(t, map[string][2]string{
+		"jne-0042":  {"JNE0042", domain.MatchNormalized},
+		"000777":    {"777", domain.MatchLeadingZeros},
+		"JNE123456": {"123456", domain.MatchPrefix},
+		"TX1234":    {"TX2134", domain.MatchTransposed},
+		"AMB01":     {"AMB10", domain.MatchTransposed},
+	}, got)
+}
+
+func TestSuggestConnoteMatches_SkipsAmbiguous(t *testing.T) {
+	// "0123" and "123" both fit "00123" once zeros are trimmed.
+	matches := suggestConnoteMatches(orphans("00123"), orphans("0123", "123"))
+	assert.Empty(t, matches)
+}
+
+func TestAcceptConnoteMatches(t *testing.T) {
+	repo := &reconcileBatchRepo{}
+	jobs := &transitionJobRepo{}
+	svc := &batchService{batchRepo: repo, jobRepo: jobs}
+
+	sys, field := uuid.New(), uuid.New()
+	matches := []domain.ConnoteMatch{
+		{SystemItemID: sys, FieldItemID: field},
+		{SystemItemID: uuid.New(), FieldItemID: field}, // field item already merged
+	}
+	result, err := svc.AcceptConnoteMatches(context.Background(), 7, uuid.New(), matches)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Merged)
+	require.Len(t, result.Rejected, 1)
+	assert.Equal(t, matches[1].SystemItemID, result.Rejected[0].SystemItemID)
+
+	jobs.latest = &domain.BatchJob{Status: domain.BatchJobRunning}
+	_, err = svc.AcceptConnoteMatches(context.Background(), 7, uuid.New(), matches)
+	assert.ErrorIs(t, err, domain.ErrBatchJobActive)
+}
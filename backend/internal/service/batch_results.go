@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+const (
+	defaultResultsPageSize = 100
+	maxResultsPageSize     = 1000
+)
+
+// ErrInvalidResultsQuery is returned for filter, sort or paging parameters
+// that cannot be satisfied.
+var ErrInvalidResultsQuery = errors.New("invalid results query")
+
+func (s *batchService) ListBatchResults(ctx context.Context, userID int64, batchID uuid.UUID, q domain.BatchItemQuery) (*domain.BatchItemPage, error) {
+	if err := validateBatchItemQuery(&q); err != nil {
+		return nil, err
+	}
+	if err := s.verifyBatchAccess(ctx, batchID, userID, domain.BatchPermissionViewer); err != nil {
+		return nil, err
+	}
+
+	page, err := s.batchRepo.QueryBatchItems(ctx, batchID, q)
+	if err != nil {
+		return nil, err
+	}
+	for i := range page.Items {
+		if page.Items[i].ReasonCode != "" {
+			explanation := page.Items[i].ReasonCode.Explain()
+			page.Items[i].Explanation = &explanation
+		}
+	}
+	return page, nil
+}
+
+// validateBatchItemQuery rejects impossible queries and applies defaults:
+// without a limit, results page at defaultResultsPageSize.
+func validateBatchItemQuery(q *domain.BatchItemQuery) error {
+	switch q.Sort {
+	case "", domain.BatchItemSortCreatedAt, domain.BatchItemSortUpdatedAt,
+		domain.BatchItemSortConnote, domain.BatchItemSortDistance:
+	default:
+		return fmt.Errorf("%w: unknown sort %q", ErrInvalidResultsQuery, q.Sort)
+	}
+
+	if q.Limit < 0 || q.Limit > maxResultsPageSize {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidResultsQuery, maxResultsPageSize)
+	}
+	if q.Limit == 0 {
+		q.Limit = defaultResultsPageSize
+	}
+
+	if q.MinDistanceKm != nil && q.MaxDistanceKm != nil && *q.MinDistanceKm > *q.MaxDistanceKm {
+		return fmt.Errorf("%w: min_distance_km is greater than max_distance_km", ErrInvalidResultsQuery)
+	}
+
+	if bb := q.BBox; bb != nil {
+		switch bb.Match {
+		case "", domain.BBoxMatchAny, domain.BBoxMatchField, domain.BBoxMatchSystem:
+		default:
+			return fmt.Errorf("%w: bbox_match must be any, field or system", ErrInvalidResultsQuery)
+		}
+		if bb.MinLng > bb.MaxLng || bb.MinLat > bb.MaxLat {
+			return fmt.Errorf("%w: bbox must be minLng,minLat,maxLng,maxLat", ErrInvalidResultsQuery)
+		}
+		if bb.MinLat < -90 || bb.MaxLat > 90 || bb.MinLng < -180 || bb.MaxLng > 180 {
+			return fmt.Errorf("%w: bbox is outside WGS84 bounds", ErrInvalidResultsQuery)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"geoaccuracy-backend/internal/domain"
+	"geoaccuracy-backend/internal/repository"
+)
+
+// ownedBatchRepo runs item queries against the SQL repository but skips the
+// ownership lookup.
+type ownedBatchRepo struct {
+	domain.BatchRepository
+	batch *domain.Batch
+}
+
+func (r *ownedBatchRepo) GetBatchByID(ctx context.Context, id uuid.UUID) (*domain.Batch, error) {
+	return r.batch, nil
+}
+
+var batchItemTestColumns = []string{
+	"id", "batch_id", "connote", "recipient_name", "system_address", "courier_id",
+	"system_lat", "system_lng", "field_lat", "field_lng",
+	"distance_km", "accuracy_level", "error", "geocode_status", "created_at", "updated_at",
+	"reason_code", "address_quality_score", "address_quality_flags",
+	"provider", "attempt_count", "from_cache",
+	"cache_status", "geocoded_city", "geocoded_province", "processed_at",
+	"review_status", "reviewer_id", "review_comment", "corrected_lat", "corrected_lng", "reviewed_at",
+}
+
+func batchItemTestRow(id, batchID uuid.UUID, connote string, distance interface{}) []driver.Value {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 123456000, time.UTC)
+	return []driver.Value{
+		id, batchID, connote, "", "", "K01",
+		nil, nil, nil, nil,
+		distance, "good", "", domain.ItemStatusCompleted, now, now,
+		"", nil, "{}",
+		"google", 1, true,
+		domain.CacheStatusExact, "Jakarta Pusat", "DKI Jakarta", now,
+		"unreviewed", nil, "", nil, nil, nil,
+	}
+}
+
+func TestListBatchResults_KeysetPagination(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	batchID := uuid.New()
+	svc := &batchService{batchRepo: &ownedBatchRepo{
+		BatchRepository: repository.NewBatchRepository(db),
+		batch:           &domain.Batch{ID: batchID, UserID: 7},
+	}}
+
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	rows := sqlmock.NewRows(batchItemTestColumns).
+		AddRow(batchItemTestRow(ids[0], batchID, "R1", 0.5)...).
+		AddRow(batchItemTestRow(ids[1], batchID, "R2", 1.25)...).
+		AddRow(batchItemTestRow(ids[2], batchID, "R3", nil)...)
+	mock.ExpectQuery(regexp.QuoteMeta(`connote ILIKE $3 ESCAPE '\'`)+`.*`+
+		regexp.QuoteMeta(`ORDER BY COALESCE(distance_km, 'Infinity'::float8) DESC, id DESC LIMIT $4`)).
+		WithArgs(batchID, sqlmock.AnyArg(), `%R\_%`, 3).
+		WillReturnRows(rows)
+
+	q := domain.BatchItemQuery{
+		AccuracyLevels: []string{"good"},
+		Connote:        "R_",
+		Sort:           domain.BatchItemSortDistance,
+		Desc:           true,
+		Limit:          2,
+	}
+	page, err := svc.ListBatchResults(context.Background(), 7, batchID, q)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	assert.True(t, page.HasMore)
+	require.NotEmpty(t, page.NextCursor)
+
+	// The next page continues after the last returned item.
+	mock.ExpectQuery(regexp.QuoteMeta(`(COALESCE(distance_km, 'Infinity'::float8), id) < ($4::float8, $5)`)).
+		WithArgs(batchID, sqlmock.AnyArg(), `%R\_%`, "1.25", ids[1], 3).
+		WillReturnRows(sqlmock.NewRows(batchItemTestColumns).AddRow(batchItemTestRow(ids[2], batchID, "R3", nil)...))
+
+	q.Cursor = page.NextCursor
+	page, err = svc.ListBatchResults(context.Background(), 7, batchID, q)
+	require.NoError(t, err)
+	require.Len(t, page.Items, 1)
+	assert.False(t, page.HasMore)
+	assert.Empty(t, page.NextCursor)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	// A cursor from another sort key or direction is rejected before any
+	// query runs.
+	q.Desc = false
+	_, err = svc.ListBatchResults(context.Background(), 7, batchID, q)
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+	q.Desc, q.Sort = true, domain.BatchItemSortConnote
+	_, err = svc.ListBatchResults(context.Background(), 7, batchID, q)
+	assert.ErrorIs(t, err, domain.ErrInvalidCursor)
+}
+
+func TestListBatchResults_DefaultsPageSize(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	batchID := uuid.New()
+	svc := &batchService{batchRepo: &ownedBatchRepo{
+		BatchRepository: repository.NewBatchRepository(db),
+		batch:           &domain.Batch{ID: batchID, UserID: 7},
+	}}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`LIMIT $2`)).
+		WithArgs(batchID, defaultResultsPageSize+1).
+		WillReturnRows(sqlmock.NewRows(batchItemTestColumns))
+
+	page, err := svc.ListBatchResults(context.Background(), 7, batchID, domain.BatchItemQuery{})
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+	assert.False(t, page.HasMore)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestListBatchResults_RejectsInvalidQuery(t *testing.T) {
+	min, max := 5.0, 1.0
+	for name, q := range map[string]domain.BatchItemQuery{
+		"sort":     {Sort: "recipient_name"},
+		"limit":    {Limit: maxResultsPageSize + 1},
+		"distance": {MinDistanceKm: &min, MaxDistanceKm: &max},
+		"bbox":     {BBox: &domain.BoundingBox{MinLng: 107, MinLat: -6, MaxLng: 106, MaxLat: -5}},
+		"match":    {BBox: &domain.BoundingBox{MinLng: 106, MinLat: -7, MaxLng: 107, MaxLat: -6, Match: "both"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			svc := &batchService{}
+			_, err := svc.ListBatchResults(context.Background(), 7, uuid.New(), q)
+			assert.ErrorIs(t, err, ErrInvalidResultsQuery)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+const (
+	defaultReviewPageSize = 50
+	maxReviewPageSize     = 500
+	maxReviewItems        = 1000 // per bulk action
+	maxReviewComment      = 2000
+)
+
+// ErrInvalidReview is returned for a review queue query or review action
+// that cannot be satisfied, such as an unknown status or a coordinate out of
+// range.
+var ErrInvalidReview = errors.New("invalid review")
+
+// flaggedAccuracyLevels are what a review queue shows by default.
+var flaggedAccuracyLevels = []string{"inaccurate", domain.AccuracyUnverifiable}
+
+func (s *batchService) GetReviewQueue(ctx context.Context, userID int64, batchID uuid.UUID, q domain.ReviewQueueQuery) (*domain.ReviewQueuePage, error) {
+	for _, st := range q.Statuses {
+		if domain.ReviewSources(st) == nil {
+			return nil, fmt.Errorf("%w: unknown review status %q", ErrInvalidReview, st)
+		}
+	}
+	if len(q.AccuracyLevels) == 0 {
+		q.AccuracyLevels = flaggedAccuracyLevels
+	}
+	if err := checkReviewPage(&q.Limit, q.Offset); err != nil {
+		return nil, err
+	}
+	if err := s.verifyBatchAccess(ctx, batchID, userID, domain.BatchPermissionViewer); err != nil {
+		return nil, err
+	}
+	return s.batchRepo.ListReviewQueue(ctx, batchID, q)
+}
+
+func (s *batchService) ListMyReviews(ctx context.Context, userID int64, limit int) ([]domain.ReviewItem, error) {
+	if err := checkReviewPage(&limit, 0); err != nil {
+		return nil, err
+	}
+	return s.batchRepo.ListClaimedReviews(ctx, userID, limit)
+}
+
+// ReviewBatchItems moves the listed items to req.Status. Items whose current
+// state does not allow the transition, or that another reviewer has claimed,
+// are skipped rather than failing the whole request. Claiming needs no prior
+// claim; releasing and resolving need the caller's own.
+func (s *batchService) ReviewBatchItems(ctx context.Context, userID int64, batchID uuid.UUID, req domain.ReviewRequest) (*domain.ReviewResult, error) {
+	from := domain.ReviewSources(req.Status)
+	if from == nil {
+		return nil, fmt.Errorf("%w: unknown review status %q", ErrInvalidReview, req.Status)
+	}
+	if len(req.ItemIDs) == 0 || len(req.ItemIDs) > maxReviewItems {
+		return nil, fmt.Errorf("%w: between 1 and %d items per request", ErrInvalidReview, maxReviewItems)
+	}
+	req.Comment = strings.TrimSpace(req.Comment)
+	if len(req.Comment) > maxReviewComment {
+		return nil, fmt.Errorf("%w: comment is longer than %d bytes", ErrInvalidReview, maxReviewComment)
+	}
+	if (req.CorrectedLat == nil) != (req.CorrectedLng == nil) {
+		return nil, fmt.Errorf("%w: set both corrected_lat and corrected_lng or neither", ErrInvalidReview)
+	}
+	if req.CorrectedLat != nil {
+		if req.Status != domain.ReviewAddressCorrected {
+			return nil, fmt.Errorf("%w: a corrected coordinate needs status %s", ErrInvalidReview, domain.ReviewAddressCorrected)
+		}
+		if *req.CorrectedLat < -90 || *req.CorrectedLat > 90 || *req.CorrectedLng < -180 || *req.CorrectedLng > 180 {
+			return nil, fmt.Errorf("%w: corrected coordinate out of range", ErrInvalidReview)
+		}
+	}
+	if err := s.verifyBatchWritable(ctx, batchID, userID); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.batchRepo.ApplyReview(ctx, batchID, domain.ReviewUpdate{
+		ItemIDs:      req.ItemIDs,
+		From:         from,
+		To:           req.Status,
+		ReviewerID:   userID,
+		RequireOwn:   req.Status != domain.ReviewInReview,
+		Comment:      req.Comment,
+		CorrectedLat: req.CorrectedLat,
+		CorrectedLng: req.CorrectedLng,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	moved := make(map[uuid.UUID]bool, len(updated))
+	for _, id := range updated {
+		moved[id] = true
+	}
+	result := &domain.ReviewResult{Updated: len(updated), Skipped: []uuid.UUID{}}
+	for _, id := range req.ItemIDs {
+		if !moved[id] {
+			result.Skipped = append(result.Skipped, id)
+			moved[id] = true // report duplicates once
+		}
+	}
+	return result, nil
+}
+
+func checkReviewPage(limit *int, offset int) error {
+	if *limit == 0 {
+		*limit = defaultReviewPageSize
+	}
+	if *limit < 0 || *limit > maxReviewPageSize {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidReview, maxReviewPageSize)
+	}
+	if offset < 0 {
+		return fmt.Errorf("%w: offset must not be negative", ErrInvalidReview)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"geoaccuracy-backend/internal/domain"
+	"geoaccuracy-backend/internal/repository"
+)
+
+func TestReviewTransitions(t *testing.T) {
+	assert.Equal(t, []domain.ReviewStatus{domain.ReviewInReview}, domain.ReviewSources(domain.ReviewAccepted))
+	assert.Contains(t, domain.ReviewSources(domain.ReviewInReview), domain.ReviewCourierDisputed, "resolved items can be reopened")
+	assert.Nil(t, domain.ReviewSources("approved"))
+
+	assert.Equal(t, "accurate", domain.ReviewAddressCorrected.AccuracyOverride())
+	assert.Equal(t, "inaccurate", domain.ReviewCourierDisputed.AccuracyOverride())
+	assert.Empty(t, domain.ReviewInReview.AccuracyOverride())
+}
+
+func TestReviewBatchItems_ResolvesOwnClaims(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	batchID := uuid.New()
+	svc := &batchService{batchRepo: &ownedBatchRepo{
+		BatchRepository: repository.NewBatchRepository(db),
+		batch:           &domain.Batch{ID: batchID, UserID: 7},
+	}}
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+	// Only the first item is claimed by user 7; the second is skipped.
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`WHERE batch_id = $1 AND id = ANY($2) AND review_status = ANY($3)`)+`.*`+
+		regexp.QuoteMeta(`($10::bigint IS NULL OR reviewer_id = $10)`)).
+		WithArgs(batchID, pq.Array(ids), pq.Array([]domain.ReviewStatus{domain.ReviewInReview}),
+			domain.ReviewCourierDisputed, int64(7), "wrong drop point", false, nil, nil, int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "connote"}).AddRow(ids[0], "R1"))
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE courier_performance SET accuracy_override`)).
+		WithArgs(batchID.String(), `{"R1"}`, "inaccurate").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	result, err := svc.ReviewBatchItems(context.Background(), 7, batchID, domain.ReviewRequest{
+		ItemIDs: ids,
+		Status:  domain.ReviewCourierDisputed,
+		Comment: " wrong drop point ",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, []uuid.UUID{ids[1]}, result.Skipped)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewBatchItems_RejectsInvalidRequest(t *testing.T) {
+	lat, lng, far := -6.2, 106.8, 200.0
+	item := []uuid.UUID{uuid.New()}
+	for name, req := range map[string]domain.ReviewRequest{
+		"status":     {ItemIDs: item, Status: "approved"},
+		"no items":   {Status: domain.ReviewInReview},
+		"half point": {ItemIDs: item, Status: domain.ReviewAddressCorrected, CorrectedLat: &lat},
+		"range":      {ItemIDs: item, Status: domain.ReviewAddressCorrected, CorrectedLat: &lat, CorrectedLng: &far},
+		"not fixing": {ItemIDs: item, Status: domain.ReviewAccepted, CorrectedLat: &lat, CorrectedLng: &lng},
+	} {
+		svc := &batchService{}
+		_, err := svc.ReviewBatchItems(context.Background(), 7, uuid.New(), req)
+		assert.ErrorIs(t, err, ErrInvalidReview, name)
+	}
+}
@@ -3,10 +3,13 @@ package service
 import (
 	"context"
 	"errors"
+	"geoaccuracy-backend/config"
 	"geoaccuracy-backend/internal/domain"
+	"geoaccuracy-backend/internal/repository"
 	"geoaccuracy-backend/pkg/utils"
 	"log"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,32 +17,52 @@ import (
 	ws "geoaccuracy-backend/internal/websocket"
 )
 
-// errAccessDenied is returned when the authenticated user does not own the requested batch.
+// errAccessDenied is returned when the authenticated user may not access the
+// requested batch at the level the operation needs.
 var errAccessDenied = errors.New("batch not found or access denied")
 
+// ErrEmptyReprocessFilter is returned when a reprocess request selects no
+// criteria; re-running everything is what ProcessBatch is for.
+var ErrEmptyReprocessFilter = errors.New("reprocess filter must set at least one criterion")
+
 type batchService struct {
 	batchRepo      domain.BatchRepository
+	jobRepo        domain.BatchJobRepository
 	geoService     GeocodeService
 	historyService *HistoryService
 	analyticsRepo  domain.AnalyticsRepository // for courier_performance population
 	hub            *ws.Hub
+	classifier     resultClassifier
+	settingsRepo   repository.SettingsRepository // address quality policy threshold
+
+	// running maps batch IDs to the cancel funcs of jobs executing in this
+	// process, so pause and cancel take effect after the current item.
+	running sync.Map
 }
 
-func NewBatchService(repo domain.BatchRepository, geoService GeocodeService, historySvc *HistoryService, analyticsRepo domain.AnalyticsRepository, hub *ws.Hub) domain.BatchService {
+func NewBatchService(repo domain.BatchRepository, jobRepo domain.BatchJobRepository, geoService GeocodeService, historySvc *HistoryService, analyticsRepo domain.AnalyticsRepository, hub *ws.Hub, areaSvc AreaService, settingsRepo repository.SettingsRepository, cfg *config.Config) domain.BatchService {
 	return &batchService{
 		batchRepo:      repo,
+		jobRepo:        jobRepo,
+		settingsRepo:   settingsRepo,
 		geoService:     geoService,
 		historyService: historySvc,
 		analyticsRepo:  analyticsRepo,
 		hub:            hub,
+		classifier:     resultClassifier{areaSvc: areaSvc, minPrecision: cfg.MinVerifiablePrecision},
 	}
 }
 
-func (s *batchService) CreateBatch(ctx context.Context, userID int64, name string) (*domain.Batch, error) {
+func (s *batchService) CreateBatch(ctx context.Context, userID int64, name string, meta domain.BatchMetadata) (*domain.Batch, error) {
+	if err := normalizeBatchMetadata(&meta); err != nil {
+		return nil, err
+	}
 	batch := &domain.Batch{
-		UserID: userID,
-		Name:   name,
-		Status: domain.BatchStatusDraft,
+		UserID:        userID,
+		Name:          name,
+		Status:        domain.BatchStatusDraft,
+		BatchMetadata: meta,
+		Access:        domain.BatchPermissionOwner,
 	}
 	err := s.batchRepo.CreateBatch(ctx, batch)
 	if err != nil {
@@ -52,48 +75,151 @@ func (s *batchService) GetBatch(ctx context.Context, id uuid.UUID) (*domain.Batc
 	return s.batchRepo.GetBatchByID(ctx, id)
 }
 
-func (s *batchService) ListUserBatches(ctx context.Context, userID int64) ([]domain.Batch, error) {
-	return s.batchRepo.GetBatchesByUserID(ctx, userID)
+func (s *batchService) ListUserBatches(ctx context.Context, userID int64, filter domain.BatchFilter) ([]domain.Batch, error) {
+	if err := normalizeBatchFilter(&filter); err != nil {
+		return nil, err
+	}
+	return s.batchRepo.GetBatchesByUserID(ctx, userID, filter)
 }
 
-// verifyBatchOwnership fetches the batch and confirms it belongs to userID.
-// Returns errAccessDenied if the batch is missing or owned by another user.
-func (s *batchService) verifyBatchOwnership(ctx context.Context, batchID uuid.UUID, userID int64) error {
+// verifyBatchAccess confirms userID may act on the batch at the need level,
+// as its owner or through a share with them or one of their teams. Returns
+// errAccessDenied if the batch is missing or the user's access is too low.
+func (s *batchService) verifyBatchAccess(ctx context.Context, batchID uuid.UUID, userID int64, need domain.BatchPermission) error {
+	_, err := s.batchWithAccess(ctx, batchID, userID, need)
+	return err
+}
+
+// batchWithAccess is verifyBatchAccess for callers that need the batch; its
+// Access is set to the user's permission.
+func (s *batchService) batchWithAccess(ctx context.Context, batchID uuid.UUID, userID int64, need domain.BatchPermission) (*domain.Batch, error) {
 	batch, err := s.batchRepo.GetBatchByID(ctx, batchID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if batch == nil || batch.UserID != userID {
-		return errAccessDenied
+	if batch == nil {
+		return nil, errAccessDenied
 	}
-	return nil
+	if batch.UserID == userID {
+		batch.Access = domain.BatchPermissionOwner
+		return batch, nil
+	}
+	if need == domain.BatchPermissionOwner {
+		return nil, errAccessDenied
+	}
+	perm, err := s.batchRepo.GetBatchPermission(ctx, batchID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !perm.Allows(need) {
+		return nil, errAccessDenied
+	}
+	batch.Access = perm
+	return batch, nil
 }
 
-// UploadSystemData validates batch ownership then bulk-inserts/updates system records.
-func (s *batchService) UploadSystemData(ctx context.Context, userID int64, batchID uuid.UUID, records []domain.SystemRecord) error {
+// UploadSystemData validates batch ownership then bulk-inserts/updates system
+// records. Every address is scored so the shipper gets back the ones that are
+// unlikely to geocode before any provider call is spent on them.
+func (s *batchService) UploadSystemData(ctx context.Context, userID int64, batchID uuid.UUID, records []domain.SystemRecord, mode domain.UploadMode) (*domain.UploadReport, error) {
 	// FIX BUG-03: verify the batch belongs to this user before allowing writes.
-	if err := s.verifyBatchOwnership(ctx, batchID, userID); err != nil {
-		return err
+	if err := s.verifyBatchWritable(ctx, batchID, userID); err != nil {
+		return nil, err
+	}
+
+	valid, validation := validateSystemRecords(records, normalizeUploadMode(mode))
+	report := s.newUploadReport(int(userID))
+	report.Validation = validation
+	if rejectUpload(validation) {
+		return report, domain.ErrUploadRejected
 	}
+	if err := s.storeSystemRecords(ctx, batchID, valid, report); err != nil {
+		return nil, err
+	}
+	if err := s.batchRepo.ReplaceUploadIssues(ctx, batchID, domain.UploadSourceSystem, validation.Issues); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *batchService) newUploadReport(userID int) *domain.UploadReport {
+	return &domain.UploadReport{
+		QualityMinScore: s.minQualityScore(userID),
+		LowQuality:      []domain.LowQualityAddress{},
+	}
+}
+
+// storeSystemRecords scores and upserts records, adding them to report.
+func (s *batchService) storeSystemRecords(ctx context.Context, batchID uuid.UUID, records []domain.SystemRecord, report *domain.UploadReport) error {
 	var items []domain.BatchItem
 	for _, rec := range records {
+		quality := ScoreAddress(rec.SystemAddress)
+		score := quality.Score
+		flags := make([]string, len(quality.Flags))
+		for i, f := range quality.Flags {
+			flags[i] = string(f)
+		}
+
 		items = append(items, domain.BatchItem{
-			BatchID:       batchID,
-			Connote:       rec.Connote,
-			RecipientName: rec.RecipientName,
-			SystemAddress: rec.SystemAddress,
-			GeocodeStatus: "pending",
+			BatchID:             batchID,
+			Connote:             rec.Connote,
+			RecipientName:       rec.RecipientName,
+			SystemAddress:       rec.SystemAddress,
+			GeocodeStatus:       domain.ItemStatusPending,
+			AddressQualityScore: &score,
+			AddressQualityFlags: flags,
 		})
+
+		if score < report.QualityMinScore {
+			report.LowQuality = append(report.LowQuality, domain.LowQualityAddress{
+				Connote:       rec.Connote,
+				SystemAddress: rec.SystemAddress,
+				Score:         score,
+				Flags:         quality.Flags,
+			})
+		}
 	}
-	return s.batchRepo.UpsertBatchItems(ctx, items)
+	if err := s.batchRepo.UpsertBatchItems(ctx, items); err != nil {
+		return err
+	}
+
+	report.Accepted += len(items)
+	report.LowQualityCount = len(report.LowQuality)
+	return nil
+}
+
+// minQualityScore returns the user's low-quality threshold, falling back to
+// the default when settings cannot be read.
+func (s *batchService) minQualityScore(userID int) int {
+	if s.settingsRepo != nil {
+		if settings, err := s.settingsRepo.GetByUserID(userID); err == nil && settings != nil {
+			return settings.MinPremiumQualityScore
+		}
+	}
+	return domain.DefaultMinPremiumQualityScore
 }
 
 // UploadFieldData validates batch ownership then stores field GPS records.
-func (s *batchService) UploadFieldData(ctx context.Context, userID int64, batchID uuid.UUID, records []domain.FieldRecord) error {
+func (s *batchService) UploadFieldData(ctx context.Context, userID int64, batchID uuid.UUID, records []domain.FieldRecord, mode domain.UploadMode) (*domain.UploadValidation, error) {
 	// FIX BUG-03: verify the batch belongs to this user before allowing writes.
-	if err := s.verifyBatchOwnership(ctx, batchID, userID); err != nil {
-		return err
+	if err := s.verifyBatchWritable(ctx, batchID, userID); err != nil {
+		return nil, err
+	}
+
+	valid, validation := validateFieldRecords(records, normalizeUploadMode(mode))
+	if rejectUpload(validation) {
+		return validation, domain.ErrUploadRejected
+	}
+	if err := s.storeFieldRecords(ctx, batchID, valid); err != nil {
+		return nil, err
+	}
+	if err := s.batchRepo.ReplaceUploadIssues(ctx, batchID, domain.UploadSourceField, validation.Issues); err != nil {
+		return nil, err
 	}
+	return validation, nil
+}
+
+func (s *batchService) storeFieldRecords(ctx context.Context, batchID uuid.UUID, records []domain.FieldRecord) error {
 	var items []domain.BatchItem
 	for _, rec := range records {
 		lat := rec.FieldLat
@@ -110,185 +236,482 @@ func (s *batchService) UploadFieldData(ctx context.Context, userID int64, batchI
 	return s.batchRepo.UpsertBatchItems(ctx, items)
 }
 
+// batchJobChunkSize items are geocoded, saved and checkpointed together, so an
+// interrupted job loses at most one chunk of work.
+const batchJobChunkSize = 100
+
+// ProcessBatch queues the whole batch for processing by the worker pool and
+// returns immediately; progress is reported over the batch WebSocket.
 func (s *batchService) ProcessBatch(ctx context.Context, userID int64, batchID uuid.UUID) error {
 	// FIX BUG-03: verify the batch belongs to this user before allowing processing.
-	if err := s.verifyBatchOwnership(ctx, batchID, userID); err != nil {
+	batch, err := s.writableBatch(ctx, batchID, userID)
+	if err != nil {
 		return err
 	}
-
-	err := s.batchRepo.UpdateBatchStatus(ctx, batchID, domain.BatchStatusProcessing)
-	if err != nil {
+	// Checked before the items are reset: an active (or paused) job would
+	// otherwise geocode the whole batch again.
+	if err := s.ensureNoActiveJob(ctx, batchID); err != nil {
 		return err
 	}
 
-	// Run processing asynchronously so HTTP request can return immediately
-	go func() {
-		// Create a separate background context so HTTP cancellation doesn't stop processing
-		bgCtx := context.Background()
+	// Items are marked before the job exists so a worker can never claim it and
+	// find nothing to do.
+	if _, err := s.batchRepo.MarkBatchItemsPending(ctx, batchID, domain.ReprocessFilter{}); err != nil {
+		return err
+	}
+	return s.startBatchJob(ctx, batch, func() error {
+		_, err := s.jobRepo.Enqueue(ctx, batchID, userID, false)
+		return err
+	})
+}
 
-		items, err := s.batchRepo.GetBatchItemsByBatchID(bgCtx, batchID)
-		if err != nil {
-			s.batchRepo.UpdateBatchStatus(bgCtx, batchID, domain.BatchStatusFailed)
-			if s.hub != nil {
-				s.hub.Broadcast <- ws.Message{Type: "error", BatchID: batchID.String(), Payload: err.Error()}
-			}
-			return
+// startBatchJob marks the batch processing and then runs queue, which makes
+// its job claimable. In the other order a worker could claim and finish a
+// small batch before the status is set, leaving it processing for good. If
+// queue fails the previous status is restored, unless another request
+// queued a job meanwhile.
+func (s *batchService) startBatchJob(ctx context.Context, batch *domain.Batch, queue func() error) error {
+	if err := s.batchRepo.UpdateBatchStatus(ctx, batch.ID, domain.BatchStatusProcessing); err != nil {
+		return err
+	}
+	err := queue()
+	if err != nil && !errors.Is(err, domain.ErrBatchJobActive) {
+		if restoreErr := s.batchRepo.UpdateBatchStatus(ctx, batch.ID, batch.Status); restoreErr != nil {
+			log.Printf("WARN: batch %v: restore status %s: %v", batch.ID, batch.Status, restoreErr)
 		}
+	}
+	return err
+}
 
-		total := len(items)
-		var results []domain.ValidationResult
-		var updatedItems []domain.BatchItem
-		var courierEvents []domain.CourierPerformance
+// ReprocessBatch queues only the items matching the filter, e.g. the ones that
+// failed during a provider outage. Unlike ProcessBatch it refuses to touch a
+// batch with an active job, since that job would pick the items up without
+// honouring BypassCache.
+func (s *batchService) ReprocessBatch(ctx context.Context, userID int64, batchID uuid.UUID, req domain.ReprocessRequest) (int, error) {
+	batch, err := s.writableBatch(ctx, batchID, userID)
+	if err != nil {
+		return 0, err
+	}
+	if req.IsEmpty() {
+		return 0, ErrEmptyReprocessFilter
+	}
+	if err := s.ensureNoActiveJob(ctx, batchID); err != nil {
+		return 0, err
+	}
 
-		// IN-MEMORY CACHE FOR BATCH (Best practice for thousands of identical addresses)
-		memCache := make(map[string]*domain.GeocodeResponse)
+	matched, err := s.batchRepo.MarkBatchItemsPending(ctx, batchID, req.ReprocessFilter)
+	if err != nil || matched == 0 {
+		return 0, err
+	}
+	err = s.startBatchJob(ctx, batch, func() error {
+		_, err := s.jobRepo.Enqueue(ctx, batchID, userID, req.BypassCache)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return matched, nil
+}
 
-		for i, item := range items {
-			if item.SystemAddress == "" {
-				s.emitProgress(batchID.String(), i+1, total)
-				continue
-			}
+func isActiveJobStatus(status domain.BatchJobStatus) bool {
+	return status == domain.BatchJobQueued || status == domain.BatchJobRunning || status == domain.BatchJobPaused
+}
 
-			normalizeAddr := strings.ToLower(strings.TrimSpace(item.SystemAddress))
-			var geoRes *domain.GeocodeResponse
-			var geoErr error
+// GetBatchJob returns the most recent processing job of the batch, or nil.
+func (s *batchService) GetBatchJob(ctx context.Context, userID int64, batchID uuid.UUID) (*domain.BatchJob, error) {
+	if err := s.verifyBatchAccess(ctx, batchID, userID, domain.BatchPermissionViewer); err != nil {
+		return nil, err
+	}
+	return s.jobRepo.GetLatestByBatchID(ctx, batchID)
+}
 
-			if cachedRes, ok := memCache[normalizeAddr]; ok {
-				geoRes = cachedRes
-			} else {
-				geoRes, geoErr = s.geoService.GeocodeAddress(bgCtx, int(userID), item.SystemAddress)
-				if geoErr == nil {
-					memCache[normalizeAddr] = geoRes
-				}
-			}
+// RunBatchJob processes the pending items of the job's batch chunk by chunk.
+// Each chunk is saved before checkpoint is called, so a job that is
+// interrupted resumes with exactly the items that are still pending.
+func (s *batchService) RunBatchJob(ctx context.Context, job *domain.BatchJob, checkpoint func(processed, total int) error) error {
+	runCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	s.running.Store(job.BatchID, stop)
+	defer s.running.Delete(job.BatchID)
+
+	err := s.runBatchJob(runCtx, job, checkpoint)
+	if err != nil && runCtx.Err() != nil && ctx.Err() == nil {
+		// Stopped by PauseBatch/CancelBatch rather than by the worker pool.
+		return domain.ErrBatchJobStopped
+	}
+	return err
+}
 
-			outItem := domain.BatchItem{
-				ID:        item.ID,
-				BatchID:   item.BatchID,
-				Connote:   item.Connote,
-				CourierID: item.CourierID,
-			}
+func (s *batchService) runBatchJob(ctx context.Context, job *domain.BatchJob, checkpoint func(processed, total int) error) error {
+	batchID := job.BatchID
+	remaining, err := s.batchRepo.CountBatchItemsByStatus(ctx, batchID, domain.ItemStatusPending)
+	if err != nil {
+		return err
+	}
+	processed := job.Processed
+	total := processed + remaining
+	if err := checkpoint(processed, total); err != nil {
+		return err
+	}
 
-			if geoErr != nil {
-				outItem.Error = geoErr.Error()
-				outItem.GeocodeStatus = "failed"
-
-				// Still record the courier event as an error
-				if item.CourierID != "" && s.analyticsRepo != nil {
-					dist := 0.0
-					courierEvents = append(courierEvents, domain.CourierPerformance{
-						UserID:                 userID,
-						BatchID:                batchID.String(),
-						CourierID:              item.CourierID,
-						OrderID:                item.Connote,
-						ReportedLat:            safeFloat(item.FieldLat),
-						ReportedLng:            safeFloat(item.FieldLng),
-						DistanceVarianceMeters: &dist,
-						AccuracyStatus:         "error",
-						SLAStatus:              "unknown",
-						EventTimestamp:         time.Now(),
-					})
-				}
+	// IN-MEMORY CACHE FOR BATCH (Best practice for thousands of identical addresses)
+	memCache := make(map[string]*domain.GeocodeResponse)
+
+	for {
+		items, err := s.batchRepo.GetPendingBatchItems(ctx, batchID, batchJobChunkSize)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			break
+		}
+
+		// Geocode the chunk up front: cache hits in one query, the rest in
+		// parallel per provider. On interruption only part of it resolves.
+		results, stopErr := s.geocodeChunk(ctx, job, items, memCache)
+
+		var updatedItems []domain.BatchItem
+		var courierEvents []domain.CourierPerformance
+		for _, item := range items {
+			var outItem domain.BatchItem
+			var event *domain.CourierPerformance
+			if strings.TrimSpace(item.SystemAddress) == "" {
+				outItem = skippedItem(item)
 			} else {
-				sysLat := geoRes.Lat
-				sysLng := geoRes.Lng
-				outItem.SystemLat = &sysLat
-				outItem.SystemLng = &sysLng
-				outItem.GeocodeStatus = "completed"
-
-				if item.FieldLat != nil && item.FieldLng != nil {
-					dist := utils.CalculateDistance(sysLat, sysLng, *item.FieldLat, *item.FieldLng)
-					accuracy := utils.EvaluateAccuracy(dist)
-
-					outItem.DistanceKm = &dist
-					outItem.AccuracyLevel = accuracy
-
-					results = append(results, domain.ValidationResult{
-						SystemAddress: item.SystemAddress,
-						GeoLat:        sysLat,
-						GeoLng:        sysLng,
-						FieldLat:      *item.FieldLat,
-						FieldLng:      *item.FieldLng,
-						DistanceKm:    dist,
-						AccuracyLevel: accuracy,
-						Provider:      geoRes.Provider,
-					})
-
-					// Build courier performance event if courier is identified
-					if item.CourierID != "" && s.analyticsRepo != nil {
-						distMeters := dist * 1000
-						slaStatus := "on_time" // default — extend later with delivery date logic
-						courierEvents = append(courierEvents, domain.CourierPerformance{
-							UserID:                 userID,
-							BatchID:                batchID.String(),
-							CourierID:              item.CourierID,
-							OrderID:                item.Connote,
-							ReportedLat:            *item.FieldLat,
-							ReportedLng:            *item.FieldLng,
-							ActualLat:              &sysLat,
-							ActualLng:              &sysLng,
-							DistanceVarianceMeters: &distMeters,
-							AccuracyStatus:         accuracy,
-							SLAStatus:              slaStatus,
-							EventTimestamp:         time.Now(),
-						})
-					}
+				geo, ok := s.chunkResult(item, results, memCache)
+				if !ok && stopErr != nil {
+					continue // interrupted before this address resolved; stays pending
+				}
+				if !ok {
+					// Never expected; failing beats re-fetching the item forever.
+					geo = batchGeocode{err: ErrGeocodeFailed}
 				}
+				outItem, event = s.processItem(ctx, job, item, geo)
 			}
-
 			updatedItems = append(updatedItems, outItem)
-
+			if event != nil {
+				courierEvents = append(courierEvents, *event)
+			}
+			processed++
 			// Emit progress immediately after processing this item
-			s.emitProgress(batchID.String(), i+1, total)
+			s.emitProgress(batchID.String(), processed, total)
 		}
 
-		// FIX BUG-05: Guard before UpsertBatchItems — if every item was skipped
-		// (e.g. all system_address fields were empty), updatedItems is nil/empty.
-		// While the repository already has an empty-slice guard at the SQL level,
-		// calling UpsertBatchItems with zero items is a no-op that could still
-		// mark the batch as "completed" with 0 actual results on disk.
-		if len(updatedItems) == 0 {
-			log.Printf("WARN: ProcessBatch batch=%v produced 0 updatedItems — all records were skipped", batchID)
-			s.batchRepo.UpdateBatchStatus(bgCtx, batchID, domain.BatchStatusCompleted)
-			if s.hub != nil {
-				s.hub.Broadcast <- ws.Message{Type: "completed", BatchID: batchID.String(), Payload: "Batch completed (all records skipped due to empty addresses)"}
-			}
-			return
+		// Save finished items even when stopping so they are not geocoded again.
+		saveCtx := context.WithoutCancel(ctx)
+		if err := s.batchRepo.UpsertBatchItems(saveCtx, updatedItems); err != nil {
+			return err
+		}
+		s.saveCourierEvents(saveCtx, batchID, courierEvents)
+		if err := checkpoint(processed, total); err != nil {
+			return err
+		}
+		if stopErr != nil {
+			return stopErr
 		}
+	}
+
+	if total == 0 {
+		log.Printf("WARN: ProcessBatch batch=%v produced 0 updatedItems — all records were skipped", batchID)
+		s.batchRepo.UpdateBatchStatus(ctx, batchID, domain.BatchStatusCompleted)
+		if s.hub != nil {
+			s.hub.Broadcast <- ws.Message{Type: "completed", BatchID: batchID.String(), Payload: "Batch completed (no records to process)"}
+		}
+		return nil
+	}
+
+	if err := s.batchRepo.UpdateBatchStatus(ctx, batchID, domain.BatchStatusCompleted); err != nil {
+		return err
+	}
+	if s.hub != nil {
+		s.hub.Broadcast <- ws.Message{Type: "completed", BatchID: batchID.String(), Payload: "Batch processing completed successfully"}
+	}
+	s.saveBatchSession(ctx, job)
+	return nil
+}
 
-		if err := s.batchRepo.UpsertBatchItems(bgCtx, updatedItems); err != nil {
-			s.batchRepo.UpdateBatchStatus(bgCtx, batchID, domain.BatchStatusFailed)
-			if s.hub != nil {
-				s.hub.Broadcast <- ws.Message{Type: "error", BatchID: batchID.String(), Payload: "Database error during save"}
+// batchGeocode is the geocode outcome for one item of a chunk.
+type batchGeocode struct {
+	res         *domain.GeocodeResponse
+	err         error
+	fromCache   bool
+	cacheStatus string
+}
+
+// geocodeChunk resolves the chunk's addresses that the job has not seen yet.
+// It only fails when ctx is cancelled, returning what finished before that.
+func (s *batchService) geocodeChunk(ctx context.Context, job *domain.BatchJob, items []domain.BatchItem, memCache map[string]*domain.GeocodeResponse) (map[string]GeocodeResult, error) {
+	var addresses []string
+	scores := make(map[string]int)
+	for _, item := range items {
+		if addr := strings.TrimSpace(item.SystemAddress); addr != "" {
+			if _, ok := memCache[strings.ToLower(addr)]; !ok {
+				addresses = append(addresses, item.SystemAddress)
+			}
+			// Scored on upload; the quality policy reuses it.
+			if item.AddressQualityScore != nil {
+				scores[addr] = *item.AddressQualityScore
 			}
-			return
 		}
+	}
+	if len(addresses) == 0 {
+		return nil, ctx.Err()
+	}
+	// BypassCache skips the persistent cache read; fresh results still
+	// replace the cached ones.
+	return s.geoService.GeocodeBatch(ctx, int(job.UserID), addresses, GeocodeOptions{BypassCache: job.BypassCache, QualityScores: scores})
+}
 
-		s.batchRepo.UpdateBatchStatus(bgCtx, batchID, domain.BatchStatusCompleted)
-		if s.hub != nil {
-			s.hub.Broadcast <- ws.Message{Type: "completed", BatchID: batchID.String(), Payload: "Batch processing completed successfully"}
+// chunkResult finds the item's geocode, preferring addresses already resolved
+// earlier in the job. ok is false when the lookup was interrupted.
+func (s *batchService) chunkResult(item domain.BatchItem, results map[string]GeocodeResult, memCache map[string]*domain.GeocodeResponse) (batchGeocode, bool) {
+	key := strings.ToLower(strings.TrimSpace(item.SystemAddress))
+	if res, ok := memCache[key]; ok {
+		return batchGeocode{res: res, fromCache: true, cacheStatus: cacheStatusFor(item, res, true)}, true
+	}
+	r, ok := results[item.SystemAddress]
+	if !ok {
+		return batchGeocode{}, false
+	}
+	if r.Err != nil {
+		return batchGeocode{err: r.Err}, true
+	}
+	memCache[key] = r.Response
+	return batchGeocode{
+		res:         r.Response,
+		fromCache:   r.Response.FromCache,
+		cacheStatus: cacheStatusFor(item, r.Response, r.Response.FromCache),
+	}, true
+}
+
+// cacheStatusFor classifies a cached geocode by the address text it was
+// resolved for: both the cache and the geocoders keep that text in
+// res.Address, so a differing text means a normalized match.
+func cacheStatusFor(item domain.BatchItem, res *domain.GeocodeResponse, fromCache bool) string {
+	switch {
+	case !fromCache:
+		return domain.CacheStatusMiss
+	case strings.TrimSpace(item.SystemAddress) == strings.TrimSpace(res.Address):
+		return domain.CacheStatusExact
+	default:
+		return domain.CacheStatusFuzzy
+	}
+}
+
+// skippedItem records why an item without an address was not geocoded, so
+// it is not left pending.
+func skippedItem(item domain.BatchItem) domain.BatchItem {
+	now := time.Now()
+	return domain.BatchItem{
+		ID:            item.ID,
+		BatchID:       item.BatchID,
+		Connote:       item.Connote,
+		CourierID:     item.CourierID,
+		AttemptCount:  item.AttemptCount + 1,
+		ProcessedAt:   &now,
+		GeocodeStatus: domain.ItemStatusSkipped,
+		ReasonCode:    domain.ReasonAddressEmpty,
+	}
+}
+
+// processItem classifies one geocoded item against its field coordinates.
+// Geocoding problems are recorded on the returned item.
+func (s *batchService) processItem(ctx context.Context, job *domain.BatchJob, item domain.BatchItem, geo batchGeocode) (domain.BatchItem, *domain.CourierPerformance) {
+	userID := job.UserID
+	now := time.Now()
+
+	outItem := domain.BatchItem{
+		ID:           item.ID,
+		BatchID:      item.BatchID,
+		Connote:      item.Connote,
+		CourierID:    item.CourierID,
+		AttemptCount: item.AttemptCount + 1,
+		FromCache:    &geo.fromCache,
+		CacheStatus:  geo.cacheStatus,
+		ProcessedAt:  &now,
+	}
+	geoRes, geoErr := geo.res, geo.err
+
+	if geoErr != nil {
+		outItem.Error = geoErr.Error()
+		outItem.GeocodeStatus = domain.ItemStatusFailed
+		outItem.ReasonCode = reasonForGeocodeError(geoErr)
+
+		// Still record the courier event as an error
+		if item.CourierID == "" || s.analyticsRepo == nil {
+			return outItem, nil
+		}
+		dist := 0.0
+		return outItem, &domain.CourierPerformance{
+			UserID:                 userID,
+			BatchID:                item.BatchID.String(),
+			CourierID:              item.CourierID,
+			OrderID:                item.Connote,
+			ReportedLat:            safeFloat(item.FieldLat),
+			ReportedLng:            safeFloat(item.FieldLng),
+			DistanceVarianceMeters: &dist,
+			AccuracyStatus:         "error",
+			SLAStatus:              "unknown",
+			EventTimestamp:         time.Now(),
+			AccuracyOverride:       item.ReviewStatus.AccuracyOverride(),
 		}
+	}
+
+	sysLat := geoRes.Lat
+	sysLng := geoRes.Lng
+	outItem.SystemLat = &sysLat
+	outItem.SystemLng = &sysLng
+	outItem.Provider = geoRes.Provider
+	outItem.GeocodedCity = geoRes.City
+	outItem.GeocodedProvince = geoRes.Province
+	outItem.GeocodeStatus = domain.ItemStatusCompleted
+
+	if item.FieldLat == nil || item.FieldLng == nil {
+		outItem.ReasonCode = domain.ReasonFieldCoordMissing
+		return outItem, nil
+	}
 
-		// Save history session AND courier performance events
-		session := buildSession(int(userID), results)
-		if err := s.historyService.SaveSession(session); err != nil {
-			log.Printf("WARN: failed to save comparison session for batch %v: %v", batchID, err)
+	dist := utils.CalculateDistance(sysLat, sysLng, *item.FieldLat, *item.FieldLng)
+	accuracy := s.classifier.evaluateAccuracy(dist, geoRes.Precision)
+
+	outItem.DistanceKm = &dist
+	outItem.AccuracyLevel = accuracy
+	outItem.ReasonCode = s.classifier.reasonForMatch(ctx, geoRes, accuracy)
+
+	// Build courier performance event if courier is identified
+	if item.CourierID == "" || s.analyticsRepo == nil {
+		return outItem, nil
+	}
+	distMeters := dist * 1000
+	slaStatus := "on_time" // default — extend later with delivery date logic
+	return outItem, &domain.CourierPerformance{
+		UserID:                 userID,
+		BatchID:                item.BatchID.String(),
+		CourierID:              item.CourierID,
+		OrderID:                item.Connote,
+		ReportedLat:            *item.FieldLat,
+		ReportedLng:            *item.FieldLng,
+		ActualLat:              &sysLat,
+		ActualLng:              &sysLng,
+		DistanceVarianceMeters: &distMeters,
+		AccuracyStatus:         accuracy,
+		SLAStatus:              slaStatus,
+		EventTimestamp:         time.Now(),
+		AccuracyOverride:       item.ReviewStatus.AccuracyOverride(),
+	}
+}
+
+func (s *batchService) saveCourierEvents(ctx context.Context, batchID uuid.UUID, events []domain.CourierPerformance) {
+	if s.analyticsRepo == nil || len(events) == 0 {
+		return
+	}
+	for _, event := range events {
+		ev := event // copy for goroutine safety
+		if err := s.analyticsRepo.SaveCourierPerformance(ctx, &ev); err != nil {
+			log.Printf("WARN: failed to save courier performance for %s/%s: %v", ev.CourierID, ev.OrderID, err)
 		}
+	}
+	log.Printf("INFO: saved %d courier performance events for batch %v", len(events), batchID)
+}
 
-		if s.analyticsRepo != nil {
-			for _, event := range courierEvents {
-				ev := event // copy for goroutine safety
-				if err := s.analyticsRepo.SaveCourierPerformance(context.Background(), &ev); err != nil {
-					log.Printf("WARN: failed to save courier performance for %s/%s: %v", ev.CourierID, ev.OrderID, err)
-				}
-			}
-			log.Printf("INFO: saved %d courier performance events for batch %v", len(courierEvents), batchID)
+// saveBatchSession records the finished batch in the comparison history. It
+// reads the results back from the database because a resumed job only holds
+// the chunks it processed itself.
+func (s *batchService) saveBatchSession(ctx context.Context, job *domain.BatchJob) {
+	var results []domain.ValidationResult
+	err := s.batchRepo.StreamBatchItems(ctx, job.BatchID, func(it *domain.BatchItem) error {
+		if it.GeocodeStatus == domain.ItemStatusCompleted && it.DistanceKm != nil {
+			results = append(results, domain.ValidationResult{
+				SystemAddress: it.SystemAddress,
+				DistanceKm:    *it.DistanceKm,
+				AccuracyLevel: it.AccuracyLevel,
+				ReasonCode:    it.ReasonCode,
+			})
 		}
-	}()
+		return nil
+	})
+	if err != nil {
+		log.Printf("WARN: failed to load results for comparison session of batch %v: %v", job.BatchID, err)
+		return
+	}
+	session := buildSession(int(job.UserID), results)
+	if err := s.historyService.SaveSession(session); err != nil {
+		log.Printf("WARN: failed to save comparison session for batch %v: %v", job.BatchID, err)
+	}
+}
+
+// PauseBatch stops the batch's job after the current item. Finished items are
+// kept; ResumeBatch carries on with the rest.
+func (s *batchService) PauseBatch(ctx context.Context, userID int64, batchID uuid.UUID) error {
+	return s.stopBatchJob(ctx, userID, batchID,
+		[]domain.BatchJobStatus{domain.BatchJobQueued, domain.BatchJobRunning},
+		domain.BatchJobPaused, domain.BatchStatusPaused, "Batch processing paused")
+}
+
+// CancelBatch stops the batch's job for good. Items not processed yet stay
+// pending until the batch is processed again.
+func (s *batchService) CancelBatch(ctx context.Context, userID int64, batchID uuid.UUID) error {
+	return s.stopBatchJob(ctx, userID, batchID,
+		[]domain.BatchJobStatus{domain.BatchJobQueued, domain.BatchJobRunning, domain.BatchJobPaused},
+		domain.BatchJobCancelled, domain.BatchStatusCancelled, "Batch processing cancelled")
+}
 
+func (s *batchService) stopBatchJob(ctx context.Context, userID int64, batchID uuid.UUID, from []domain.BatchJobStatus, to domain.BatchJobStatus, batchStatus domain.BatchStatus, message string) error {
+	if err := s.verifyBatchAccess(ctx, batchID, userID, domain.BatchPermissionEditor); err != nil {
+		return err
+	}
+	job, err := s.jobRepo.Transition(ctx, batchID, from, to)
+	if err != nil {
+		return err
+	}
+	if job == nil {
+		return domain.ErrBatchJobNotActive
+	}
+	// A job running in another instance notices at its next heartbeat or checkpoint.
+	if stop, ok := s.running.Load(batchID); ok {
+		stop.(context.CancelFunc)()
+	}
+	if err := s.batchRepo.UpdateBatchStatus(ctx, batchID, batchStatus); err != nil {
+		return err
+	}
+	if s.hub != nil {
+		s.hub.Broadcast <- ws.Message{Type: string(batchStatus), BatchID: batchID.String(), Payload: message}
+	}
 	return nil
 }
 
+// ResumeBatch queues a paused job again. It continues with the items that are
+// still pending rather than starting over.
+func (s *batchService) ResumeBatch(ctx context.Context, userID int64, batchID uuid.UUID) error {
+	batch, err := s.writableBatch(ctx, batchID, userID)
+	if err != nil {
+		return err
+	}
+	return s.startBatchJob(ctx, batch, func() error {
+		job, err := s.jobRepo.Transition(ctx, batchID, []domain.BatchJobStatus{domain.BatchJobPaused}, domain.BatchJobQueued)
+		if err != nil || job != nil {
+			return err
+		}
+		latest, err := s.jobRepo.GetLatestByBatchID(ctx, batchID)
+		if err != nil {
+			return err
+		}
+		if latest != nil && latest.Status == domain.BatchJobPaused && latest.LockedBy != nil {
+			return domain.ErrBatchJobStopping
+		}
+		return domain.ErrBatchJobNotActive
+	})
+}
+
+// MarkBatchFailed is called by the worker pool once a job has used up its
+// retries.
+func (s *batchService) MarkBatchFailed(ctx context.Context, batchID uuid.UUID, reason string) error {
+	if s.hub != nil {
+		s.hub.Broadcast <- ws.Message{Type: "error", BatchID: batchID.String(), Payload: reason}
+	}
+	return s.batchRepo.UpdateBatchStatus(ctx, batchID, domain.BatchStatusFailed)
+}
+
 func (s *batchService) emitProgress(batchID string, processed, total int) {
 	if s.hub == nil {
 		return
@@ -314,10 +737,20 @@ func safeFloat(f *float64) float64 {
 // GetBatchResults validates batch ownership then returns all items for that batch.
 func (s *batchService) GetBatchResults(ctx context.Context, userID int64, batchID uuid.UUID) ([]domain.BatchItem, error) {
 	// FIX BUG-03: verify the batch belongs to this user before returning results.
-	if err := s.verifyBatchOwnership(ctx, batchID, userID); err != nil {
+	if err := s.verifyBatchAccess(ctx, batchID, userID, domain.BatchPermissionViewer); err != nil {
+		return nil, err
+	}
+	items, err := s.batchRepo.GetBatchItemsByBatchID(ctx, batchID)
+	if err != nil {
 		return nil, err
 	}
-	return s.batchRepo.GetBatchItemsByBatchID(ctx, batchID)
+	for i := range items {
+		if items[i].ReasonCode != "" {
+			explanation := items[i].ReasonCode.Explain()
+			items[i].Explanation = &explanation
+		}
+	}
+	return items, nil
 }
 
 // UpsertETLItems bulk-inserts ETL-derived BatchItems so they appear in the Dashboard
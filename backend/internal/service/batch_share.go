@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+var (
+	// ErrInvalidShare is returned for a share request without exactly one
+	// user or team, with an unknown permission, or naming the owner.
+	ErrInvalidShare = errors.New("invalid share")
+	// ErrShareNotFound is returned when unsharing a share the batch does not have.
+	ErrShareNotFound = errors.New("share not found")
+)
+
+func (s *batchService) ListBatchShares(ctx context.Context, userID int64, batchID uuid.UUID) ([]domain.BatchShare, error) {
+	if err := s.verifyBatchAccess(ctx, batchID, userID, domain.BatchPermissionViewer); err != nil {
+		return nil, err
+	}
+	return s.batchRepo.ListBatchShares(ctx, batchID)
+}
+
+func (s *batchService) ShareBatch(ctx context.Context, userID int64, batchID uuid.UUID, req domain.ShareBatchRequest) (*domain.BatchShare, error) {
+	if (req.UserID == nil) == (req.TeamID == nil) {
+		return nil, fmt.Errorf("%w: set exactly one of user_id and team_id", ErrInvalidShare)
+	}
+	switch req.Permission {
+	case domain.BatchPermissionViewer, domain.BatchPermissionEditor:
+	default:
+		return nil, fmt.Errorf("%w: permission must be viewer or editor", ErrInvalidShare)
+	}
+	if err := s.verifyBatchAccess(ctx, batchID, userID, domain.BatchPermissionOwner); err != nil {
+		return nil, err
+	}
+	if req.UserID != nil && *req.UserID == userID {
+		return nil, fmt.Errorf("%w: the owner already has full access", ErrInvalidShare)
+	}
+
+	share := &domain.BatchShare{
+		BatchID:    batchID,
+		UserID:     req.UserID,
+		TeamID:     req.TeamID,
+		Permission: req.Permission,
+		CreatedBy:  userID,
+	}
+	if err := s.batchRepo.UpsertBatchShare(ctx, share); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+func (s *batchService) UnshareBatch(ctx context.Context, userID int64, batchID uuid.UUID, shareID int64) error {
+	if err := s.verifyBatchAccess(ctx, batchID, userID, domain.BatchPermissionOwner); err != nil {
+		return err
+	}
+	err := s.batchRepo.DeleteBatchShare(ctx, batchID, shareID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrShareNotFound
+	}
+	return err
+}
+
+// TransferBatchOwnership hands the batch to newOwnerID. With keepAccess the
+// previous owner keeps editor access through a share, which the new owner
+// may remove. A running job would keep writing under the old owner, so the
+// batch must be idle.
+func (s *batchService) TransferBatchOwnership(ctx context.Context, userID int64, batchID uuid.UUID, newOwnerID int64, keepAccess bool) error {
+	if newOwnerID == userID {
+		return fmt.Errorf("%w: the batch already belongs to this user", ErrInvalidShare)
+	}
+	if err := s.verifyBatchAccess(ctx, batchID, userID, domain.BatchPermissionOwner); err != nil {
+		return err
+	}
+	if err := s.ensureNoActiveJob(ctx, batchID); err != nil {
+		return err
+	}
+	return s.batchRepo.TransferBatchOwnership(ctx, batchID, userID, newOwnerID, keepAccess)
+}
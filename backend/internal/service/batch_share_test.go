@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+// shareBatchRepo is owned by user 7 and shared according to perms.
+type shareBatchRepo struct {
+	statsBatchRepo
+	perms       map[int64]domain.BatchPermission
+	shared      []domain.BatchShare
+	transferred int64
+	keptAccess  bool
+}
+
+func (r *shareBatchRepo) GetBatchPermission(ctx context.Context, batchID uuid.UUID, userID int64) (domain.BatchPermission, error) {
+	return r.perms[userID], nil
+}
+
+func (r *shareBatchRepo) UpsertBatchShare(ctx context.Context, share *domain.BatchShare) error {
+	r.shared = append(r.shared, *share)
+	return nil
+}
+
+func (r *shareBatchRepo) TransferBatchOwnership(ctx context.Context, batchID uuid.UUID, fromUserID, toUserID int64, keepAccess bool) error {
+	r.transferred, r.keptAccess = toUserID, keepAccess
+	return nil
+}
+
+func TestBatchPermission_Allows(t *testing.T) {
+	assert.True(t, domain.BatchPermissionOwner.Allows(domain.BatchPermissionEditor))
+	assert.True(t, domain.BatchPermissionEditor.Allows(domain.BatchPermissionViewer))
+	assert.False(t, domain.BatchPermissionViewer.Allows(domain.BatchPermissionEditor))
+	assert.False(t, domain.BatchPermissionEditor.Allows(domain.BatchPermissionOwner))
+	assert.False(t, domain.BatchPermission("").Allows(domain.BatchPermissionViewer))
+}
+
+func TestBatchAccess_RespectsShares(t *testing.T) {
+	batchID := uuid.New()
+	repo := &shareBatchRepo{
+		statsBatchRepo: statsBatchRepo{batch: &domain.Batch{ID: batchID, UserID: 7}, counts: map[int]int{}},
+		perms:          map[int64]domain.BatchPermission{8: domain.BatchPermissionViewer, 9: domain.BatchPermissionEditor},
+	}
+	svc := &batchService{batchRepo: repo, jobRepo: &transitionJobRepo{}}
+	ctx := context.Background()
+
+	// A viewer reads but does not write.
+	_, err := svc.GetBatchStats(ctx, 8, batchID, nil)
+	require.NoError(t, err)
+	_, err = svc.UploadFieldData(ctx, 8, batchID, nil, domain.UploadModeLenient)
+	assert.ErrorIs(t, err, errAccessDenied)
+
+	// An editor writes but does not delete, share or transfer.
+	batch, err := svc.batchWithAccess(ctx, batchID, 9, domain.BatchPermissionEditor)
+	require.NoError(t, err)
+	assert.Equal(t, domain.BatchPermissionEditor, batch.Access)
+	assert.ErrorIs(t, svc.DeleteBatch(ctx, 9, batchID), errAccessDenied)
+	_, err = svc.ShareBatch(ctx, 9, batchID, domain.ShareBatchRequest{UserID: ptrInt64(10), Permission: domain.BatchPermissionViewer})
+	assert.ErrorIs(t, err, errAccessDenied)
+	assert.ErrorIs(t, svc.TransferBatchOwnership(ctx, 9, batchID, 10, false), errAccessDenied)
+
+	// Without a share there is no access at all.
+	_, err = svc.GetBatchStats(ctx, 10, batchID, nil)
+	assert.ErrorIs(t, err, errAccessDenied)
+	assert.Empty(t, repo.shared)
+	assert.Zero(t, repo.transferred)
+}
+
+func TestShareBatch(t *testing.T) {
+	batchID := uuid.New()
+	repo := &shareBatchRepo{statsBatchRepo: statsBatchRepo{batch: &domain.Batch{ID: batchID, UserID: 7}}}
+	jobs := &transitionJobRepo{}
+	svc := &batchService{batchRepo: repo, jobRepo: jobs}
+	ctx := context.Background()
+
+	for name, req := range map[string]domain.ShareBatchRequest{
+		"no target":   {Permission: domain.BatchPermissionViewer},
+		"two targets": {UserID: ptrInt64(8), TeamID: ptrInt64(1), Permission: domain.BatchPermissionViewer},
+		"permission":  {UserID: ptrInt64(8), Permission: domain.BatchPermissionOwner},
+		"owner":       {UserID: ptrInt64(7), Permission: domain.BatchPermissionEditor},
+	} {
+		_, err := svc.ShareBatch(ctx, 7, batchID, req)
+		assert.ErrorIs(t, err, ErrInvalidShare, name)
+	}
+
+	share, err := svc.ShareBatch(ctx, 7, batchID, domain.ShareBatchRequest{TeamID: ptrInt64(3), Permission: domain.BatchPermissionEditor})
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), share.CreatedBy)
+	assert.Len(t, repo.shared, 1)
+
+	assert.ErrorIs(t, svc.TransferBatchOwnership(ctx, 7, batchID, 7, false), ErrInvalidShare)
+
+	// A running job keeps the batch with its owner.
+	jobs.latest = &domain.BatchJob{BatchID: batchID, Status: domain.BatchJobRunning}
+	assert.ErrorIs(t, svc.TransferBatchOwnership(ctx, 7, batchID, 8, true), domain.ErrBatchJobActive)
+	assert.Zero(t, repo.transferred)
+
+	jobs.latest.Status = domain.BatchJobCompleted
+	require.NoError(t, svc.TransferBatchOwnership(ctx, 7, batchID, 8, true))
+	assert.Equal(t, int64(8), repo.transferred)
+	assert.True(t, repo.keptAccess)
+}
+
+func ptrInt64(v int64) *int64 { return &v }
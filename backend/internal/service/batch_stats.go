@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+// maxHistogramEdges bounds the bucket edges a client may ask for.
+const maxHistogramEdges = 50
+
+// defaultHistogramEdges (km) follow the accuracy thresholds closely enough to
+// read next to the accuracy counts, then widen for the long tail.
+var defaultHistogramEdges = []float64{0, 0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 50}
+
+// ErrInvalidHistogram is returned for bucket edges that are not ascending,
+// negative, or too many.
+var ErrInvalidHistogram = errors.New("invalid histogram buckets")
+
+func (s *batchService) GetBatchStats(ctx context.Context, userID int64, batchID uuid.UUID, edges []float64) (*domain.BatchStats, error) {
+	explicit := len(edges) > 0
+	if !explicit {
+		// Copied: the buckets point into the slice.
+		edges = append([]float64(nil), defaultHistogramEdges...)
+	}
+	if err := validateHistogramEdges(edges); err != nil {
+		return nil, err
+	}
+	batch, err := s.batchWithAccess(ctx, batchID, userID, domain.BatchPermissionViewer)
+	if err != nil {
+		return nil, err
+	}
+	if batch.ItemsPurgedAt != nil {
+		// Only the summary is left; it was computed with the default buckets.
+		if explicit {
+			return nil, fmt.Errorf("%w: item detail was purged, only the default buckets are kept", ErrInvalidHistogram)
+		}
+		return s.batchRepo.GetBatchSummary(ctx, batchID)
+	}
+	return s.computeBatchStats(ctx, batchID, edges)
+}
+
+// computeBatchStats aggregates the batch's items; edges must be valid.
+func (s *batchService) computeBatchStats(ctx context.Context, batchID uuid.UUID, edges []float64) (*domain.BatchStats, error) {
+	stats, err := s.batchRepo.GetBatchStats(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.batchRepo.CountDistanceBuckets(ctx, batchID, edges)
+	if err != nil {
+		return nil, err
+	}
+	stats.Histogram = histogramBuckets(edges, counts)
+	return stats, nil
+}
+
+func validateHistogramEdges(edges []float64) error {
+	if len(edges) > maxHistogramEdges {
+		return fmt.Errorf("%w: at most %d edges", ErrInvalidHistogram, maxHistogramEdges)
+	}
+	for i, e := range edges {
+		if e < 0 {
+			return fmt.Errorf("%w: edges must not be negative", ErrInvalidHistogram)
+		}
+		if i > 0 && e <= edges[i-1] {
+			return fmt.Errorf("%w: edges must be strictly ascending", ErrInvalidHistogram)
+		}
+	}
+	return nil
+}
+
+// histogramBuckets turns width_bucket counts into labelled buckets. Every
+// bucket is listed, empty or not, so charts keep a stable x axis; the bucket
+// below the first edge only appears when that edge is above zero.
+func histogramBuckets(edges []float64, counts map[int]int) []domain.HistogramBucket {
+	buckets := make([]domain.HistogramBucket, 0, len(edges)+1)
+	if edges[0] > 0 {
+		buckets = append(buckets, domain.HistogramBucket{MaxKm: &edges[0], Count: counts[0]})
+	}
+	for i := 1; i < len(edges); i++ {
+		buckets = append(buckets, domain.HistogramBucket{MinKm: &edges[i-1], MaxKm: &edges[i], Count: counts[i]})
+	}
+	return append(buckets, domain.HistogramBucket{MinKm: &edges[len(edges)-1], Count: counts[len(edges)]})
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+type statsBatchRepo struct {
+	domain.BatchRepository
+	batch  *domain.Batch
+	counts map[int]int
+	edges  []float64
+}
+
+func (r *statsBatchRepo) GetBatchByID(ctx context.Context, id uuid.UUID) (*domain.Batch, error) {
+	return r.batch, nil
+}
+
+func (r *statsBatchRepo) GetBatchStats(ctx context.Context, batchID uuid.UUID) (*domain.BatchStats, error) {
+	return &domain.BatchStats{BatchID: batchID}, nil
+}
+
+func (r *statsBatchRepo) CountDistanceBuckets(ctx context.Context, batchID uuid.UUID, edges []float64) (map[int]int, error) {
+	r.edges = edges
+	return r.counts, nil
+}
+
+func TestGetBatchStats_Histogram(t *testing.T) {
+	batchID := uuid.New()
+	repo := &statsBatchRepo{
+		batch:  &domain.Batch{ID: batchID, UserID: 7},
+		counts: map[int]int{0: 1, 1: 4, 3: 2},
+	}
+	svc := &batchService{batchRepo: repo}
+
+	stats, err := svc.GetBatchStats(context.Background(), 7, batchID, []float64{0.1, 0.5, 1})
+	require.NoError(t, err)
+	require.Len(t, stats.Histogram, 4)
+
+	below, first, last := stats.Histogram[0], stats.Histogram[1], stats.Histogram[3]
+	assert.Nil(t, below.MinKm)
+	assert.Equal(t, 0.1, *below.MaxKm)
+	assert.Equal(t, 1, below.Count)
+	assert.Equal(t, 0.1, *first.MinKm)
+	assert.Equal(t, 0.5, *first.MaxKm)
+	assert.Equal(t, 4, first.Count)
+	assert.Equal(t, 0, stats.Histogram[2].Count)
+	assert.Equal(t, 1.0, *last.MinKm)
+	assert.Nil(t, last.MaxKm)
+	assert.Equal(t, 2, last.Count)
+
+	// Defaults start at zero, so there is no bucket below the first edge.
+	stats, err = svc.GetBatchStats(context.Background(), 7, batchID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, defaultHistogramEdges, repo.edges)
+	assert.Len(t, stats.Histogram, len(defaultHistogramEdges))
+	assert.Equal(t, 0.0, *stats.Histogram[0].MinKm)
+}
+
+func TestGetBatchStats_RejectsInvalidEdges(t *testing.T) {
+	svc := &batchService{}
+	for name, edges := range map[string][]float64{
+		"descending": {1, 0.5},
+		"duplicate":  {0.5, 0.5},
+		"negative":   {-1, 0},
+		"too many":   make([]float64, maxHistogramEdges+1),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.GetBatchStats(context.Background(), 7, uuid.New(), edges)
+			assert.ErrorIs(t, err, ErrInvalidHistogram)
+		})
+	}
+}
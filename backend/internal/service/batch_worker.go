@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+const (
+	// batchJobLease is how long a claimed job stays locked without a
+	// heartbeat. After a crash the job is picked up again once it expires.
+	batchJobLease        = 60 * time.Second
+	batchJobHeartbeat    = batchJobLease / 3
+	batchJobPollInterval = 2 * time.Second
+
+	maxBatchJobAttempts = 3
+	batchJobRetryDelay  = 30 * time.Second
+)
+
+// BatchWorkerPool runs queued batch jobs. Jobs live in Postgres, so work
+// queued before a restart (or interrupted by one) is resumed by whichever
+// instance claims it next.
+type BatchWorkerPool struct {
+	jobs     domain.BatchJobRepository
+	batches  domain.BatchService
+	workers  int
+	hostname string
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewBatchWorkerPool(jobs domain.BatchJobRepository, batches domain.BatchService, workers int) *BatchWorkerPool {
+	if workers < 1 {
+		workers = 1
+	}
+	hostname, _ := os.Hostname()
+	return &BatchWorkerPool{jobs: jobs, batches: batches, workers: workers, hostname: hostname}
+}
+
+// Start launches the workers. Each polls the queue until Stop is called.
+func (p *BatchWorkerPool) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	for i := 0; i < p.workers; i++ {
+		workerID := fmt.Sprintf("%s:%d:%d", p.hostname, os.Getpid(), i)
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.loop(ctx, workerID)
+		}()
+	}
+	log.Printf("[BatchWorker] started %d workers", p.workers)
+}
+
+// Stop interrupts running jobs, which save their current chunk and go back
+// to the queue, and waits for the workers to exit.
+func (p *BatchWorkerPool) Stop() {
+	if p.cancel == nil {
+		return
+	}
+	p.cancel()
+	p.wg.Wait()
+	log.Println("[BatchWorker] stopped")
+}
+
+func (p *BatchWorkerPool) loop(ctx context.Context, workerID string) {
+	ticker := time.NewTicker(batchJobPollInterval)
+	defer ticker.Stop()
+	for {
+		// Drain the queue before waiting for the next tick.
+		for ctx.Err() == nil && p.runNext(ctx, workerID) {
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runNext claims and runs one job. It reports whether a job was found.
+func (p *BatchWorkerPool) runNext(ctx context.Context, workerID string) bool {
+	job, err := p.jobs.Claim(ctx, workerID, batchJobLease)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[BatchWorker] claim failed: %v", err)
+		}
+		return false
+	}
+	if job == nil {
+		return false
+	}
+	log.Printf("[BatchWorker] %s running job %d for batch %v (attempt %d, resuming at %d)",
+		workerID, job.ID, job.BatchID, job.Attempts, job.Processed)
+
+	jobCtx, cancelJob := context.WithCancel(ctx)
+	defer cancelJob()
+	go p.heartbeat(jobCtx, cancelJob, job.ID, workerID)
+
+	checkpoint := func(processed, total int) error {
+		return p.jobs.Checkpoint(context.WithoutCancel(jobCtx), job.ID, workerID, processed, total, batchJobLease)
+	}
+	runErr := p.batches.RunBatchJob(jobCtx, job, checkpoint)
+	// Errors after an interruption are usually just the cancelled context
+	// surfacing through the database driver; they do not count as failures.
+	interrupted := jobCtx.Err() != nil
+	cancelJob()
+
+	p.finish(ctx, job, workerID, runErr, interrupted)
+	return true
+}
+
+// heartbeat renews the lease while a job runs and cancels it if the lease
+// was lost to another worker.
+func (p *BatchWorkerPool) heartbeat(ctx context.Context, cancelJob context.CancelFunc, jobID int64, workerID string) {
+	ticker := time.NewTicker(batchJobHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := p.jobs.ExtendLease(ctx, jobID, workerID, batchJobLease)
+			if errors.Is(err, domain.ErrBatchJobLeaseLost) {
+				log.Printf("[BatchWorker] job %d: lease lost, stopping", jobID)
+				cancelJob()
+				return
+			}
+			if err != nil && ctx.Err() == nil {
+				log.Printf("[BatchWorker] job %d: heartbeat failed: %v", jobID, err)
+			}
+		}
+	}
+}
+
+func (p *BatchWorkerPool) finish(ctx context.Context, job *domain.BatchJob, workerID string, runErr error, interrupted bool) {
+	// The pool context may already be cancelled; the bookkeeping must still happen.
+	ctx = context.WithoutCancel(ctx)
+
+	switch {
+	case runErr == nil:
+		if err := p.jobs.Complete(ctx, job.ID, workerID); err != nil {
+			p.notHeld(ctx, job, workerID, "mark completed", err)
+		}
+	case errors.Is(runErr, domain.ErrBatchJobLeaseLost):
+		// Another worker owns the job now; leave it alone.
+	case interrupted || errors.Is(runErr, domain.ErrBatchJobStopped):
+		// Shutdown, pause/cancel, or a lost lease (then Release matches nothing).
+		if err := p.jobs.Release(ctx, job.ID, workerID); err != nil {
+			log.Printf("[BatchWorker] job %d: release: %v", job.ID, err)
+		}
+	case job.Attempts < maxBatchJobAttempts:
+		log.Printf("[BatchWorker] job %d attempt %d failed, retrying: %v", job.ID, job.Attempts, runErr)
+		runAfter := time.Now().Add(time.Duration(job.Attempts) * batchJobRetryDelay)
+		if err := p.jobs.Retry(ctx, job.ID, workerID, runErr.Error(), runAfter); err != nil {
+			p.notHeld(ctx, job, workerID, "requeue", err)
+		}
+	default:
+		log.Printf("[BatchWorker] job %d failed after %d attempts: %v", job.ID, job.Attempts, runErr)
+		if err := p.jobs.Fail(ctx, job.ID, workerID, runErr.Error()); err != nil {
+			p.notHeld(ctx, job, workerID, "mark failed", err)
+			return
+		}
+		if err := p.batches.MarkBatchFailed(ctx, job.BatchID, "Batch processing failed: "+runErr.Error()); err != nil {
+			log.Printf("[BatchWorker] batch %v: mark failed: %v", job.BatchID, err)
+		}
+	}
+}
+
+// notHeld handles a final status update that did not apply. The job was
+// paused or cancelled while its last chunk ran, or another worker claimed
+// it, and keeps that state; Release unlocks it in the first case so it can
+// be resumed straight away.
+func (p *BatchWorkerPool) notHeld(ctx context.Context, job *domain.BatchJob, workerID, action string, err error) {
+	if !errors.Is(err, domain.ErrBatchJobLeaseLost) {
+		log.Printf("[BatchWorker] job %d: %s: %v", job.ID, action, err)
+		return
+	}
+	log.Printf("[BatchWorker] job %d: no longer running under %s, not marked (%s)", job.ID, workerID, action)
+	if err := p.jobs.Release(ctx, job.ID, workerID); err != nil {
+		log.Printf("[BatchWorker] job %d: release: %v", job.ID, err)
+	}
+}
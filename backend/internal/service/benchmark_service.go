@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"geoaccuracy-backend/config"
+	"geoaccuracy-backend/internal/domain"
+	"geoaccuracy-backend/pkg/utils"
+)
+
+// maxGroundTruthEntries caps a dataset; every entry costs one call per provider.
+const maxGroundTruthEntries = 5000
+
+const (
+	// A running report is refreshed every benchmarkHeartbeat. One that has not
+	// been for benchmarkStaleAfter lost its runner and is failed.
+	benchmarkHeartbeat  = 20 * time.Second
+	benchmarkStaleAfter = 2 * time.Minute
+
+	errBenchmarkInterrupted = "interrupted by a server restart"
+)
+
+var (
+	ErrDatasetNotFound = errors.New("dataset not found or access denied")
+	ErrReportNotFound  = errors.New("benchmark report not found or access denied")
+	ErrInvalidDataset  = errors.New("invalid ground-truth dataset")
+)
+
+type benchmarkService struct {
+	repo       domain.BenchmarkRepository
+	geoService GeocodeService
+	costs      map[string]float64 // USD per 1,000 requests
+
+	// Benchmarks outlive their request and run on ctx, which Stop cancels.
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewBenchmarkService(repo domain.BenchmarkRepository, geoService GeocodeService, cfg *config.Config) domain.BenchmarkService {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &benchmarkService{
+		repo:       repo,
+		geoService: geoService,
+		costs:      cfg.ProviderCostPer1000,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Start fails the reports left running by a previous process, then keeps
+// checking for ones orphaned by other instances.
+func (s *benchmarkService) Start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(benchmarkStaleAfter / 2)
+		defer ticker.Stop()
+		for {
+			n, err := s.repo.FailStaleReports(s.ctx, benchmarkStaleAfter, errBenchmarkInterrupted)
+			if err != nil && s.ctx.Err() == nil {
+				log.Printf("[Benchmark] failed to fail stale reports: %v", err)
+			} else if n > 0 {
+				log.Printf("[Benchmark] failed %d reports orphaned by a restart", n)
+			}
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (s *benchmarkService) Stop() {
+	s.cancel()
+	s.wg.Wait()
+	log.Println("[Benchmark] stopped")
+}
+
+func (s *benchmarkService) CreateDataset(ctx context.Context, userID int64, name string, entries []domain.GroundTruthEntry) (*domain.GroundTruthDataset, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidDataset)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: no entries", ErrInvalidDataset)
+	}
+	if len(entries) > maxGroundTruthEntries {
+		return nil, fmt.Errorf("%w: %d entries exceeds the limit of %d", ErrInvalidDataset, len(entries), maxGroundTruthEntries)
+	}
+
+	ds := &domain.GroundTruthDataset{UserID: userID, Name: name}
+	if err := s.repo.CreateDataset(ctx, ds, entries); err != nil {
+		return nil, err
+	}
+	return ds, nil
+}
+
+func (s *benchmarkService) ListDatasets(ctx context.Context, userID int64) ([]domain.GroundTruthDataset, error) {
+	return s.repo.ListDatasets(ctx, userID)
+}
+
+func (s *benchmarkService) DeleteDataset(ctx context.Context, userID, datasetID int64) error {
+	err := s.repo.DeleteDataset(ctx, datasetID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrDatasetNotFound
+	}
+	return err
+}
+
+func (s *benchmarkService) StartBenchmark(ctx context.Context, userID, datasetID int64, providers []string) (*domain.BenchmarkReport, error) {
+	ds, err := s.repo.GetDataset(ctx, datasetID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if ds == nil {
+		return nil, ErrDatasetNotFound
+	}
+
+	configured := s.geoService.ConfiguredProviders(int(userID))
+	if len(providers) == 0 {
+		providers = configured
+	}
+	for _, p := range providers {
+		if !slices.Contains(configured, p) {
+			return nil, fmt.Errorf("%w: %s", ErrProviderNotConfigured, p)
+		}
+	}
+
+	entries, err := s.repo.GetEntries(ctx, datasetID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.BenchmarkReport{
+		UserID:    userID,
+		DatasetID: datasetID,
+		Status:    domain.BenchmarkStatusRunning,
+		Providers: providers,
+	}
+	if err := s.repo.CreateReport(ctx, report); err != nil {
+		return nil, err
+	}
+
+	// The request context ends with the HTTP response; the job outlives it.
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.runBenchmark(s.ctx, report.ID, int(userID), entries, providers)
+	}()
+
+	return report, nil
+}
+
+func (s *benchmarkService) GetReport(ctx context.Context, userID, reportID int64) (*domain.BenchmarkReport, error) {
+	rep, err := s.repo.GetReport(ctx, reportID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if rep == nil {
+		return nil, ErrReportNotFound
+	}
+	return rep, nil
+}
+
+func (s *benchmarkService) ListReports(ctx context.Context, userID, datasetID int64) ([]domain.BenchmarkReport, error) {
+	return s.repo.ListReports(ctx, datasetID, userID)
+}
+
+// benchmarkSample is the outcome of geocoding one entry with one provider.
+type benchmarkSample struct {
+	province      string
+	hit           bool
+	providerError bool // failed for a reason other than "not found"
+	errorM        float64
+	latency       time.Duration
+}
+
+// runBenchmark geocodes every entry through each provider, bypassing the cache
+// in both directions so results reflect the provider and not earlier lookups.
+// Providers run in parallel; each keeps its own rate limits and yields to
+// batch jobs.
+func (s *benchmarkService) runBenchmark(ctx context.Context, reportID int64, userID int, entries []domain.GroundTruthEntry, providers []string) {
+	log.Printf("[Benchmark] Report %d: %d entries x %d providers", reportID, len(entries), len(providers))
+
+	samples := make([][]benchmarkSample, len(providers))
+	heartbeatCtx, stopHeartbeat := context.WithCancel(ctx)
+	go s.heartbeat(heartbeatCtx, reportID)
+
+	var wg sync.WaitGroup
+	for i, provider := range providers {
+		wg.Add(1)
+		go func(i int, provider string) {
+			defer wg.Done()
+			samples[i] = s.sampleProvider(ctx, userID, provider, entries)
+		}(i, provider)
+	}
+	wg.Wait()
+	stopHeartbeat()
+
+	if ctx.Err() != nil {
+		// ctx is done; give the final write its own short deadline.
+		saveCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.repo.FinishReport(saveCtx, reportID, domain.BenchmarkStatusFailed, nil, errBenchmarkInterrupted); err != nil {
+			log.Printf("[Benchmark] Report %d: failed to record interruption: %v", reportID, err)
+		}
+		log.Printf("[Benchmark] Report %d interrupted", reportID)
+		return
+	}
+
+	result := summarizeBenchmark(len(entries), providers, samples, s.costs)
+	if err := s.repo.FinishReport(ctx, reportID, domain.BenchmarkStatusCompleted, result, ""); err != nil {
+		log.Printf("[Benchmark] Report %d: failed to save result: %v", reportID, err)
+		_ = s.repo.FinishReport(ctx, reportID, domain.BenchmarkStatusFailed, nil, err.Error())
+		return
+	}
+	log.Printf("[Benchmark] Report %d completed", reportID)
+}
+
+func (s *benchmarkService) heartbeat(ctx context.Context, reportID int64) {
+	ticker := time.NewTicker(benchmarkHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.repo.HeartbeatReport(ctx, reportID); err != nil && ctx.Err() == nil {
+				log.Printf("[Benchmark] Report %d: heartbeat failed: %v", reportID, err)
+			}
+		}
+	}
+}
+
+func (s *benchmarkService) sampleProvider(ctx context.Context, userID int, provider string, entries []domain.GroundTruthEntry) []benchmarkSample {
+	opts := GeocodeOptions{Provider: provider, BypassCache: true, NoCacheWrite: true, Background: true}
+	samples := make([]benchmarkSample, 0, len(entries))
+
+	for _, e := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+		start := time.Now()
+		res, err := s.geoService.GeocodeAddressWithOptions(ctx, userID, e.Address, opts)
+		sample := benchmarkSample{province: e.Province, latency: time.Since(start)}
+
+		if err == nil && res != nil {
+			sample.hit = true
+			sample.errorM = utils.CalculateDistance(e.Lat, e.Lng, res.Lat, res.Lng) * 1000
+		} else if !errors.Is(err, ErrAddressNotFound) {
+			sample.providerError = true
+		}
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
+// summarizeBenchmark turns raw samples (one slice per provider, same order as
+// providers) into the stored report.
+func summarizeBenchmark(totalEntries int, providers []string, samples [][]benchmarkSample, costs map[string]float64) *domain.BenchmarkResult {
+	result := &domain.BenchmarkResult{TotalEntries: totalEntries}
+
+	for i, provider := range providers {
+		byProvince := make(map[string][]benchmarkSample)
+		for _, smp := range samples[i] {
+			province := strings.TrimSpace(smp.province)
+			if province == "" {
+				province = "unknown"
+			}
+			byProvince[province] = append(byProvince[province], smp)
+		}
+
+		provinces := make([]string, 0, len(byProvince))
+		for p := range byProvince {
+			provinces = append(provinces, p)
+		}
+		sort.Strings(provinces)
+
+		pb := domain.ProviderBenchmark{
+			Provider:         provider,
+			BenchmarkStats:   benchmarkStats(samples[i]),
+			EstimatedCostUSD: float64(len(samples[i])) / 1000 * costs[provider],
+		}
+		for _, p := range provinces {
+			pb.Provinces = append(pb.Provinces, domain.ProvinceBenchmark{
+				Province:       p,
+				BenchmarkStats: benchmarkStats(byProvince[p]),
+			})
+		}
+		result.Providers = append(result.Providers, pb)
+	}
+	return result
+}
+
+func benchmarkStats(samples []benchmarkSample) domain.BenchmarkStats {
+	st := domain.BenchmarkStats{Attempts: len(samples)}
+	if len(samples) == 0 {
+		return st
+	}
+
+	var errorsM []float64
+	var totalLatency time.Duration
+	for _, smp := range samples {
+		totalLatency += smp.latency
+		if smp.hit {
+			st.Hits++
+			errorsM = append(errorsM, smp.errorM)
+		}
+		if smp.providerError {
+			st.ProviderErrors++
+		}
+	}
+
+	st.HitRate = float64(st.Hits) / float64(st.Attempts)
+	st.MedianErrorM = utils.Percentile(errorsM, 0.5)
+	st.P90ErrorM = utils.Percentile(errorsM, 0.9)
+	st.AvgLatencyMs = float64(totalLatency.Milliseconds()) / float64(st.Attempts)
+	return st
+}
+
+// ParseGroundTruthCSV reads a CSV with a header row containing address, lat and
+// lng columns and an optional province column. Indonesian headers (alamat,
+// provinsi) and common coordinate aliases are accepted.
+func ParseGroundTruthCSV(r io.Reader) ([]domain.GroundTruthEntry, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: cannot read header: %v", ErrInvalidDataset, err)
+	}
+
+	cols := map[string]int{}
+	aliases := map[string]string{
+		"address": "address", "alamat": "address",
+		"lat": "lat", "latitude": "lat",
+		"lng": "lng", "lon": "lng", "long": "lng", "longitude": "lng",
+		"province": "province", "provinsi": "province",
+	}
+	for i, h := range header {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		if canonical, ok := aliases[key]; ok {
+			cols[canonical] = i
+		}
+	}
+	for _, required := range []string{"address", "lat", "lng"} {
+		if _, ok := cols[required]; !ok {
+			return nil, fmt.Errorf("%w: missing %q column", ErrInvalidDataset, required)
+		}
+	}
+
+	field := func(rec []string, name string) string {
+		i, ok := cols[name]
+		if !ok || i >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[i])
+	}
+
+	var entries []domain.GroundTruthEntry
+	for line := 2; ; line++ {
+		rec, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidDataset, line, err)
+		}
+
+		address := field(rec, "address")
+		if address == "" {
+			return nil, fmt.Errorf("%w: line %d: empty address", ErrInvalidDataset, line)
+		}
+		lat, errLat := strconv.ParseFloat(field(rec, "lat"), 64)
+		lng, errLng := strconv.ParseFloat(field(rec, "lng"), 64)
+		if errLat != nil || errLng != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+			return nil, fmt.Errorf("%w: line %d: invalid coordinate", ErrInvalidDataset, line)
+		}
+
+		entries = append(entries, domain.GroundTruthEntry{
+			Address:  address,
+			Province: field(rec, "province"),
+			Lat:      lat,
+			Lng:      lng,
+		})
+	}
+	return entries, nil
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"geoaccuracy-backend/config"
+	"geoaccuracy-backend/internal/domain"
+)
+
+func TestParseGroundTruthCSV(t *testing.T) {
+	csvData := "\ufeffAlamat,Latitude,Longitude,Provinsi\n" +
+		"Jl. Sudirman 1,-6.2,106.8,DKI Jakarta\n" +
+		"\"Jl. Tunjungan 5, Surabaya\",-7.26,112.74,\n"
+
+	entries, err := ParseGroundTruthCSV(strings.NewReader(csvData))
+
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "Jl. Sudirman 1", entries[0].Address)
+	assert.Equal(t, "DKI Jakarta", entries[0].Province)
+	assert.Equal(t, -6.2, entries[0].Lat)
+	assert.Equal(t, 106.8, entries[0].Lng)
+	assert.Equal(t, "Jl. Tunjungan 5, Surabaya", entries[1].Address)
+	assert.Empty(t, entries[1].Province)
+}
+
+func TestParseGroundTruthCSV_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"missing column", "address,lat\nx,1\n", `missing "lng" column`},
+		{"bad coordinate", "address,lat,lng\nx,abc,1\n", "line 2: invalid coordinate"},
+		{"out of range", "address,lat,lng\nx,91,1\n", "line 2: invalid coordinate"},
+		{"empty address", "address,lat,lng\nx,1,1\n ,1,1\n", "line 3: empty address"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseGroundTruthCSV(strings.NewReader(tt.data))
+			require.ErrorIs(t, err, ErrInvalidDataset)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
+
+func TestSummarizeBenchmark(t *testing.T) {
+	google := []benchmarkSample{
+		{province: "DKI Jakarta", hit: true, errorM: 10, latency: 100 * time.Millisecond},
+		{province: "DKI Jakarta", hit: true, errorM: 30, latency: 100 * time.Millisecond},
+		{province: "Jawa Timur", hit: true, errorM: 200, latency: 100 * time.Millisecond},
+		{province: "", hit: false, latency: 100 * time.Millisecond},
+	}
+	nominatim := []benchmarkSample{
+		{province: "DKI Jakarta", hit: false},
+		{province: "DKI Jakarta", providerError: true},
+		{province: "Jawa Timur", hit: true, errorM: 50},
+		{province: "", hit: true, errorM: 70},
+	}
+
+	res := summarizeBenchmark(4, []string{ProviderGoogleMaps, ProviderNominatim},
+		[][]benchmarkSample{google, nominatim},
+		map[string]float64{ProviderGoogleMaps: 5})
+
+	require.Len(t, res.Providers, 2)
+	assert.Equal(t, 4, res.TotalEntries)
+
+	g := res.Providers[0]
+	assert.Equal(t, ProviderGoogleMaps, g.Provider)
+	assert.Equal(t, 4, g.Attempts)
+	assert.Equal(t, 3, g.Hits)
+	assert.InDelta(t, 0.75, g.HitRate, 1e-9)
+	assert.InDelta(t, 30, g.MedianErrorM, 1e-9)
+	assert.InDelta(t, 166, g.P90ErrorM, 1e-9) // 30 + (200-30)*0.8
+	assert.InDelta(t, 100, g.AvgLatencyMs, 1e-9)
+	assert.InDelta(t, 0.02, g.EstimatedCostUSD, 1e-9)
+
+	require.Len(t, g.Provinces, 3)
+	assert.Equal(t, "DKI Jakarta", g.Provinces[0].Province)
+	assert.Equal(t, 2, g.Provinces[0].Hits)
+	assert.InDelta(t, 20, g.Provinces[0].MedianErrorM, 1e-9)
+	assert.Equal(t, "Jawa Timur", g.Provinces[1].Province)
+	assert.Equal(t, "unknown", g.Provinces[2].Province)
+	assert.Equal(t, 0.0, g.Provinces[2].HitRate)
+
+	n := res.Providers[1]
+	assert.Equal(t, 2, n.Hits)
+	assert.Equal(t, 1, n.ProviderErrors)
+	assert.InDelta(t, 60, n.MedianErrorM, 1e-9)
+	assert.Equal(t, 0.0, n.EstimatedCostUSD)
+}
+
+// memBenchmarkRepo records how reports were finished.
+type memBenchmarkRepo struct {
+	domain.BenchmarkRepository
+	mu       sync.Mutex
+	finished map[int64]domain.BenchmarkStatus
+	errors   map[int64]string
+}
+
+func (r *memBenchmarkRepo) GetDataset(ctx context.Context, id, userID int64) (*domain.GroundTruthDataset, error) {
+	return &domain.GroundTruthDataset{ID: id, UserID: userID}, nil
+}
+
+func (r *memBenchmarkRepo) GetEntries(ctx context.Context, datasetID int64) ([]domain.GroundTruthEntry, error) {
+	return []domain.GroundTruthEntry{{Address: "Jl. Sudirman 1"}, {Address: "Jl. Thamrin 2"}}, nil
+}
+
+func (r *memBenchmarkRepo) CreateReport(ctx context.Context, report *domain.BenchmarkReport) error {
+	report.ID = 1
+	return nil
+}
+
+func (r *memBenchmarkRepo) HeartbeatReport(ctx context.Context, id int64) error {
+	return nil
+}
+
+func (r *memBenchmarkRepo) FinishReport(ctx context.Context, id int64, status domain.BenchmarkStatus, result *domain.BenchmarkResult, errMsg string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.finished[id], r.errors[id] = status, errMsg
+	return nil
+}
+
+func TestBenchmarkStop_FailsRunningReport(t *testing.T) {
+	repo := &memBenchmarkRepo{finished: map[int64]domain.BenchmarkStatus{}, errors: map[int64]string{}}
+	started := make(chan struct{}, 1)
+	geo := &stubGeocodeService{GeocodeFunc: func(ctx context.Context, userID int, address string) (*domain.GeocodeResponse, error) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}}
+	svc := NewBenchmarkService(repo, geo, &config.Config{})
+
+	report, err := svc.StartBenchmark(context.Background(), 7, 3, nil)
+	require.NoError(t, err)
+	<-started
+	svc.Stop()
+
+	assert.Equal(t, domain.BenchmarkStatusFailed, repo.finished[report.ID])
+	assert.Equal(t, errBenchmarkInterrupted, repo.errors[report.ID])
+}
@@ -3,13 +3,27 @@ package service
 import (
 	"context"
 	"log"
+	"strings"
+	"sync"
 
+	"geoaccuracy-backend/config"
 	"geoaccuracy-backend/internal/domain"
 	"geoaccuracy-backend/pkg/utils"
 )
 
+// validateBatchWorkers bounds how many items of one batch request are geocoded
+// concurrently. Provider rate limiters inside GeocodeService are shared by all
+// workers, so this caps in-flight requests without bypassing provider limits.
+const validateBatchWorkers = 8
+
+// validateBatchWindow caps how far dispatch may run ahead of the next result
+// to emit. A slow early item then holds at most this many results in memory
+// instead of the rest of the batch.
+const validateBatchWindow = 4 * validateBatchWorkers
+
 type ComparisonService interface {
 	ValidateBatch(ctx context.Context, userID int, req domain.BatchValidationRequest) (*domain.BatchValidationResponse, error)
+	StreamValidateBatch(ctx context.Context, userID int, req domain.BatchValidationRequest, emit func(domain.ValidationResult) error) error
 	ValidateSingle(ctx context.Context, userID int, item domain.ValidationRequestItem) domain.ValidationResult
 	SaveSession(session *domain.ComparisonSession) error
 }
@@ -17,39 +31,133 @@ type ComparisonService interface {
 type comparisonService struct {
 	geoService     GeocodeService
 	historyService *HistoryService
+	classifier     resultClassifier
 }
 
 // NewComparisonService creates a ComparisonService.
 // historySvc is used to persist a summary after each batch completes.
-func NewComparisonService(geoService GeocodeService, historySvc *HistoryService) ComparisonService {
+// areaSvc is optional and enables the OUTSIDE_SERVICE_AREA reason code.
+func NewComparisonService(geoService GeocodeService, historySvc *HistoryService, areaSvc AreaService, cfg *config.Config) ComparisonService {
 	return &comparisonService{
 		geoService:     geoService,
 		historyService: historySvc,
+		classifier:     resultClassifier{areaSvc: areaSvc, minPrecision: cfg.MinVerifiablePrecision},
 	}
 }
 
 func (s *comparisonService) ValidateBatch(ctx context.Context, userID int, req domain.BatchValidationRequest) (*domain.BatchValidationResponse, error) {
 	results := make([]domain.ValidationResult, 0, len(req.Items))
 
-	for _, item := range req.Items {
-		if err := ctx.Err(); err != nil {
-			return nil, err
-		}
-		res := s.ValidateSingle(ctx, userID, item)
+	err := s.StreamValidateBatch(ctx, userID, req, func(res domain.ValidationResult) error {
 		results = append(results, res)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.BatchValidationResponse{Results: results}, nil
+}
+
+// indexedResult carries a worker result back with its position in the request.
+type indexedResult struct {
+	index  int
+	result domain.ValidationResult
+}
+
+// StreamValidateBatch validates items on a bounded worker pool and calls emit
+// for each result in input order, as soon as that result and every result
+// before it are ready. Returning an error from emit stops the remaining work.
+func (s *comparisonService) StreamValidateBatch(ctx context.Context, userID int, req domain.BatchValidationRequest, emit func(domain.ValidationResult) error) error {
+	total := len(req.Items)
+	if total == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := validateBatchWorkers
+	if total < workers {
+		workers = total
+	}
+
+	jobs := make(chan int)
+	done := make(chan indexedResult, workers)
+	// One slot per item dispatched but not yet emitted.
+	window := make(chan struct{}, validateBatchWindow)
+
+	go func() {
+		defer close(jobs)
+		for i := range req.Items {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				res := s.ValidateSingle(ctx, userID, req.Items[i])
+				select {
+				case done <- indexedResult{index: i, result: res}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	// Results arrive out of order; hold them until their predecessors are emitted.
+	pending := make(map[int]domain.ValidationResult)
+	session := &domain.ComparisonSession{UserID: userID}
+	next := 0
+
+	for r := range done {
+		pending[r.index] = r.result
+		for {
+			res, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			if err := emit(res); err != nil {
+				return err
+			}
+			addToSession(session, res)
+			next++
+			<-window
+		}
 	}
 
-	resp := &domain.BatchValidationResponse{Results: results}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	// Asynchronously persist a session summary (non-blocking, best-effort)
 	go func() {
-		session := buildSession(userID, results)
 		if err := s.historyService.SaveSession(session); err != nil {
 			log.Printf("WARN: failed to save comparison session: %v", err)
 		}
 	}()
 
-	return resp, nil
+	return nil
 }
 
 func (s *comparisonService) SaveSession(session *domain.ComparisonSession) error {
@@ -57,54 +165,69 @@ func (s *comparisonService) SaveSession(session *domain.ComparisonSession) error
 }
 
 func (s *comparisonService) ValidateSingle(ctx context.Context, userID int, item domain.ValidationRequestItem) domain.ValidationResult {
+	result := domain.ValidationResult{
+		ID:            item.ID,
+		SystemAddress: item.SystemAddress,
+		FieldLat:      item.FieldLat,
+		FieldLng:      item.FieldLng,
+	}
+
+	if strings.TrimSpace(item.SystemAddress) == "" {
+		result.Error = "empty address"
+		result.SetReason(domain.ReasonAddressEmpty)
+		return result
+	}
+
 	geoRes, err := s.geoService.GeocodeAddress(ctx, userID, item.SystemAddress)
 	if err != nil {
-		return domain.ValidationResult{
-			ID:            item.ID,
-			SystemAddress: item.SystemAddress,
-			FieldLat:      item.FieldLat,
-			FieldLng:      item.FieldLng,
-			Error:         err.Error(),
-		}
+		result.Error = err.Error()
+		result.SetReason(reasonForGeocodeError(err))
+		return result
 	}
 
-	distance := utils.CalculateDistance(geoRes.Lat, geoRes.Lng, item.FieldLat, item.FieldLng)
-	accuracy := utils.EvaluateAccuracy(distance)
+	result.GeoLat = geoRes.Lat
+	result.GeoLng = geoRes.Lng
+	result.Provider = geoRes.Provider
 
-	return domain.ValidationResult{
-		ID:            item.ID,
-		SystemAddress: item.SystemAddress,
-		GeoLat:        geoRes.Lat,
-		GeoLng:        geoRes.Lng,
-		FieldLat:      item.FieldLat,
-		FieldLng:      item.FieldLng,
-		DistanceKm:    distance,
-		AccuracyLevel: accuracy,
-		Provider:      geoRes.Provider,
+	// (0, 0) is the zero value of an omitted coordinate, not a real delivery point.
+	if item.FieldLat == 0 && item.FieldLng == 0 {
+		result.SetReason(domain.ReasonFieldCoordMissing)
+		return result
 	}
+
+	result.DistanceKm = utils.CalculateDistance(geoRes.Lat, geoRes.Lng, item.FieldLat, item.FieldLng)
+	result.AccuracyLevel = s.classifier.evaluateAccuracy(result.DistanceKm, geoRes.Precision)
+	result.SetReason(s.classifier.reasonForMatch(ctx, geoRes, result.AccuracyLevel))
+
+	return result
 }
 
 // buildSession computes summary counts from validation results.
 func buildSession(userID int, results []domain.ValidationResult) *domain.ComparisonSession {
-	s := &domain.ComparisonSession{
-		UserID:     userID,
-		TotalCount: len(results),
-	}
+	s := &domain.ComparisonSession{UserID: userID}
 	for _, r := range results {
-		if r.Error != "" {
-			s.ErrorCount++
-			continue
-		}
-		switch r.AccuracyLevel {
-		case "accurate":
-			s.AccurateCount++
-		case "fairly_accurate":
-			s.FairlyCount++
-		case "inaccurate":
-			s.InaccurateCount++
-		default:
-			s.ErrorCount++
-		}
+		addToSession(s, r)
 	}
 	return s
 }
+
+// addToSession counts one result into the session summary.
+func addToSession(s *domain.ComparisonSession, r domain.ValidationResult) {
+	s.TotalCount++
+	if r.Error != "" {
+		s.ErrorCount++
+		return
+	}
+	switch r.AccuracyLevel {
+	case "accurate":
+		s.AccurateCount++
+	case "fairly_accurate":
+		s.FairlyCount++
+	case "inaccurate":
+		s.InaccurateCount++
+	case domain.AccuracyUnverifiable:
+		s.UnverifiableCount++
+	default:
+		s.ErrorCount++
+	}
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"geoaccuracy-backend/config"
+	"geoaccuracy-backend/internal/domain"
+	"geoaccuracy-backend/internal/repository"
+)
+
+// stubGeocodeService resolves every address through GeocodeFunc.
+type stubGeocodeService struct {
+	GeocodeFunc func(ctx context.Context, userID int, address string) (*domain.GeocodeResponse, error)
+}
+
+func (s *stubGeocodeService) GeocodeAddress(ctx context.Context, userID int, address string) (*domain.GeocodeResponse, error) {
+	return s.GeocodeFunc(ctx, userID, address)
+}
+
+func (s *stubGeocodeService) GeocodeAddressWithOptions(ctx context.Context, userID int, address string, opts GeocodeOptions) (*domain.GeocodeResponse, error) {
+	return s.GeocodeFunc(ctx, userID, address)
+}
+
+// GeocodeBatch resolves addresses one at a time, in order, stopping once ctx
+// is cancelled; a result that arrives after cancellation is dropped.
+func (s *stubGeocodeService) GeocodeBatch(ctx context.Context, userID int, addresses []string, opts GeocodeOptions) (map[string]GeocodeResult, error) {
+	results := make(map[string]GeocodeResult, len(addresses))
+	for _, a := range addresses {
+		if _, done := results[a]; done {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		res, err := s.GeocodeFunc(ctx, userID, a)
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+		results[a] = GeocodeResult{Response: res, Err: err}
+	}
+	return results, nil
+}
+
+func (s *stubGeocodeService) ConfiguredProviders(userID int) []string {
+	return []string{ProviderNominatim}
+}
+
+func newTestComparisonService(t *testing.T, geo GeocodeService) ComparisonService {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	cfg := &config.Config{MinVerifiablePrecision: domain.PrecisionStreet}
+	return NewComparisonService(geo, NewHistoryService(repository.NewHistoryRepository(db)), nil, cfg)
+}
+
+func TestStreamValidateBatch_PreservesOrderUnderConcurrency(t *testing.T) {
+	var inFlight, maxInFlight int32
+
+	geo := &stubGeocodeService{GeocodeFunc: func(ctx context.Context, userID int, address string) (*domain.GeocodeResponse, error) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+
+		var idx int
+		fmt.Sscanf(address, "addr-%d", &idx)
+		// Earlier items finish last so out-of-order completion is guaranteed.
+		time.Sleep(time.Duration(20-idx) * time.Millisecond)
+		return &domain.GeocodeResponse{Lat: -6.2, Lng: 106.8, Provider: "stub"}, nil
+	}}
+	svc := newTestComparisonService(t, geo)
+
+	req := domain.BatchValidationRequest{}
+	for i := 0; i < 20; i++ {
+		req.Items = append(req.Items, domain.ValidationRequestItem{
+			ID:            fmt.Sprintf("item-%d", i),
+			SystemAddress: fmt.Sprintf("addr-%d", i),
+			FieldLat:      -6.2,
+			FieldLng:      106.8,
+		})
+	}
+
+	var got []string
+	err := svc.StreamValidateBatch(context.Background(), 1, req, func(res domain.ValidationResult) error {
+		got = append(got, res.ID)
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Len(t, got, 20)
+	for i, id := range got {
+		assert.Equal(t, fmt.Sprintf("item-%d", i), id)
+	}
+	assert.Greater(t, atomic.LoadInt32(&maxInFlight), int32(1), "items should be geocoded concurrently")
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(validateBatchWorkers))
+}
+
+func TestStreamValidateBatch_StopsOnEmitError(t *testing.T) {
+	var calls int32
+	geo := &stubGeocodeService{GeocodeFunc: func(ctx context.Context, userID int, address string) (*domain.GeocodeResponse, error) {
+		atomic.AddInt32(&calls, 1)
+		return &domain.GeocodeResponse{Lat: 1, Lng: 1}, nil
+	}}
+	svc := newTestComparisonService(t, geo)
+
+	req := domain.BatchValidationRequest{}
+	for i := 0; i < 100; i++ {
+		req.Items = append(req.Items, domain.ValidationRequestItem{ID: fmt.Sprint(i), SystemAddress: "x"})
+	}
+
+	errClientGone := errors.New("client disconnected")
+	err := svc.StreamValidateBatch(context.Background(), 1, req, func(res domain.ValidationResult) error {
+		return errClientGone
+	})
+
+	assert.ErrorIs(t, err, errClientGone)
+	assert.Less(t, atomic.LoadInt32(&calls), int32(100))
+}
+
+func TestStreamValidateBatch_BoundsResultsHeldBehindSlowItem(t *testing.T) {
+	release := make(chan struct{})
+	var maxStarted int32
+	geo := &stubGeocodeService{GeocodeFunc: func(ctx context.Context, userID int, address string) (*domain.GeocodeResponse, error) {
+		var idx int32
+		fmt.Sscanf(address, "addr-%d", &idx)
+		for {
+			m := atomic.LoadInt32(&maxStarted)
+			if idx <= m || atomic.CompareAndSwapInt32(&maxStarted, m, idx) {
+				break
+			}
+		}
+		if idx == 0 {
+			<-release // stuck behind a rate limiter
+		}
+		return &domain.GeocodeResponse{Lat: 1, Lng: 1}, nil
+	}}
+	svc := newTestComparisonService(t, geo)
+
+	n := 10 * validateBatchWindow
+	req := domain.BatchValidationRequest{}
+	for i := 0; i < n; i++ {
+		req.Items = append(req.Items, domain.ValidationRequestItem{ID: fmt.Sprint(i), SystemAddress: fmt.Sprintf("addr-%d", i)})
+	}
+
+	errc := make(chan error, 1)
+	emitted := 0
+	go func() {
+		errc <- svc.StreamValidateBatch(context.Background(), 1, req, func(res domain.ValidationResult) error {
+			emitted++
+			return nil
+		})
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Less(t, atomic.LoadInt32(&maxStarted), int32(validateBatchWindow))
+	close(release)
+	require.NoError(t, <-errc)
+	assert.Equal(t, n, emitted)
+}
+
+func TestValidateBatch_ReturnsResultsInInputOrder(t *testing.T) {
+	geo := &stubGeocodeService{GeocodeFunc: func(ctx context.Context, userID int, address string) (*domain.GeocodeResponse, error) {
+		if address == "bad" {
+			return nil, ErrAddressNotFound
+		}
+		return &domain.GeocodeResponse{Lat: 0, Lng: 0}, nil
+	}}
+	svc := newTestComparisonService(t, geo)
+
+	res, err := svc.ValidateBatch(context.Background(), 1, domain.BatchValidationRequest{Items: []domain.ValidationRequestItem{
+		{ID: "a", SystemAddress: "good"},
+		{ID: "b", SystemAddress: "bad"},
+		{ID: "c", SystemAddress: "good"},
+	}})
+
+	require.NoError(t, err)
+	require.Len(t, res.Results, 3)
+	assert.Equal(t, "a", res.Results[0].ID)
+	assert.Equal(t, "b", res.Results[1].ID)
+	assert.NotEmpty(t, res.Results[1].Error)
+	assert.Equal(t, "c", res.Results[2].ID)
+}
+
+func TestValidateSingle_ReasonCodes(t *testing.T) {
+	geo := &stubGeocodeService{GeocodeFunc: func(ctx context.Context, userID int, address string) (*domain.GeocodeResponse, error) {
+		switch address {
+		case "unknown place":
+			return nil, ErrAddressNotFound
+		case "provider down":
+			return nil, ErrRateLimited
+		case "Surabaya":
+			return &domain.GeocodeResponse{Lat: -7.25, Lng: 112.75, Precision: domain.PrecisionCity}, nil
+		case "Kelurahan Gubeng":
+			return &domain.GeocodeResponse{Lat: -7.27, Lng: 112.75, Precision: domain.PrecisionDistrict}, nil
+		}
+		return &domain.GeocodeResponse{Lat: -6.2, Lng: 106.8, Precision: domain.PrecisionRooftop}, nil
+	}}
+	svc := newTestComparisonService(t, geo)
+
+	tests := []struct {
+		name string
+		item domain.ValidationRequestItem
+		want domain.ReasonCode
+	}{
+		{"empty address", domain.ValidationRequestItem{SystemAddress: "  "}, domain.ReasonAddressEmpty},
+		{"not found", domain.ValidationRequestItem{SystemAddress: "unknown place", FieldLat: 1, FieldLng: 1}, domain.ReasonAddressNotFound},
+		{"all providers failed", domain.ValidationRequestItem{SystemAddress: "provider down", FieldLat: 1, FieldLng: 1}, domain.ReasonProviderAllFailed},
+		{"field coordinate missing", domain.ValidationRequestItem{SystemAddress: "Jl. Sudirman 1"}, domain.ReasonFieldCoordMissing},
+		{"city centroid only", domain.ValidationRequestItem{SystemAddress: "Surabaya", FieldLat: -7.25, FieldLng: 112.75}, domain.ReasonCityLevelMatchOnly},
+		{"below configured precision", domain.ValidationRequestItem{SystemAddress: "Kelurahan Gubeng", FieldLat: -7.3, FieldLng: 112.75}, domain.ReasonLowPrecisionMatch},
+		{"accurate", domain.ValidationRequestItem{SystemAddress: "Jl. Sudirman 1", FieldLat: -6.2, FieldLng: 106.8}, domain.ReasonWithinAccurateThreshold},
+		{"too far", domain.ValidationRequestItem{SystemAddress: "Jl. Sudirman 1", FieldLat: -6.3, FieldLng: 106.8}, domain.ReasonDistanceExceedsThreshold},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := svc.ValidateSingle(context.Background(), 1, tt.item)
+			assert.Equal(t, tt.want, res.ReasonCode)
+			assert.NotEmpty(t, res.Explanation.EN)
+			assert.NotEmpty(t, res.Explanation.ID)
+		})
+	}
+}
+
+func TestValidateSingle_LowPrecisionIsUnverifiable(t *testing.T) {
+	geo := &stubGeocodeService{GeocodeFunc: func(ctx context.Context, userID int, address string) (*domain.GeocodeResponse, error) {
+		return &domain.GeocodeResponse{Lat: -7.25, Lng: 112.75, Precision: domain.PrecisionCity}, nil
+	}}
+	svc := newTestComparisonService(t, geo)
+
+	// 3 km away from a city centroid would otherwise be "inaccurate".
+	res := svc.ValidateSingle(context.Background(), 1, domain.ValidationRequestItem{
+		SystemAddress: "Surabaya", FieldLat: -7.277, FieldLng: 112.75,
+	})
+
+	assert.Equal(t, domain.AccuracyUnverifiable, res.AccuracyLevel)
+
+	session := buildSession(1, []domain.ValidationResult{res})
+	assert.Equal(t, 1, session.UnverifiableCount)
+	assert.Equal(t, 0, session.InaccurateCount)
+	assert.Equal(t, 0, session.ErrorCount)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+const (
+	defaultTimelineEvents = 200
+	maxTimelineEvents     = 1000
+)
+
+// ErrInvalidTimeline is returned for an empty connote or an out-of-range limit.
+var ErrInvalidTimeline = errors.New("invalid timeline request")
+
+type connoteService struct {
+	repo domain.ConnoteRepository
+}
+
+func NewConnoteService(repo domain.ConnoteRepository) domain.ConnoteService {
+	return &connoteService{repo: repo}
+}
+
+// GetTimeline returns the newest limit events (default 200), oldest first.
+func (s *connoteService) GetTimeline(ctx context.Context, userID int64, connote string, limit int) (*domain.ConnoteTimeline, error) {
+	connote = strings.TrimSpace(connote)
+	if connote == "" {
+		return nil, fmt.Errorf("%w: connote is required", ErrInvalidTimeline)
+	}
+	if limit == 0 {
+		limit = defaultTimelineEvents
+	}
+	if limit < 0 || limit > maxTimelineEvents {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidTimeline, maxTimelineEvents)
+	}
+
+	// One extra event tells whether older ones were left out.
+	events, err := s.repo.ListConnoteEvents(ctx, userID, connote, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	timeline := &domain.ConnoteTimeline{Connote: connote, Events: []domain.ConnoteEvent{}}
+	if len(events) > limit {
+		events = events[:limit]
+		timeline.Truncated = true
+	}
+	for i := len(events) - 1; i >= 0; i-- {
+		timeline.Events = append(timeline.Events, events[i])
+	}
+	return timeline, nil
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"geoaccuracy-backend/internal/domain"
+	"geoaccuracy-backend/internal/repository"
+)
+
+var connoteEventTestColumns = []string{
+	"origin", "source", "occurred_at", "batch_ref", "batch_name", "courier_id", "lat", "lng",
+	"system_lat", "system_lng", "distance_km", "accuracy", "geocode_status", "reason_code", "review_status",
+}
+
+func TestGetTimeline(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	svc := NewConnoteService(repository.NewConnoteRepository(db))
+
+	day := time.Date(2026, 9, 1, 8, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`COALESCE(bi.processed_at, bi.created_at)`)+`.*`+regexp.QuoteMeta(`UNION ALL`)+
+		`.*`+regexp.QuoteMeta(`LEFT JOIN batches b ON b.id = CASE WHEN cp.batch_id ~`)+`.*`+regexp.QuoteMeta(`ORDER BY 3 DESC`)).
+		WithArgs("JNE123", int64(7), 3).
+		WillReturnRows(sqlmock.NewRows(connoteEventTestColumns).
+			AddRow(domain.ConnoteOriginCourierEvent, domain.BatchSourceERP, day.AddDate(0, 0, 2), "ERP-SYNC-1-1", "",
+				"K01", -6.2, 106.8, nil, nil, nil, "accurate", "", "", "").
+			AddRow(domain.ConnoteOriginBatchItem, domain.BatchSourceCSV, day.AddDate(0, 0, 1), "b1", "September",
+				"K01", -6.2, 106.8, -6.3, 106.9, 12.5, "inaccurate", "completed", "", "address_corrected").
+			AddRow(domain.ConnoteOriginBatchItem, domain.BatchSourceETL, day, "b0", "Agustus",
+				"K02", nil, nil, nil, nil, nil, "", "pending", "", "unreviewed"))
+
+	timeline, err := svc.GetTimeline(context.Background(), 7, " JNE123 ", 2)
+	require.NoError(t, err)
+	assert.True(t, timeline.Truncated)
+	require.Len(t, timeline.Events, 2)
+	assert.Equal(t, "b1", timeline.Events[0].BatchRef, "oldest first")
+	assert.Equal(t, "accurate", timeline.Events[0].Accuracy, "reviewer override")
+	assert.Equal(t, domain.BatchSourceERP, timeline.Events[1].Source)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	_, err = svc.GetTimeline(context.Background(), 7, " ", 0)
+	assert.ErrorIs(t, err, ErrInvalidTimeline)
+	_, err = svc.GetTimeline(context.Background(), 7, "JNE123", maxTimelineEvents+1)
+	assert.ErrorIs(t, err, ErrInvalidTimeline)
+}
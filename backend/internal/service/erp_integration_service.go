@@ -174,7 +174,7 @@ func (s *erpIntegrationService) ExecuteSyncJob(ctx context.Context, integrationI
 	}
 
 	if payload.BatchID == "" {
-		payload.BatchID = fmt.Sprintf("ERP-SYNC-%d-%d", i.ID, time.Now().Unix())
+		payload.BatchID = fmt.Sprintf("%s%d-%d", domain.ERPSyncBatchPrefix, i.ID, time.Now().Unix())
 	}
 
 	// Forward to WebhookService for standard pipeline processing
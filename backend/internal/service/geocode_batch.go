@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+// geocodeTask is one unique address moving through the provider pools.
+type geocodeTask struct {
+	hash, address  string
+	step           int // index into the provider chain
+	res            *domain.GeocodeResponse
+	lastErr        error
+	premiumSkipped bool
+	cancelled      bool
+}
+
+func (s *geocodeService) GeocodeBatch(ctx context.Context, userID int, addresses []string, opts GeocodeOptions) (map[string]GeocodeResult, error) {
+	results := make(map[string]GeocodeResult, len(addresses))
+
+	// Addresses that normalize alike share one lookup.
+	byHash := make(map[string][]string)
+	var hashes []string
+	for _, a := range addresses {
+		if _, done := results[a]; done {
+			continue
+		}
+		trimmed := strings.TrimSpace(a)
+		if trimmed == "" {
+			results[a] = GeocodeResult{Err: errors.New("empty address")}
+			continue
+		}
+		h := generateHash(normalizeAddress(trimmed))
+		if _, seen := byHash[h]; !seen {
+			hashes = append(hashes, h)
+		}
+		byHash[h] = append(byHash[h], a)
+		results[a] = GeocodeResult{} // placeholder, removed below if unresolved
+	}
+	resolve := func(hash string, r GeocodeResult) {
+		for _, a := range byHash[hash] {
+			results[a] = r
+		}
+		delete(byHash, hash)
+	}
+
+	// 1. One cache query for the whole set.
+	if !opts.BypassCache && len(hashes) > 0 {
+		cached, err := s.geoRepo.GetCachedResults(ctx, hashes)
+		if err != nil {
+			// A cache outage only costs provider calls.
+			log.Printf("[GeocodeBatch] bulk cache lookup failed: %v", err)
+		}
+		for h, c := range cached {
+			resolve(h, GeocodeResult{Response: fromCacheEntry(c)})
+		}
+	}
+
+	var misses []*geocodeTask
+	for _, h := range hashes {
+		if group, ok := byHash[h]; ok {
+			misses = append(misses, &geocodeTask{hash: h, address: strings.TrimSpace(group[0])})
+		}
+	}
+	if len(misses) > 0 {
+		keys := s.loadProviderKeys(userID)
+		chain := s.waterfall(keys)
+		if opts.Provider != "" {
+			chain = []string{opts.Provider}
+		}
+		for _, t := range s.runProviderPools(ctx, chain, keys, opts, misses) {
+			if t.cancelled {
+				continue
+			}
+			if t.res != nil {
+				if !opts.NoCacheWrite {
+					s.cacheResult(t.hash, t.address, t.res)
+				}
+				resolve(t.hash, GeocodeResult{Response: t.res})
+				continue
+			}
+			err := t.lastErr
+			if opts.Provider == "" {
+				err = waterfallError(t.address, t.lastErr, t.premiumSkipped)
+			}
+			resolve(t.hash, GeocodeResult{Err: err})
+		}
+	}
+
+	// Whatever is left was interrupted.
+	for _, group := range byHash {
+		for _, a := range group {
+			delete(results, a)
+		}
+	}
+	return results, ctx.Err()
+}
+
+// runProviderPools starts one worker pool per provider in chain, sized by
+// its concurrency setting. Every task enters the first pool and moves down
+// the chain until a provider resolves it, so a slow or throttled provider
+// only holds up the addresses waiting for it.
+func (s *geocodeService) runProviderPools(ctx context.Context, chain []string, keys providerKeys, opts GeocodeOptions, tasks []*geocodeTask) []*geocodeTask {
+	single := opts.Provider != ""
+	if len(chain) == 0 {
+		return tasks
+	}
+	// Buffered for every task, so forwarding never blocks and the pools
+	// cannot deadlock on each other.
+	queues := make([]chan *geocodeTask, len(chain))
+	for i := range queues {
+		queues[i] = make(chan *geocodeTask, len(tasks))
+	}
+	done := make(chan *geocodeTask, len(tasks))
+
+	forward := func(t *geocodeTask) {
+		t.step++
+		if t.step < len(chain) {
+			queues[t.step] <- t
+		} else {
+			done <- t
+		}
+	}
+	for i, provider := range chain {
+		workers := s.concurrency[provider]
+		if workers < 1 {
+			workers = 1
+		}
+		for w := 0; w < workers; w++ {
+			go func(queue <-chan *geocodeTask) {
+				for t := range queue {
+					if ctx.Err() != nil {
+						t.cancelled = true
+						done <- t
+						continue
+					}
+					if !single && skipPremium(provider, t.address, opts, keys) {
+						t.premiumSkipped = true
+						forward(t)
+						continue
+					}
+					res, err := s.geocodeWith(ctx, provider, t.address, keys)
+					if ctx.Err() != nil {
+						t.cancelled = true
+						done <- t
+						continue
+					}
+					if err == nil && res != nil {
+						t.res = res
+						done <- t
+						continue
+					}
+					t.lastErr = err
+					if !single {
+						log.Printf("[Waterfall] %s failed for '%s': %v. Falling back...", provider, t.address, err)
+					}
+					forward(t)
+				}
+			}(queues[i])
+		}
+	}
+
+	for _, t := range tasks {
+		queues[0] <- t
+	}
+	finished := make([]*geocodeTask, 0, len(tasks))
+	for range tasks {
+		finished = append(finished, <-done)
+	}
+	for _, q := range queues {
+		close(q)
+	}
+	return finished
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+func TestGeocodeBatch_BulkCacheThenProviderPools(t *testing.T) {
+	svc, mGeo, mSet, mTrans := setupTestService()
+	svc.limiters[ProviderNominatim] = rate.NewLimiter(rate.Inf, 1)
+	svc.concurrency[ProviderGeoapify] = 3
+
+	cachedHash := generateHash(normalizeAddress("Jl. Sudirman 1"))
+	mGeo.On("GetCachedResults", mock.Anything, mock.MatchedBy(func(h []string) bool { return len(h) == 6 })).
+		Return(map[string]*domain.GeocodeCache{cachedHash: {AddressHash: cachedHash, Lat: -6.2, Lng: 106.8, Provider: ProviderGeoapify}}, nil).Once()
+	mGeo.On("SaveResult", mock.Anything, mock.Anything).Return(nil)
+	mSet.On("GetByUserID", 1).Return(&domain.UserSettings{GeoapifyKey: "key"}, nil)
+
+	var nominatimCalls, inFlight, maxInFlight int32
+	var mu sync.Mutex
+	geoapifyAddresses := map[string]bool{}
+	mTrans.roundTripFunc = func(req *http.Request) (*http.Response, error) {
+		if strings.Contains(req.URL.Host, "nominatim") {
+			atomic.AddInt32(&nominatimCalls, 1)
+			return jsonResponse(200, `[]`), nil // not found, falls through to Geoapify
+		}
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		geoapifyAddresses[req.URL.Query().Get("text")] = true
+		mu.Unlock()
+		return jsonResponse(200, `{"features":[{"properties":{"lat": -7.25, "lon": 112.75, "city":"Surabaya"}}]}`), nil
+	}
+
+	addresses := []string{"Jl. Sudirman 1", "Jl. A 1", "jl a 1", "Jl. B 2", "Jl. C 3", "Jl. D 4", "Jl. E 5", "Jl. A 1", " "}
+	results, err := svc.GeocodeBatch(context.Background(), 1, addresses, GeocodeOptions{})
+	require.NoError(t, err)
+
+	assert.True(t, results["Jl. Sudirman 1"].Response.FromCache)
+	for _, a := range []string{"Jl. A 1", "jl a 1", "Jl. B 2", "Jl. E 5"} {
+		require.NoError(t, results[a].Err, a)
+		assert.Equal(t, ProviderGeoapify, results[a].Response.Provider)
+		assert.False(t, results[a].Response.FromCache)
+	}
+	assert.Error(t, results[" "].Err)
+
+	// "Jl. A 1" and "jl a 1" normalize alike: five unique misses, each tried
+	// once per provider, with Geoapify never above its pool size.
+	assert.Equal(t, int32(5), nominatimCalls)
+	assert.Len(t, geoapifyAddresses, 5)
+	assert.LessOrEqual(t, maxInFlight, int32(3))
+	mGeo.AssertNotCalled(t, "GetCachedResult", mock.Anything, mock.Anything)
+	mGeo.AssertNumberOfCalls(t, "SaveResult", 5)
+}
+
+func TestGeocodeBatch_Cancelled(t *testing.T) {
+	svc, mGeo, mSet, mTrans := setupTestService()
+	mGeo.On("GetCachedResults", mock.Anything, mock.Anything).Return(map[string]*domain.GeocodeCache{}, nil)
+	mGeo.On("SaveResult", mock.Anything, mock.Anything).Return(nil)
+	mSet.On("GetByUserID", 1).Return(&domain.UserSettings{}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mTrans.roundTripFunc = func(req *http.Request) (*http.Response, error) {
+		cancel() // the first request stops the batch; Nominatim's 1 RPS limiter holds the rest
+		return jsonResponse(200, `[{"lat":"-6.2","lon":"106.8","addresstype":"road"}]`), nil
+	}
+
+	results, err := svc.GeocodeBatch(ctx, 1, []string{"Jl. A 1", "Jl. B 2", "Jl. C 3"}, GeocodeOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, results)
+}
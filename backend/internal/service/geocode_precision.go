@@ -0,0 +1,88 @@
+package service
+
+import "geoaccuracy-backend/internal/domain"
+
+// The helpers below translate each provider's own "how specific is this match"
+// vocabulary into the normalised domain.Precision* levels.
+
+func nominatimPrecision(addressType string) string {
+	switch addressType {
+	case "building", "house", "amenity", "shop", "office", "place", "craft", "tourism", "leisure":
+		return domain.PrecisionRooftop
+	case "road", "street":
+		return domain.PrecisionStreet
+	case "suburb", "neighbourhood", "quarter", "village", "hamlet", "city_district", "postcode":
+		return domain.PrecisionDistrict
+	case "city", "town", "municipality", "county":
+		return domain.PrecisionCity
+	case "state", "region", "province", "country":
+		return domain.PrecisionRegion
+	}
+	return domain.PrecisionUnknown
+}
+
+func geoapifyPrecision(resultType string) string {
+	switch resultType {
+	case "building", "amenity":
+		return domain.PrecisionRooftop
+	case "street":
+		return domain.PrecisionStreet
+	case "suburb", "district", "postcode":
+		return domain.PrecisionDistrict
+	case "city", "county":
+		return domain.PrecisionCity
+	case "state", "country":
+		return domain.PrecisionRegion
+	}
+	return domain.PrecisionUnknown
+}
+
+func positionStackPrecision(resultType string) string {
+	switch resultType {
+	case "address", "venue":
+		return domain.PrecisionRooftop
+	case "street":
+		return domain.PrecisionStreet
+	case "neighbourhood", "borough", "postalcode", "macrohood":
+		return domain.PrecisionDistrict
+	case "locality", "localadmin", "county":
+		return domain.PrecisionCity
+	case "region", "macroregion", "country":
+		return domain.PrecisionRegion
+	}
+	return domain.PrecisionUnknown
+}
+
+// googlePrecision prefers geometry.location_type and falls back to the result
+// types when Google only reports an approximate or centroid location.
+func googlePrecision(locationType string, types []string) string {
+	switch locationType {
+	case "ROOFTOP":
+		return domain.PrecisionRooftop
+	case "RANGE_INTERPOLATED":
+		return domain.PrecisionStreet
+	}
+
+	best := domain.PrecisionUnknown
+	for _, t := range types {
+		var p string
+		switch t {
+		case "street_address", "premise", "subpremise", "establishment", "point_of_interest":
+			p = domain.PrecisionRooftop
+		case "route", "intersection":
+			p = domain.PrecisionStreet
+		case "sublocality", "sublocality_level_1", "sublocality_level_2", "neighborhood", "administrative_area_level_3", "administrative_area_level_4", "postal_code":
+			p = domain.PrecisionDistrict
+		case "locality", "administrative_area_level_2":
+			p = domain.PrecisionCity
+		case "administrative_area_level_1", "country":
+			p = domain.PrecisionRegion
+		default:
+			continue
+		}
+		if domain.PrecisionRank(p) > domain.PrecisionRank(best) {
+			best = p
+		}
+	}
+	return best
+}
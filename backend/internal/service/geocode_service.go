@@ -16,6 +16,7 @@ import (
 
 	"golang.org/x/time/rate"
 
+	"geoaccuracy-backend/config"
 	"geoaccuracy-backend/internal/domain"
 	"geoaccuracy-backend/internal/repository"
 )
@@ -28,29 +29,128 @@ var (
 
 type GeocodeService interface {
 	GeocodeAddress(ctx context.Context, userID int, address string) (*domain.GeocodeResponse, error)
+	GeocodeAddressWithOptions(ctx context.Context, userID int, address string, opts GeocodeOptions) (*domain.GeocodeResponse, error)
+	// GeocodeBatch resolves many addresses at once: cache hits in one query,
+	// the remaining unique addresses through per-provider worker pools. The
+	// result is keyed by the addresses as given. On cancellation it returns
+	// the results finished so far together with ctx.Err().
+	GeocodeBatch(ctx context.Context, userID int, addresses []string, opts GeocodeOptions) (map[string]GeocodeResult, error)
+	// ConfiguredProviders lists, in waterfall order, the providers the user can call.
+	ConfiguredProviders(userID int) []string
+}
+
+// GeocodeResult is the outcome of one address in GeocodeBatch.
+type GeocodeResult struct {
+	Response *domain.GeocodeResponse
+	Err      error
+}
+
+// Provider names as reported in domain.GeocodeResponse.Provider.
+const (
+	ProviderNominatim     = "Nominatim"
+	ProviderGeoapify      = "Geoapify"
+	ProviderPositionStack = "PositionStack"
+	ProviderGoogleMaps    = "GoogleMaps"
+)
+
+// waterfallOrder is cheapest-first: free Nominatim, then freemium, then Google.
+var waterfallOrder = []string{ProviderNominatim, ProviderGeoapify, ProviderPositionStack, ProviderGoogleMaps}
+
+var (
+	ErrUnknownProvider       = errors.New("unknown geocoding provider")
+	ErrProviderNotConfigured = errors.New("geocoding provider has no API key configured")
+	// ErrLowQualityAddress wraps the last provider error when premium providers
+	// were skipped by the address quality policy.
+	ErrLowQualityAddress = errors.New("address quality too low for premium geocoding")
+)
+
+// premiumProviders are billed per request without a free tier and are the
+// ones the address quality policy keeps low-scoring addresses away from.
+var premiumProviders = map[string]bool{ProviderGoogleMaps: true}
+
+// GeocodeOptions tweaks a single lookup. The zero value behaves like GeocodeAddress.
+type GeocodeOptions struct {
+	Provider     string // restrict the lookup to this provider instead of the waterfall
+	BypassCache  bool   // skip the cache read
+	NoCacheWrite bool   // do not store the result, e.g. for benchmarks
+	Background   bool   // yield to interactive and batch lookups, see backgroundLimiters
+
+	// QualityScores are address quality scores already computed, e.g. on
+	// upload, keyed by trimmed address. Other addresses are scored on demand.
+	QualityScores map[string]int
+}
+
+// providerKeys holds the per-user API keys (Nominatim needs none) and the
+// address quality policy; a zero minPremiumQuality disables the policy.
+type providerKeys struct {
+	maps, geoapify, positionStack string
+	minPremiumQuality             int
+}
+
+func (k providerKeys) has(provider string) bool {
+	switch provider {
+	case ProviderNominatim:
+		return true
+	case ProviderGeoapify:
+		return k.geoapify != ""
+	case ProviderPositionStack:
+		return k.positionStack != ""
+	case ProviderGoogleMaps:
+		return k.maps != ""
+	}
+	return false
 }
 
 type geocodeService struct {
 	geoRepo      repository.GeocodeRepository
 	settingsRepo repository.SettingsRepository
-	rateLimiter  *rate.Limiter // Nominatim limiter (1 RPS)
-	httpClient   *http.Client
+	// limiters cap each provider's requests per second across the instance;
+	// concurrency is the worker pool size per provider in GeocodeBatch.
+	limiters    map[string]*rate.Limiter
+	concurrency map[string]int
+	httpClient  *http.Client
+
+	// backgroundLimiters hold Background lookups to half of each provider's
+	// rate on top of limiters, so a benchmark leaves batch jobs the rest.
+	backgroundLimiters map[string]*rate.Limiter
 }
 
-func NewGeocodeService(geoRepo repository.GeocodeRepository, settingsRepo repository.SettingsRepository) GeocodeService {
-	// Nominatim policy strictly demands max 1 request per second
-	limiter := rate.NewLimiter(rate.Every(1*time.Second), 1)
+// Used for providers missing from the configuration. Nominatim's usage
+// policy allows at most one request per second.
+var (
+	defaultProviderRPS         = map[string]float64{ProviderNominatim: 1, ProviderGeoapify: 5, ProviderPositionStack: 5, ProviderGoogleMaps: 25}
+	defaultProviderConcurrency = map[string]int{ProviderNominatim: 1, ProviderGeoapify: 5, ProviderPositionStack: 4, ProviderGoogleMaps: 10}
+)
 
+func NewGeocodeService(geoRepo repository.GeocodeRepository, settingsRepo repository.SettingsRepository, cfg *config.Config) GeocodeService {
 	client := &http.Client{
 		Timeout: 10 * time.Second,
 	}
 
-	return &geocodeService{
+	svc := &geocodeService{
 		geoRepo:      geoRepo,
 		settingsRepo: settingsRepo,
-		rateLimiter:  limiter,
+		limiters:     make(map[string]*rate.Limiter, len(waterfallOrder)),
+		concurrency:  make(map[string]int, len(waterfallOrder)),
 		httpClient:   client,
+
+		backgroundLimiters: make(map[string]*rate.Limiter, len(waterfallOrder)),
 	}
+	for _, p := range waterfallOrder {
+		rps, workers := defaultProviderRPS[p], defaultProviderConcurrency[p]
+		if cfg != nil {
+			if v, ok := cfg.GeocodeRPS[p]; ok && v > 0 {
+				rps = v
+			}
+			if v, ok := cfg.GeocodeConcurrency[p]; ok && v >= 1 {
+				workers = v
+			}
+		}
+		svc.limiters[p] = rate.NewLimiter(rate.Limit(rps), 1)
+		svc.backgroundLimiters[p] = rate.NewLimiter(rate.Limit(rps/2), 1)
+		svc.concurrency[p] = workers
+	}
+	return svc
 }
 
 // normalizeAddress cleans up address formatting so that minor typographic
@@ -72,6 +172,10 @@ func normalizeAddress(s string) string {
 }
 
 func (s *geocodeService) GeocodeAddress(ctx context.Context, userID int, address string) (*domain.GeocodeResponse, error) {
+	return s.GeocodeAddressWithOptions(ctx, userID, address, GeocodeOptions{})
+}
+
+func (s *geocodeService) GeocodeAddressWithOptions(ctx context.Context, userID int, address string, opts GeocodeOptions) (*domain.GeocodeResponse, error) {
 	address = strings.TrimSpace(address)
 	if address == "" {
 		return nil, errors.New("empty address")
@@ -83,79 +187,164 @@ func (s *geocodeService) GeocodeAddress(ctx context.Context, userID int, address
 	addressHash := generateHash(normalizedForHash)
 
 	// 1. Check PostgreSQL Cache
-	cached, err := s.geoRepo.GetCachedResult(ctx, addressHash)
-	if err == nil && cached != nil {
-		return &domain.GeocodeResponse{
-			Address:   cached.OriginalAddress,
-			City:      cached.City,
-			Province:  cached.Province,
-			Lat:       cached.Lat,
-			Lng:       cached.Lng,
-			Provider:  cached.Provider,
-			FromCache: true,
-		}, nil
-	}
-
-	// Fetch API Keys
-	var mapsKey, geoapifyKey, positionStackKey string
-	if userID != 0 {
-		settings, err := s.settingsRepo.GetByUserID(userID)
-		if err == nil && settings != nil {
-			mapsKey = strings.TrimSpace(settings.MapsKey)
-			geoapifyKey = strings.TrimSpace(settings.GeoapifyKey)
-			positionStackKey = strings.TrimSpace(settings.PositionStackKey)
+	if !opts.BypassCache {
+		cached, err := s.geoRepo.GetCachedResult(ctx, addressHash)
+		if err == nil && cached != nil {
+			return fromCacheEntry(cached), nil
 		}
 	}
 
-	// WATERFALL FALLBACK STRATEGY
-	var res *domain.GeocodeResponse
-	var geocodeErr error
+	keys := s.loadProviderKeys(userID)
 
-	// Attempt 1: Nominatim (Free, rate limited at 1 RPS)
-	err = s.rateLimiter.Wait(ctx)
-	if err == nil {
-		res, geocodeErr = s.geocodeNominatim(ctx, address)
-		if geocodeErr == nil && res != nil {
+	// Single-provider lookup (benchmarks, targeted reprocessing)
+	if opts.Provider != "" {
+		if err := s.waitBackground(ctx, opts, opts.Provider); err != nil {
+			return nil, err
+		}
+		res, err := s.geocodeWith(ctx, opts.Provider, address, keys)
+		if err != nil {
+			return nil, err
+		}
+		if !opts.NoCacheWrite {
 			s.cacheResult(addressHash, address, res)
-			return res, nil
 		}
-		log.Printf("[Waterfall] Nominatim failed for '%s': %v. Falling back...", address, geocodeErr)
+		return res, nil
 	}
 
-	// Attempt 2: Geoapify (Freemium)
-	if geoapifyKey != "" {
-		res, geocodeErr = s.geocodeGeoapify(ctx, address, geoapifyKey)
-		if geocodeErr == nil && res != nil {
-			s.cacheResult(addressHash, address, res)
+	// WATERFALL FALLBACK STRATEGY
+	var geocodeErr error
+	premiumSkipped := false
+	for _, provider := range s.waterfall(keys) {
+		if skipPremium(provider, address, opts, keys) {
+			premiumSkipped = true
+			continue
+		}
+		if err := s.waitBackground(ctx, opts, provider); err != nil {
+			return nil, err
+		}
+		res, err := s.geocodeWith(ctx, provider, address, keys)
+		if err == nil && res != nil {
+			if !opts.NoCacheWrite {
+				s.cacheResult(addressHash, address, res)
+			}
 			return res, nil
 		}
-		log.Printf("[Waterfall] Geoapify failed for '%s': %v. Falling back...", address, geocodeErr)
+		geocodeErr = err
+		log.Printf("[Waterfall] %s failed for '%s': %v. Falling back...", provider, address, err)
 	}
+	return nil, waterfallError(address, geocodeErr, premiumSkipped)
+}
 
-	// Attempt 3: PositionStack (Freemium)
-	if positionStackKey != "" {
-		res, geocodeErr = s.geocodePositionStack(ctx, address, positionStackKey)
-		if geocodeErr == nil && res != nil {
-			s.cacheResult(addressHash, address, res)
-			return res, nil
+// waterfall lists the providers the keys allow, cheapest first.
+func (s *geocodeService) waterfall(keys providerKeys) []string {
+	var providers []string
+	for _, p := range waterfallOrder {
+		if keys.has(p) {
+			providers = append(providers, p)
 		}
-		log.Printf("[Waterfall] PositionStack failed for '%s': %v. Falling back...", address, geocodeErr)
 	}
+	return providers
+}
 
-	// Attempt 4: Google Maps (Premium, Final Fallback)
-	if mapsKey != "" {
-		res, geocodeErr = s.geocodeGoogleMaps(ctx, address, mapsKey)
-		if geocodeErr == nil && res != nil {
-			s.cacheResult(addressHash, address, res)
-			return res, nil
+// skipPremium applies the address quality policy: premium providers are not
+// billed for addresses scoring below the user's threshold.
+func skipPremium(provider, address string, opts GeocodeOptions, keys providerKeys) bool {
+	if !premiumProviders[provider] || keys.minPremiumQuality <= 0 {
+		return false
+	}
+	score, ok := opts.QualityScores[strings.TrimSpace(address)]
+	if !ok {
+		score = ScoreAddress(address).Score
+	}
+	if score >= keys.minPremiumQuality {
+		return false
+	}
+	log.Printf("[Waterfall] Skipping %s for low-quality address '%s' (score %d < %d)", provider, address, score, keys.minPremiumQuality)
+	return true
+}
+
+// waterfallError is the error after every provider failed or was skipped.
+func waterfallError(address string, lastErr error, premiumSkipped bool) error {
+	if premiumSkipped && lastErr != nil {
+		return fmt.Errorf("%w: %w", ErrLowQualityAddress, lastErr)
+	}
+	if premiumSkipped {
+		return fmt.Errorf("%w: %s", ErrLowQualityAddress, address)
+	}
+	if lastErr != nil {
+		return lastErr
+	}
+	return fmt.Errorf("all configured geocoding providers failed for address: %s", address)
+}
+
+// fromCacheEntry converts a cache row into the response callers see.
+func fromCacheEntry(c *domain.GeocodeCache) *domain.GeocodeResponse {
+	return &domain.GeocodeResponse{
+		Address:   c.OriginalAddress,
+		City:      c.City,
+		Province:  c.Province,
+		Lat:       c.Lat,
+		Lng:       c.Lng,
+		Provider:  c.Provider,
+		Precision: c.Precision,
+		FromCache: true,
+	}
+}
+
+func (s *geocodeService) ConfiguredProviders(userID int) []string {
+	return s.waterfall(s.loadProviderKeys(userID))
+}
+
+func (s *geocodeService) loadProviderKeys(userID int) providerKeys {
+	var keys providerKeys
+	if userID != 0 {
+		settings, err := s.settingsRepo.GetByUserID(userID)
+		if err == nil && settings != nil {
+			keys.maps = strings.TrimSpace(settings.MapsKey)
+			keys.geoapify = strings.TrimSpace(settings.GeoapifyKey)
+			keys.positionStack = strings.TrimSpace(settings.PositionStackKey)
+			if settings.SkipPremiumLowQuality {
+				keys.minPremiumQuality = settings.MinPremiumQualityScore
+			}
 		}
-		log.Printf("[Waterfall] Google Maps failed for '%s': %v. No more fallbacks.", address, geocodeErr)
+	}
+	return keys
+}
+
+// waitBackground paces a Background lookup before it queues on the shared
+// limiter. Unknown providers are left to geocodeWith to reject.
+func (s *geocodeService) waitBackground(ctx context.Context, opts GeocodeOptions, provider string) error {
+	if l, ok := s.backgroundLimiters[provider]; ok && opts.Background {
+		return l.Wait(ctx)
+	}
+	return nil
+}
+
+// geocodeWith calls exactly one provider after waiting on its rate limiter.
+func (s *geocodeService) geocodeWith(ctx context.Context, provider, address string, keys providerKeys) (*domain.GeocodeResponse, error) {
+	switch provider {
+	case ProviderNominatim, ProviderGeoapify, ProviderPositionStack, ProviderGoogleMaps:
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, provider)
+	}
+	if !keys.has(provider) {
+		return nil, fmt.Errorf("%w: %s", ErrProviderNotConfigured, provider)
+	}
+
+	if err := s.limiters[provider].Wait(ctx); err != nil {
+		return nil, err
 	}
 
-	if geocodeErr != nil {
-		return nil, geocodeErr
+	switch provider {
+	case ProviderNominatim:
+		return s.geocodeNominatim(ctx, address)
+	case ProviderGeoapify:
+		return s.geocodeGeoapify(ctx, address, keys.geoapify)
+	case ProviderPositionStack:
+		return s.geocodePositionStack(ctx, address, keys.positionStack)
+	default:
+		return s.geocodeGoogleMaps(ctx, address, keys.maps)
 	}
-	return nil, fmt.Errorf("all configured geocoding providers failed for address: %s", address)
 }
 
 func (s *geocodeService) geocodeNominatim(ctx context.Context, address string) (*domain.GeocodeResponse, error) {
@@ -181,9 +370,10 @@ func (s *geocodeService) geocodeNominatim(ctx context.Context, address string) (
 	}
 
 	var results []struct {
-		Lat     string `json:"lat"`
-		Lon     string `json:"lon"`
-		Address struct {
+		Lat         string `json:"lat"`
+		Lon         string `json:"lon"`
+		AddressType string `json:"addresstype"`
+		Address     struct {
 			City     string `json:"city"`
 			Town     string `json:"town"`
 			Village  string `json:"village"`
@@ -223,7 +413,8 @@ func (s *geocodeService) geocodeNominatim(ctx context.Context, address string) (
 		Province:  province,
 		Lat:       parsedLat,
 		Lng:       parsedLng,
-		Provider:  "Nominatim",
+		Provider:  ProviderNominatim,
+		Precision: nominatimPrecision(results[0].AddressType),
 		FromCache: false,
 	}, nil
 }
@@ -252,10 +443,11 @@ func (s *geocodeService) geocodeGeoapify(ctx context.Context, address, apiKey st
 	var result struct {
 		Features []struct {
 			Properties struct {
-				City  string  `json:"city"`
-				State string  `json:"state"`
-				Lat   float64 `json:"lat"`
-				Lon   float64 `json:"lon"`
+				City       string  `json:"city"`
+				State      string  `json:"state"`
+				Lat        float64 `json:"lat"`
+				Lon        float64 `json:"lon"`
+				ResultType string  `json:"result_type"`
 			} `json:"properties"`
 		} `json:"features"`
 	}
@@ -275,7 +467,8 @@ func (s *geocodeService) geocodeGeoapify(ctx context.Context, address, apiKey st
 		Province:  props.State,
 		Lat:       props.Lat,
 		Lng:       props.Lon,
-		Provider:  "Geoapify",
+		Provider:  ProviderGeoapify,
+		Precision: geoapifyPrecision(props.ResultType),
 		FromCache: false,
 	}, nil
 }
@@ -307,6 +500,7 @@ func (s *geocodeService) geocodePositionStack(ctx context.Context, address, apiK
 			Longitude float64 `json:"longitude"`
 			Locality  string  `json:"locality"`
 			Region    string  `json:"region"`
+			Type      string  `json:"type"`
 		} `json:"data"`
 	}
 
@@ -325,7 +519,8 @@ func (s *geocodeService) geocodePositionStack(ctx context.Context, address, apiK
 		Province:  data.Region,
 		Lat:       data.Latitude,
 		Lng:       data.Longitude,
-		Provider:  "PositionStack",
+		Provider:  ProviderPositionStack,
+		Precision: positionStackPrecision(data.Type),
 		FromCache: false,
 	}, nil
 }
@@ -357,7 +552,9 @@ func (s *geocodeService) geocodeGoogleMaps(ctx context.Context, address, apiKey
 					Lat float64 `json:"lat"`
 					Lng float64 `json:"lng"`
 				} `json:"location"`
+				LocationType string `json:"location_type"`
 			} `json:"geometry"`
+			Types             []string `json:"types"`
 			AddressComponents []struct {
 				LongName string   `json:"long_name"`
 				Types    []string `json:"types"`
@@ -403,7 +600,8 @@ func (s *geocodeService) geocodeGoogleMaps(ctx context.Context, address, apiKey
 		Province:  province,
 		Lat:       res.Geometry.Location.Lat,
 		Lng:       res.Geometry.Location.Lng,
-		Provider:  "GoogleMaps",
+		Provider:  ProviderGoogleMaps,
+		Precision: googlePrecision(res.Geometry.LocationType, res.Types),
 		FromCache: false,
 	}, nil
 }
@@ -421,6 +619,7 @@ func (s *geocodeService) cacheResult(hash, originalAddress string, res *domain.G
 		Lat:             res.Lat,
 		Lng:             res.Lng,
 		Provider:        res.Provider,
+		Precision:       res.Precision,
 		ExpiresAt:       time.Now().Add(10 * 365 * 24 * time.Hour),
 	}
 	_ = s.geoRepo.SaveResult(context.Background(), cacheEntry) // async safe context
@@ -29,6 +29,15 @@ func (m *mockGeocodeRepo) GetCachedResult(ctx context.Context, hash string) (*do
 	return res, args.Error(1)
 }
 
+func (m *mockGeocodeRepo) GetCachedResults(ctx context.Context, hashes []string) (map[string]*domain.GeocodeCache, error) {
+	args := m.Called(ctx, hashes)
+	var res map[string]*domain.GeocodeCache
+	if args.Get(0) != nil {
+		res = args.Get(0).(map[string]*domain.GeocodeCache)
+	}
+	return res, args.Error(1)
+}
+
 func (m *mockGeocodeRepo) SaveResult(ctx context.Context, c *domain.GeocodeCache) error {
 	return m.Called(ctx, c).Error(0)
 }
@@ -51,6 +60,14 @@ func (m *mockSettingsRepo) Upsert(userID int, mapsKey, geoapifyKey, positionStac
 	return m.Called(userID, mapsKey, geoapifyKey, positionStackKey).Error(0)
 }
 
+func (m *mockSettingsRepo) UpsertQualityPolicy(userID int, skipPremiumLowQuality bool, minPremiumQualityScore int) error {
+	return m.Called(userID, skipPremiumLowQuality, minPremiumQualityScore).Error(0)
+}
+
+func (m *mockSettingsRepo) UpsertRetentionPolicy(userID int, itemRetentionDays, batchRetentionDays int) error {
+	return m.Called(userID, itemRetentionDays, batchRetentionDays).Error(0)
+}
+
 // mockRoundTripper intercepts HTTP requests made by the service
 type mockRoundTripper struct {
 	roundTripFunc func(req *http.Request) (*http.Response, error)
@@ -65,7 +82,7 @@ func setupTestService() (*geocodeService, *mockGeocodeRepo, *mockSettingsRepo, *
 	mSetRepo := new(mockSettingsRepo)
 	mTransport := &mockRoundTripper{}
 
-	svc := NewGeocodeService(mGeoRepo, mSetRepo).(*geocodeService)
+	svc := NewGeocodeService(mGeoRepo, mSetRepo, nil).(*geocodeService)
 	// Inject the mock transport to prevent actual outbound calls
 	svc.httpClient = &http.Client{
 		Transport: mTransport,
@@ -312,3 +329,62 @@ func TestGeocodeAddress_PositionStackFallback(t *testing.T) {
 	mGeo.AssertExpectations(t)
 	mSet.AssertExpectations(t)
 }
+
+func TestGeocodeAddress_LowQualitySkipsPremium(t *testing.T) {
+	svc, mGeo, mSet, mTrans := setupTestService()
+
+	address := "depan masjid"
+	hash := generateHash(normalizeAddress(address))
+
+	mGeo.On("GetCachedResult", mock.Anything, hash).Return(nil, nil)
+	mSet.On("GetByUserID", 1).Return(&domain.UserSettings{
+		MapsKey:                "dummy_google_key",
+		SkipPremiumLowQuality:  true,
+		MinPremiumQualityScore: domain.DefaultMinPremiumQualityScore,
+	}, nil)
+
+	mTrans.roundTripFunc = func(req *http.Request) (*http.Response, error) {
+		if strings.Contains(req.URL.String(), "googleapis.com") {
+			t.Fatalf("premium provider must not be called for a low-quality address")
+		}
+		return jsonResponse(200, `[]`), nil
+	}
+
+	res, err := svc.GeocodeAddress(context.Background(), 1, address)
+
+	assert.Nil(t, res)
+	assert.ErrorIs(t, err, ErrLowQualityAddress)
+	assert.ErrorIs(t, err, ErrAddressNotFound)
+	assert.Equal(t, domain.ReasonAddressLowQuality, reasonForGeocodeError(err))
+}
+
+func TestGeocodeAddress_StoredQualityScoreIsReused(t *testing.T) {
+	svc, mGeo, mSet, mTrans := setupTestService()
+
+	address := "depan masjid"
+	mGeo.On("GetCachedResult", mock.Anything, generateHash(normalizeAddress(address))).Return(nil, nil)
+	mSet.On("GetByUserID", 1).Return(&domain.UserSettings{
+		MapsKey:                "dummy_google_key",
+		SkipPremiumLowQuality:  true,
+		MinPremiumQualityScore: domain.DefaultMinPremiumQualityScore,
+	}, nil)
+
+	googleCalled := false
+	mTrans.roundTripFunc = func(req *http.Request) (*http.Response, error) {
+		if strings.Contains(req.URL.String(), "googleapis.com") {
+			googleCalled = true
+		}
+		return jsonResponse(200, `[]`), nil
+	}
+
+	// The stored score wins over re-scoring the text.
+	opts := GeocodeOptions{NoCacheWrite: true, QualityScores: map[string]int{address: 100}}
+	_, _ = svc.GeocodeAddressWithOptions(context.Background(), 1, address, opts)
+	assert.True(t, googleCalled)
+}
+
+func TestWaterfallError_OnlyPremiumSkipped(t *testing.T) {
+	err := waterfallError("depan masjid", nil, true)
+	assert.ErrorIs(t, err, ErrLowQualityAddress)
+	assert.NotContains(t, err.Error(), "%!")
+}
@@ -27,6 +27,9 @@ type SchedulerService interface {
 	ReloadErpIntegrations(ctx context.Context) error
 	AddOrUpdateErpJob(ctx context.Context, i domain.ErpIntegration) error
 	RemoveErpJob(integrationID int64)
+
+	// AddRetentionJob runs batches.EnforceRetention on the cron spec.
+	AddRetentionJob(spec string, batches domain.BatchService) error
 }
 
 type schedulerService struct {
@@ -117,6 +120,8 @@ func (s *schedulerService) AddOrUpdateJob(ctx context.Context, p domain.Transfor
 						session.FairlyCount++
 					case "inaccurate":
 						session.InaccurateCount++
+					case domain.AccuracyUnverifiable:
+						session.UnverifiableCount++
 					default:
 						session.ErrorCount++
 					}
@@ -225,3 +230,35 @@ func (s *schedulerService) RemoveErpJob(integrationID int64) {
 		log.Printf("Removed scheduled job for ERP Sync [%d]", integrationID)
 	}
 }
+
+// ---------------------------------------------------------
+// Data Retention
+// ---------------------------------------------------------
+
+func (s *schedulerService) AddRetentionJob(spec string, batches domain.BatchService) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entryID, exists := s.jobs["retention"]; exists {
+		s.cronRunner.Remove(entryID)
+		delete(s.jobs, "retention")
+	}
+	if spec == "" || spec == "off" {
+		return nil
+	}
+
+	entryID, err := s.cronRunner.AddFunc(spec, func() {
+		result, err := batches.EnforceRetention(context.Background())
+		if err != nil {
+			log.Printf("ERROR running retention: %v", err)
+		}
+		if result != nil && (result.BatchesDeleted > 0 || result.ItemsPurged > 0) {
+			log.Printf("Retention deleted %d batches and purged items of %d", result.BatchesDeleted, result.ItemsPurged)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("schedule retention %q: %w", spec, err)
+	}
+	s.jobs["retention"] = entryID
+	return nil
+}
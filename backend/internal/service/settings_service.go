@@ -43,6 +43,22 @@ func (s *SettingsService) UpdateSettings(userID int, req domain.UpdateSettingsRe
 	return nil
 }
 
+// UpdateQualityPolicy persists the address quality policy for the user.
+func (s *SettingsService) UpdateQualityPolicy(userID int, req domain.UpdateQualityPolicyRequest) error {
+	if err := s.repo.UpsertQualityPolicy(userID, req.SkipPremiumLowQuality, req.MinPremiumQualityScore); err != nil {
+		return fmt.Errorf("settings service UpdateQualityPolicy: %w", err)
+	}
+	return nil
+}
+
+// UpdateRetentionPolicy persists the data retention policy for the user.
+func (s *SettingsService) UpdateRetentionPolicy(userID int, req domain.UpdateRetentionPolicyRequest) error {
+	if err := s.repo.UpsertRetentionPolicy(userID, req.ItemRetentionDays, req.BatchRetentionDays); err != nil {
+		return fmt.Errorf("settings service UpdateRetentionPolicy: %w", err)
+	}
+	return nil
+}
+
 // TestProviderKey validates the API key against the given provider API.
 // Returns true if the key is valid.
 func (s *SettingsService) TestProviderKey(ctx context.Context, provider, key string) *domain.TestMapsKeyResponse {
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+// ErrInvalidTeam is returned for a team without a name or for removing the
+// owner from their own team.
+var ErrInvalidTeam = errors.New("invalid team")
+
+type teamService struct {
+	repo domain.TeamRepository
+}
+
+func NewTeamService(repo domain.TeamRepository) domain.TeamService {
+	return &teamService{repo: repo}
+}
+
+func (s *teamService) CreateTeam(ctx context.Context, userID int64, name string) (*domain.Team, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidTeam)
+	}
+	team := &domain.Team{Name: name, OwnerID: userID}
+	if err := s.repo.CreateTeam(ctx, team); err != nil {
+		return nil, err
+	}
+	return team, nil
+}
+
+func (s *teamService) ListTeams(ctx context.Context, userID int64) ([]domain.Team, error) {
+	return s.repo.ListTeamsByMember(ctx, userID)
+}
+
+func (s *teamService) GetTeam(ctx context.Context, userID, teamID int64) (*domain.Team, error) {
+	team, err := s.repo.GetTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil || !team.HasMember(userID) {
+		return nil, domain.ErrTeamNotFound
+	}
+	return team, nil
+}
+
+func (s *teamService) DeleteTeam(ctx context.Context, userID, teamID int64) error {
+	if _, err := s.ownedTeam(ctx, userID, teamID); err != nil {
+		return err
+	}
+	return s.repo.DeleteTeam(ctx, teamID)
+}
+
+func (s *teamService) AddTeamMember(ctx context.Context, userID, teamID, memberID int64) error {
+	if _, err := s.ownedTeam(ctx, userID, teamID); err != nil {
+		return err
+	}
+	return s.repo.AddTeamMember(ctx, teamID, memberID)
+}
+
+func (s *teamService) RemoveTeamMember(ctx context.Context, userID, teamID, memberID int64) error {
+	team, err := s.repo.GetTeam(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if team == nil || (team.OwnerID != userID && (memberID != userID || !team.HasMember(userID))) {
+		return domain.ErrTeamNotFound
+	}
+	if memberID == team.OwnerID {
+		return fmt.Errorf("%w: the owner cannot leave the team; delete it instead", ErrInvalidTeam)
+	}
+	return s.repo.RemoveTeamMember(ctx, teamID, memberID)
+}
+
+// ownedTeam returns domain.ErrTeamNotFound unless userID owns the team.
+func (s *teamService) ownedTeam(ctx context.Context, userID, teamID int64) (*domain.Team, error) {
+	team, err := s.repo.GetTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil || team.OwnerID != userID {
+		return nil, domain.ErrTeamNotFound
+	}
+	return team, nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+type memTeamRepo struct {
+	domain.TeamRepository
+	team    *domain.Team
+	removed []int64
+}
+
+func (r *memTeamRepo) GetTeam(ctx context.Context, id int64) (*domain.Team, error) {
+	if r.team == nil || r.team.ID != id {
+		return nil, nil
+	}
+	return r.team, nil
+}
+
+func (r *memTeamRepo) RemoveTeamMember(ctx context.Context, teamID, userID int64) error {
+	r.removed = append(r.removed, userID)
+	return nil
+}
+
+func TestTeamMembership(t *testing.T) {
+	repo := &memTeamRepo{team: &domain.Team{ID: 1, Name: "Ops Surabaya", OwnerID: 7,
+		Members: []domain.TeamMember{{UserID: 7}, {UserID: 8}, {UserID: 9}}}}
+	svc := NewTeamService(repo)
+	ctx := context.Background()
+
+	_, err := svc.GetTeam(ctx, 10, 1)
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound, "non-members do not see the team")
+	_, err = svc.GetTeam(ctx, 8, 1)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, svc.AddTeamMember(ctx, 8, 1, 10), domain.ErrTeamNotFound, "only the owner adds members")
+	assert.ErrorIs(t, svc.RemoveTeamMember(ctx, 8, 1, 9), domain.ErrTeamNotFound, "members only remove themselves")
+	assert.ErrorIs(t, svc.RemoveTeamMember(ctx, 7, 1, 7), ErrInvalidTeam)
+
+	require.NoError(t, svc.RemoveTeamMember(ctx, 8, 1, 8))
+	require.NoError(t, svc.RemoveTeamMember(ctx, 7, 1, 9))
+	assert.Equal(t, []int64{8, 9}, repo.removed)
+
+	_, err = svc.CreateTeam(ctx, 7, "  ")
+	assert.ErrorIs(t, err, ErrInvalidTeam)
+}
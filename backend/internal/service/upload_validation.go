@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+// uploadValidator collects the issues of one upload. The first row of a
+// connote is kept; later rows with the same connote are skipped.
+type uploadValidator struct {
+	report *domain.UploadValidation
+	seen   map[string]int // trimmed connote -> index of its first row in records
+}
+
+func newUploadValidator(mode domain.UploadMode, total int) *uploadValidator {
+	return &uploadValidator{
+		report: &domain.UploadValidation{
+			Mode:      mode,
+			TotalRows: total,
+			Counts:    map[string]int{},
+			Issues:    []domain.UploadIssue{},
+		},
+		seen: make(map[string]int, total),
+	}
+}
+
+func (v *uploadValidator) add(row int, connote, code, message string, skipped bool) {
+	v.report.Counts[code]++
+	if len(v.report.Issues) < maxReportedRowErrors {
+		v.report.Issues = append(v.report.Issues, domain.UploadIssue{
+			Row: row, Connote: connote, Code: code, Message: message, Skipped: skipped,
+		})
+	} else {
+		v.report.IssuesTruncated = true
+	}
+}
+
+// firstRow registers connote at index i and returns the index of an earlier
+// row with the same connote, or -1.
+func (v *uploadValidator) firstRow(connote string, i int) int {
+	key := strings.TrimSpace(connote)
+	if first, ok := v.seen[key]; ok {
+		return first
+	}
+	v.seen[key] = i
+	return -1
+}
+
+// validateSystemRecords returns the records that may be stored and the report.
+func validateSystemRecords(records []domain.SystemRecord, mode domain.UploadMode) ([]domain.SystemRecord, *domain.UploadValidation) {
+	v := newUploadValidator(mode, len(records))
+	valid := make([]domain.SystemRecord, 0, len(records))
+	for i, rec := range records {
+		row := i + 1
+		switch {
+		case strings.TrimSpace(rec.Connote) == "":
+			v.add(row, rec.Connote, domain.IssueMissingConnote, "connote is empty", true)
+			continue
+		case strings.TrimSpace(rec.SystemAddress) == "":
+			v.add(row, rec.Connote, domain.IssueMissingAddress, "system address is empty", true)
+			continue
+		}
+		if first := v.firstRow(rec.Connote, i); first >= 0 {
+			msg := fmt.Sprintf("duplicate of row %d", first+1)
+			if records[first].SystemAddress != rec.SystemAddress {
+				msg += " with a different address"
+			}
+			v.add(row, rec.Connote, domain.IssueDuplicateConnote, msg, true)
+			continue
+		}
+		valid = append(valid, rec)
+	}
+	v.report.ValidRows = len(valid)
+	v.report.SkippedRows = len(records) - len(valid)
+	return valid, v.report
+}
+
+// validateFieldRecords returns the records that may be stored and the report.
+// A non-numeric courier ID is dropped from the record but the row is kept.
+func validateFieldRecords(records []domain.FieldRecord, mode domain.UploadMode) ([]domain.FieldRecord, *domain.UploadValidation) {
+	v := newUploadValidator(mode, len(records))
+	valid := make([]domain.FieldRecord, 0, len(records))
+	clean := 0
+	for i, rec := range records {
+		row := i + 1
+		if strings.TrimSpace(rec.Connote) == "" {
+			v.add(row, rec.Connote, domain.IssueMissingConnote, "connote is empty", true)
+			continue
+		}
+		if msg := checkFieldCoordinate(rec.FieldLat, rec.FieldLng); msg != "" {
+			v.add(row, rec.Connote, domain.IssueInvalidCoordinate, msg, true)
+			continue
+		}
+		if first := v.firstRow(rec.Connote, i); first >= 0 {
+			prev := records[first]
+			if prev.FieldLat == rec.FieldLat && prev.FieldLng == rec.FieldLng {
+				v.add(row, rec.Connote, domain.IssueDuplicateConnote, fmt.Sprintf("duplicate of row %d", first+1), true)
+			} else {
+				v.add(row, rec.Connote, domain.IssueConflictingReport,
+					fmt.Sprintf("coordinates %.6f,%.6f conflict with row %d (%.6f,%.6f)",
+						rec.FieldLat, rec.FieldLng, first+1, prev.FieldLat, prev.FieldLng), true)
+			}
+			continue
+		}
+		if id := strings.TrimSpace(rec.ReportedBy); id != "" && !isDigits(id) {
+			v.add(row, rec.Connote, domain.IssueInvalidCourierID, fmt.Sprintf("courier ID %q is not numeric and was dropped", id), false)
+			rec.ReportedBy = ""
+		} else {
+			clean++
+		}
+		valid = append(valid, rec)
+	}
+	v.report.ValidRows = clean
+	v.report.SkippedRows = len(records) - len(valid)
+	return valid, v.report
+}
+
+// checkFieldCoordinate explains why a GPS fix is unusable, or returns "".
+// 0,0 is what devices report without a fix and what a missing JSON field decodes to.
+func checkFieldCoordinate(lat, lng float64) string {
+	switch {
+	case lat < -90 || lat > 90:
+		return fmt.Sprintf("latitude %v out of range", lat)
+	case lng < -180 || lng > 180:
+		return fmt.Sprintf("longitude %v out of range", lng)
+	case lat == 0 && lng == 0:
+		return "coordinate is 0,0 (no GPS fix)"
+	}
+	return ""
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeUploadMode(mode domain.UploadMode) domain.UploadMode {
+	if mode == domain.UploadModeStrict {
+		return mode
+	}
+	return domain.UploadModeLenient
+}
+
+// rejectUpload reports whether a validated upload must not be stored: strict
+// uploads are all or nothing. Lenient uploads store their valid rows and keep
+// the issues on the batch.
+func rejectUpload(v *domain.UploadValidation) bool {
+	v.Rejected = v.Mode == domain.UploadModeStrict && v.HasIssues()
+	return v.Rejected
+}
+
+func (s *batchService) ListUploadIssues(ctx context.Context, userID int64, batchID uuid.UUID) ([]domain.BatchUploadIssue, error) {
+	if err := s.verifyBatchAccess(ctx, batchID, userID, domain.BatchPermissionViewer); err != nil {
+		return nil, err
+	}
+	return s.batchRepo.ListUploadIssues(ctx, batchID)
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"geoaccuracy-backend/internal/domain"
+)
+
+type uploadIssueRepo struct {
+	ownedBatchRepo
+	upserted []domain.BatchItem
+	issues   map[string][]domain.UploadIssue
+}
+
+func (r *uploadIssueRepo) UpsertBatchItems(ctx context.Context, items []domain.BatchItem) error {
+	r.upserted = append(r.upserted, items...)
+	return nil
+}
+
+func (r *uploadIssueRepo) ReplaceUploadIssues(ctx context.Context, batchID uuid.UUID, source string, issues []domain.UploadIssue) error {
+	if r.issues == nil {
+		r.issues = map[string][]domain.UploadIssue{}
+	}
+	r.issues[source] = issues
+	return nil
+}
+
+func TestValidateSystemRecords(t *testing.T) {
+	records := []domain.SystemRecord{
+		{Connote: "R1", SystemAddress: "Jl. Sudirman 1"},
+		{Connote: "", SystemAddress: "Jl. Thamrin 2"},
+		{Connote: "R2", SystemAddress: "  "},
+		{Connote: "R1 ", SystemAddress: "Jl. Sudirman 1"},
+		{Connote: "R1", SystemAddress: "Jl. Gatot Subroto 3"},
+		{Connote: "R3", SystemAddress: "Jl. Asia Afrika 4"},
+	}
+
+	valid, v := validateSystemRecords(records, domain.UploadModeLenient)
+
+	require.Len(t, valid, 2)
+	assert.Equal(t, "R1", valid[0].Connote)
+	assert.Equal(t, "R3", valid[1].Connote)
+	assert.Equal(t, 6, v.TotalRows)
+	assert.Equal(t, 2, v.ValidRows)
+	assert.Equal(t, 4, v.SkippedRows)
+	assert.Equal(t, map[string]int{
+		domain.IssueMissingConnote:   1,
+		domain.IssueMissingAddress:   1,
+		domain.IssueDuplicateConnote: 2,
+	}, v.Counts)
+	require.Len(t, v.Issues, 4)
+	assert.Equal(t, domain.UploadIssue{Row: 4, Connote: "R1 ", Code: domain.IssueDuplicateConnote, Message: "duplicate of row 1", Skipped: true}, v.Issues[2])
+	assert.Equal(t, "duplicate of row 1 with a different address", v.Issues[3].Message)
+}
+
+func TestValidateFieldRecords(t *testing.T) {
+	records := []domain.FieldRecord{
+		{Connote: "R1", FieldLat: -6.2, FieldLng: 106.8, ReportedBy: "101"},
+		{Connote: "R2", FieldLat: 95, FieldLng: 106.8},
+		{Connote: "R3", FieldLat: -6.2, FieldLng: 190},
+		{Connote: "R4"},
+		{Connote: "R1", FieldLat: -6.2, FieldLng: 106.8},
+		{Connote: "R1", FieldLat: -6.3, FieldLng: 106.9},
+		{Connote: "R5", FieldLat: -7.25, FieldLng: 112.75, ReportedBy: "kurir-7"},
+	}
+
+	valid, v := validateFieldRecords(records, domain.UploadModeLenient)
+
+	require.Len(t, valid, 2)
+	assert.Equal(t, "101", valid[0].ReportedBy)
+	assert.Equal(t, "R5", valid[1].Connote)
+	assert.Empty(t, valid[1].ReportedBy, "non-numeric courier ID is dropped")
+	assert.Equal(t, 1, v.ValidRows)
+	assert.Equal(t, 5, v.SkippedRows)
+	assert.Equal(t, map[string]int{
+		domain.IssueInvalidCoordinate: 3,
+		domain.IssueDuplicateConnote:  1,
+		domain.IssueConflictingReport: 1,
+		domain.IssueInvalidCourierID:  1,
+	}, v.Counts)
+	assert.Equal(t, "latitude 95 out of range", v.Issues[0].Message)
+	assert.Equal(t, "coordinate is 0,0 (no GPS fix)", v.Issues[2].Message)
+	assert.Equal(t, 6, v.Issues[4].Row)
+	assert.Contains(t, v.Issues[4].Message, "conflict with row 1")
+	assert.False(t, v.Issues[5].Skipped)
+}
+
+func TestUploadFieldData_Modes(t *testing.T) {
+	batchID := uuid.New()
+	records := []domain.FieldRecord{
+		{Connote: "R1", FieldLat: -6.2, FieldLng: 106.8},
+		{Connote: "R2", FieldLat: 91, FieldLng: 106.8},
+	}
+
+	t.Run("strict rejects and stores nothing", func(t *testing.T) {
+		repo := &uploadIssueRepo{ownedBatchRepo: ownedBatchRepo{batch: &domain.Batch{ID: batchID, UserID: 7}}}
+		svc := &batchService{batchRepo: repo}
+
+		v, err := svc.UploadFieldData(context.Background(), 7, batchID, records, domain.UploadModeStrict)
+
+		assert.ErrorIs(t, err, domain.ErrUploadRejected)
+		require.NotNil(t, v)
+		assert.True(t, v.Rejected)
+		assert.Equal(t, 1, v.Counts[domain.IssueInvalidCoordinate])
+		assert.Empty(t, repo.upserted)
+		assert.Nil(t, repo.issues)
+	})
+
+	t.Run("lenient stores valid rows and records issues", func(t *testing.T) {
+		repo := &uploadIssueRepo{ownedBatchRepo: ownedBatchRepo{batch: &domain.Batch{ID: batchID, UserID: 7}}}
+		svc := &batchService{batchRepo: repo}
+
+		v, err := svc.UploadFieldData(context.Background(), 7, batchID, records, "")
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.UploadModeLenient, v.Mode)
+		assert.False(t, v.Rejected)
+		require.Len(t, repo.upserted, 1)
+		assert.Equal(t, "R1", repo.upserted[0].Connote)
+		require.Len(t, repo.issues[domain.UploadSourceField], 1)
+		assert.Equal(t, 2, repo.issues[domain.UploadSourceField][0].Row)
+	})
+
+	t.Run("strict without issues clears old issues", func(t *testing.T) {
+		repo := &uploadIssueRepo{ownedBatchRepo: ownedBatchRepo{batch: &domain.Batch{ID: batchID, UserID: 7}}}
+		svc := &batchService{batchRepo: repo}
+
+		_, err := svc.UploadFieldData(context.Background(), 7, batchID, records[:1], domain.UploadModeStrict)
+
+		require.NoError(t, err)
+		assert.Len(t, repo.upserted, 1)
+		assert.Empty(t, repo.issues[domain.UploadSourceField])
+		assert.Contains(t, repo.issues, domain.UploadSourceField)
+	})
+}
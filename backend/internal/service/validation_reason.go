@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"geoaccuracy-backend/internal/domain"
+	"geoaccuracy-backend/pkg/utils"
+)
+
+// resultClassifier picks the reason code that best explains a validation outcome.
+// It is shared by the /compare flow and batch processing so both persist the
+// same codes.
+type resultClassifier struct {
+	areaSvc      AreaService // optional; nil disables the service-area check
+	minPrecision string      // empty disables the unverifiable level
+}
+
+// evaluateAccuracy labels a match "unverifiable" when the geocode is coarser
+// than minPrecision and otherwise scores it by distance. Unknown precision
+// (e.g. legacy cache rows) is scored by distance rather than penalised.
+func (c resultClassifier) evaluateAccuracy(distanceKm float64, precision string) string {
+	minRank := domain.PrecisionRank(c.minPrecision)
+	rank := domain.PrecisionRank(precision)
+	if minRank > 0 && rank > 0 && rank < minRank {
+		return domain.AccuracyUnverifiable
+	}
+	return utils.EvaluateAccuracy(distanceKm)
+}
+
+// reasonForGeocodeError maps an error returned by GeocodeService to a reason code.
+func reasonForGeocodeError(err error) domain.ReasonCode {
+	if errors.Is(err, ErrLowQualityAddress) {
+		return domain.ReasonAddressLowQuality
+	}
+	if errors.Is(err, ErrAddressNotFound) {
+		return domain.ReasonAddressNotFound
+	}
+	return domain.ReasonProviderAllFailed
+}
+
+// reasonForMatch explains a successful geocode that was compared against a
+// field coordinate. Precision and service-area problems win over the distance
+// band because they make the distance itself misleading.
+func (c resultClassifier) reasonForMatch(ctx context.Context, geo *domain.GeocodeResponse, accuracyLevel string) domain.ReasonCode {
+	if accuracyLevel == domain.AccuracyUnverifiable {
+		if domain.IsCityLevelOrCoarser(geo.Precision) {
+			return domain.ReasonCityLevelMatchOnly
+		}
+		return domain.ReasonLowPrecisionMatch
+	}
+
+	if c.areaSvc != nil {
+		outside, err := c.areaSvc.IsOutsideServiceAreas(ctx, geo.Lat, geo.Lng)
+		if err != nil {
+			log.Printf("WARN: service area check failed for (%f, %f): %v", geo.Lat, geo.Lng, err)
+		} else if outside {
+			return domain.ReasonOutsideServiceArea
+		}
+	}
+
+	switch accuracyLevel {
+	case "accurate":
+		return domain.ReasonWithinAccurateThreshold
+	case "fairly_accurate":
+		return domain.ReasonWithinFairThreshold
+	default:
+		return domain.ReasonDistanceExceedsThreshold
+	}
+}
@@ -0,0 +1,74 @@
+// Package geoexport writes point and line features to GIS formats (GeoJSON,
+// KML and GeoPackage) one feature at a time, so callers can stream rows
+// straight from the database.
+package geoexport
+
+import "fmt"
+
+// Geometry types supported by the writers.
+const (
+	GeometryPoint      = "POINT"
+	GeometryLineString = "LINESTRING"
+)
+
+// Field types for layer attributes.
+const (
+	FieldText    = "TEXT"
+	FieldReal    = "REAL"
+	FieldInteger = "INTEGER"
+)
+
+// Geometry is a WGS84 point or line string. Coordinates are [lng, lat].
+type Geometry struct {
+	Type   string
+	Coords [][2]float64
+}
+
+// Point returns a point geometry.
+func Point(lng, lat float64) Geometry {
+	return Geometry{Type: GeometryPoint, Coords: [][2]float64{{lng, lat}}}
+}
+
+// LineString returns a line through the given [lng, lat] coordinates.
+func LineString(coords ...[2]float64) Geometry {
+	return Geometry{Type: GeometryLineString, Coords: coords}
+}
+
+// Field is one attribute column of a layer.
+type Field struct {
+	Name string
+	Type string
+}
+
+// Layer groups features of one geometry type. GeoPackage turns each layer into
+// a table; GeoJSON and KML record it as the "layer" property.
+type Layer struct {
+	Name         string
+	GeometryType string
+	Fields       []Field
+}
+
+// Feature is a single geometry with its attributes. Properties are keyed by
+// field name; missing or nil values are written as null.
+type Feature struct {
+	Layer      string
+	Name       string // KML placemark name
+	Style      string // KML style id
+	Geometry   Geometry
+	Properties map[string]interface{}
+}
+
+// Writer streams features. Close must be called to finish the document.
+type Writer interface {
+	WriteFeature(f Feature) error
+	Close() error
+}
+
+func findLayer(layers []Layer, name string) (*Layer, error) {
+	for i := range layers {
+		if layers[i].Name == name {
+			return &layers[i], nil
+		}
+	}
+	return nil, fmt.Errorf("geoexport: unknown layer %q", name)
+}
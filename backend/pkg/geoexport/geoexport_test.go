@@ -0,0 +1,111 @@
+package geoexport
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testLayers = []Layer{
+	{Name: "points", GeometryType: GeometryPoint, Fields: []Field{{Name: "connote", Type: FieldText}, {Name: "distance_m", Type: FieldReal}}},
+	{Name: "lines", GeometryType: GeometryLineString, Fields: []Field{{Name: "connote", Type: FieldText}}},
+}
+
+var testFeatures = []Feature{
+	{Layer: "points", Name: "R1 <field>", Style: "ok", Geometry: Point(106.8, -6.2),
+		Properties: map[string]interface{}{"connote": "R1", "distance_m": 42.5}},
+	{Layer: "lines", Name: "R1", Geometry: LineString([2]float64{106.8, -6.2}, [2]float64{106.9, -6.1}),
+		Properties: map[string]interface{}{"connote": "R1"}},
+}
+
+func writeAll(t *testing.T, w Writer) {
+	t.Helper()
+	for _, f := range testFeatures {
+		require.NoError(t, w.WriteFeature(f))
+	}
+	require.NoError(t, w.Close())
+}
+
+func TestGeoJSONWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewGeoJSONWriter(&buf, testLayers)
+	require.NoError(t, err)
+	writeAll(t, w)
+
+	var fc struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Geometry struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &fc))
+	assert.Equal(t, "FeatureCollection", fc.Type)
+	require.Len(t, fc.Features, 2)
+	assert.Equal(t, "Point", fc.Features[0].Geometry.Type)
+	assert.JSONEq(t, `[106.8,-6.2]`, string(fc.Features[0].Geometry.Coordinates))
+	assert.Equal(t, map[string]interface{}{"layer": "points", "connote": "R1", "distance_m": 42.5}, fc.Features[0].Properties)
+	assert.JSONEq(t, `[[106.8,-6.2],[106.9,-6.1]]`, string(fc.Features[1].Geometry.Coordinates))
+}
+
+func TestKMLWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewKMLWriter(&buf, "Batch & co", testLayers, []KMLStyle{{ID: "ok", Color: "ff00aa00"}})
+	require.NoError(t, err)
+	writeAll(t, w)
+
+	out := buf.String()
+	assert.Contains(t, out, "<name>Batch &amp; co</name>")
+	assert.Contains(t, out, `<Style id="ok"><IconStyle><color>ff00aa00</color>`)
+	assert.Contains(t, out, "<name>R1 &lt;field&gt;</name><styleUrl>#ok</styleUrl>")
+	assert.Contains(t, out, `<Data name="distance_m"><value>42.5</value></Data>`)
+	assert.Contains(t, out, "<Point><coordinates>106.8,-6.2</coordinates></Point>")
+	assert.Contains(t, out, "<coordinates>106.8,-6.2 106.9,-6.1</coordinates></LineString>")
+	assert.True(t, strings.HasSuffix(out, "</Document></kml>\n"))
+}
+
+func TestGeoPackageWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewGeoPackageWriter(&buf, testLayers)
+	require.NoError(t, err)
+	writeAll(t, w)
+
+	path := filepath.Join(t.TempDir(), "out.gpkg")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o600))
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	var appID int64
+	require.NoError(t, db.QueryRow("PRAGMA application_id").Scan(&appID))
+	assert.Equal(t, int64(gpkgApplicationID), appID)
+
+	var geom []byte
+	var connote string
+	var dist float64
+	require.NoError(t, db.QueryRow(`SELECT geom, connote, distance_m FROM points`).Scan(&geom, &connote, &dist))
+	assert.Equal(t, "R1", connote)
+	assert.Equal(t, 42.5, dist)
+	assert.Equal(t, []byte{'G', 'P', 0, 1}, geom[:4])
+	assert.Equal(t, uint32(1), binary.LittleEndian.Uint32(geom[9:13])) // WKB point after 8-byte header + order byte
+
+	var minX, maxY float64
+	require.NoError(t, db.QueryRow(`SELECT min_x, max_y FROM gpkg_contents WHERE table_name = 'lines'`).Scan(&minX, &maxY))
+	assert.Equal(t, 106.8, minX)
+	assert.Equal(t, -6.1, maxY)
+
+	var geomType string
+	require.NoError(t, db.QueryRow(`SELECT geometry_type_name FROM gpkg_geometry_columns WHERE table_name = 'lines'`).Scan(&geomType))
+	assert.Equal(t, GeometryLineString, geomType)
+}
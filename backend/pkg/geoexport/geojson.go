@@ -0,0 +1,72 @@
+package geoexport
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+type geoJSONWriter struct {
+	w      *bufio.Writer
+	layers []Layer
+	count  int
+}
+
+// NewGeoJSONWriter writes a single FeatureCollection to w.
+func NewGeoJSONWriter(w io.Writer, layers []Layer) (Writer, error) {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString(`{"type":"FeatureCollection","features":[`); err != nil {
+		return nil, err
+	}
+	return &geoJSONWriter{w: bw, layers: layers}, nil
+}
+
+type geoJSONGeometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+type geoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Geometry   geoJSONGeometry        `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+func (g *geoJSONWriter) WriteFeature(f Feature) error {
+	layer, err := findLayer(g.layers, f.Layer)
+	if err != nil {
+		return err
+	}
+
+	props := make(map[string]interface{}, len(layer.Fields)+1)
+	props["layer"] = layer.Name
+	for _, field := range layer.Fields {
+		props[field.Name] = f.Properties[field.Name]
+	}
+	out := geoJSONFeature{Type: "Feature", Properties: props}
+	if f.Geometry.Type == GeometryPoint {
+		out.Geometry = geoJSONGeometry{Type: "Point", Coordinates: f.Geometry.Coords[0]}
+	} else {
+		out.Geometry = geoJSONGeometry{Type: "LineString", Coordinates: f.Geometry.Coords}
+	}
+
+	b, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	if g.count > 0 {
+		if err := g.w.WriteByte(','); err != nil {
+			return err
+		}
+	}
+	g.count++
+	_, err = g.w.Write(b)
+	return err
+}
+
+func (g *geoJSONWriter) Close() error {
+	if _, err := g.w.WriteString("]}\n"); err != nil {
+		return err
+	}
+	return g.w.Flush()
+}
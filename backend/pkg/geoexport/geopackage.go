@@ -0,0 +1,259 @@
+package geoexport
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+// GeoPackage identifiers: application_id "GPKG" and version 1.3.0.
+const (
+	gpkgApplicationID = 0x47504B47
+	gpkgUserVersion   = 10300
+	wgs84SRSID        = 4326
+)
+
+const wgs84Definition = `GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563,AUTHORITY["EPSG","7030"]],AUTHORITY["EPSG","6326"]],PRIMEM["Greenwich",0,AUTHORITY["EPSG","8901"]],UNIT["degree",0.0174532925199433,AUTHORITY["EPSG","9122"]],AUTHORITY["EPSG","4326"]]`
+
+var gpkgSchema = []string{
+	fmt.Sprintf("PRAGMA application_id = %d", gpkgApplicationID),
+	fmt.Sprintf("PRAGMA user_version = %d", gpkgUserVersion),
+	`CREATE TABLE gpkg_spatial_ref_sys (
+		srs_name TEXT NOT NULL,
+		srs_id INTEGER NOT NULL PRIMARY KEY,
+		organization TEXT NOT NULL,
+		organization_coordsys_id INTEGER NOT NULL,
+		definition TEXT NOT NULL,
+		description TEXT)`,
+	`CREATE TABLE gpkg_contents (
+		table_name TEXT NOT NULL PRIMARY KEY,
+		data_type TEXT NOT NULL,
+		identifier TEXT UNIQUE,
+		description TEXT DEFAULT '',
+		last_change DATETIME NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now')),
+		min_x DOUBLE, min_y DOUBLE, max_x DOUBLE, max_y DOUBLE,
+		srs_id INTEGER REFERENCES gpkg_spatial_ref_sys(srs_id))`,
+	`CREATE TABLE gpkg_geometry_columns (
+		table_name TEXT NOT NULL REFERENCES gpkg_contents(table_name),
+		column_name TEXT NOT NULL,
+		geometry_type_name TEXT NOT NULL,
+		srs_id INTEGER NOT NULL REFERENCES gpkg_spatial_ref_sys(srs_id),
+		z TINYINT NOT NULL,
+		m TINYINT NOT NULL,
+		PRIMARY KEY (table_name, column_name))`,
+	`INSERT INTO gpkg_spatial_ref_sys VALUES
+		('Undefined cartesian SRS', -1, 'NONE', -1, 'undefined', 'undefined cartesian coordinate reference system'),
+		('Undefined geographic SRS', 0, 'NONE', 0, 'undefined', 'undefined geographic coordinate reference system')`,
+}
+
+// extent is a layer's bounding box, recorded in gpkg_contents on Close.
+type extent struct {
+	minX, minY, maxX, maxY float64
+	empty                  bool
+}
+
+func (e *extent) add(g Geometry) {
+	for _, c := range g.Coords {
+		if e.empty {
+			e.minX, e.maxX, e.minY, e.maxY = c[0], c[0], c[1], c[1]
+			e.empty = false
+			continue
+		}
+		e.minX, e.maxX = math.Min(e.minX, c[0]), math.Max(e.maxX, c[0])
+		e.minY, e.maxY = math.Min(e.minY, c[1]), math.Max(e.maxY, c[1])
+	}
+}
+
+type gpkgWriter struct {
+	out     io.Writer
+	path    string
+	db      *sql.DB
+	tx      *sql.Tx
+	layers  []Layer
+	inserts map[string]*sql.Stmt
+	extents map[string]*extent
+}
+
+// NewGeoPackageWriter builds a GeoPackage with one table per layer. SQLite
+// needs a seekable file, so features go to a temporary file that Close copies
+// to w and removes.
+func NewGeoPackageWriter(w io.Writer, layers []Layer) (Writer, error) {
+	tmp, err := os.CreateTemp("", "export-*.gpkg")
+	if err != nil {
+		return nil, err
+	}
+	tmp.Close()
+
+	g := &gpkgWriter{
+		out:     w,
+		path:    tmp.Name(),
+		layers:  layers,
+		inserts: make(map[string]*sql.Stmt, len(layers)),
+		extents: make(map[string]*extent, len(layers)),
+	}
+	if err := g.init(); err != nil {
+		g.cleanup()
+		return nil, err
+	}
+	return g, nil
+}
+
+func (g *gpkgWriter) init() error {
+	db, err := sql.Open("sqlite", g.path)
+	if err != nil {
+		return err
+	}
+	db.SetMaxOpenConns(1)
+	g.db = db
+
+	for _, stmt := range gpkgSchema {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("geoexport: gpkg schema: %w", err)
+		}
+	}
+	if _, err := db.Exec(`INSERT INTO gpkg_spatial_ref_sys VALUES ('WGS 84 geodetic', ?, 'EPSG', 4326, ?, 'longitude/latitude coordinates in decimal degrees on the WGS 84 spheroid')`,
+		wgs84SRSID, wgs84Definition); err != nil {
+		return err
+	}
+
+	for _, l := range g.layers {
+		cols := []string{"fid INTEGER PRIMARY KEY AUTOINCREMENT", "geom " + l.GeometryType}
+		for _, f := range l.Fields {
+			cols = append(cols, quoteIdent(f.Name)+" "+f.Type)
+		}
+		if _, err := db.Exec(fmt.Sprintf("CREATE TABLE %s (%s)", quoteIdent(l.Name), strings.Join(cols, ", "))); err != nil {
+			return err
+		}
+		if _, err := db.Exec(`INSERT INTO gpkg_contents (table_name, data_type, identifier, srs_id) VALUES (?, 'features', ?, ?)`,
+			l.Name, l.Name, wgs84SRSID); err != nil {
+			return err
+		}
+		if _, err := db.Exec(`INSERT INTO gpkg_geometry_columns VALUES (?, 'geom', ?, ?, 0, 0)`,
+			l.Name, l.GeometryType, wgs84SRSID); err != nil {
+			return err
+		}
+		g.extents[l.Name] = &extent{empty: true}
+	}
+
+	// One transaction for all features; per-row commits make SQLite crawl.
+	g.tx, err = db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, l := range g.layers {
+		names := []string{"geom"}
+		placeholders := []string{"?"}
+		for _, f := range l.Fields {
+			names = append(names, quoteIdent(f.Name))
+			placeholders = append(placeholders, "?")
+		}
+		stmt, err := g.tx.Prepare(fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+			quoteIdent(l.Name), strings.Join(names, ", "), strings.Join(placeholders, ", ")))
+		if err != nil {
+			return err
+		}
+		g.inserts[l.Name] = stmt
+	}
+	return nil
+}
+
+func (g *gpkgWriter) WriteFeature(f Feature) error {
+	layer, err := findLayer(g.layers, f.Layer)
+	if err != nil {
+		return err
+	}
+	args := make([]interface{}, 0, len(layer.Fields)+1)
+	args = append(args, encodeGPKGGeometry(f.Geometry))
+	for _, field := range layer.Fields {
+		args = append(args, f.Properties[field.Name])
+	}
+	if _, err := g.inserts[layer.Name].Exec(args...); err != nil {
+		return err
+	}
+	g.extents[layer.Name].add(f.Geometry)
+	return nil
+}
+
+func (g *gpkgWriter) Close() error {
+	defer g.cleanup()
+
+	for name, e := range g.extents {
+		if e.empty {
+			continue
+		}
+		if _, err := g.tx.Exec(`UPDATE gpkg_contents SET min_x = ?, min_y = ?, max_x = ?, max_y = ? WHERE table_name = ?`,
+			e.minX, e.minY, e.maxX, e.maxY, name); err != nil {
+			return err
+		}
+	}
+	if err := g.tx.Commit(); err != nil {
+		return err
+	}
+	g.tx = nil
+	if err := g.db.Close(); err != nil {
+		return err
+	}
+	g.db = nil
+
+	f, err := os.Open(g.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(g.out, f)
+	return err
+}
+
+func (g *gpkgWriter) cleanup() {
+	if g.tx != nil {
+		g.tx.Rollback()
+	}
+	if g.db != nil {
+		g.db.Close()
+	}
+	os.Remove(g.path)
+}
+
+// encodeGPKGGeometry returns the GeoPackage binary form: a "GP" header with
+// the SRS id (and an envelope for lines) followed by little-endian WKB.
+func encodeGPKGGeometry(g Geometry) []byte {
+	var buf bytes.Buffer
+	le := binary.LittleEndian
+
+	envelope := g.Type == GeometryLineString
+	flags := byte(1) // little endian
+	if envelope {
+		flags |= 1 << 1 // [minx, maxx, miny, maxy]
+	}
+	buf.Write([]byte{'G', 'P', 0, flags})
+	binary.Write(&buf, le, int32(wgs84SRSID))
+	if envelope {
+		e := extent{empty: true}
+		e.add(g)
+		binary.Write(&buf, le, [4]float64{e.minX, e.maxX, e.minY, e.maxY})
+	}
+
+	buf.WriteByte(1) // WKB little endian
+	if g.Type == GeometryPoint {
+		binary.Write(&buf, le, uint32(1))
+		binary.Write(&buf, le, g.Coords[0])
+		return buf.Bytes()
+	}
+	binary.Write(&buf, le, uint32(2))
+	binary.Write(&buf, le, uint32(len(g.Coords)))
+	for _, c := range g.Coords {
+		binary.Write(&buf, le, c)
+	}
+	return buf.Bytes()
+}
+
+func quoteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
@@ -0,0 +1,113 @@
+package geoexport
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// KMLStyle colours the placemarks that reference it. Color is KML's
+// aabbggrr hex notation, e.g. "ff00aa00" for opaque green.
+type KMLStyle struct {
+	ID    string
+	Color string
+}
+
+type kmlWriter struct {
+	w      *bufio.Writer
+	layers []Layer
+}
+
+// NewKMLWriter writes a KML document named name with the given styles.
+func NewKMLWriter(w io.Writer, name string, layers []Layer, styles []KMLStyle) (Writer, error) {
+	k := &kmlWriter{w: bufio.NewWriter(w), layers: layers}
+	k.w.WriteString(xml.Header)
+	k.w.WriteString(`<kml xmlns="http://www.opengis.net/kml/2.2"><Document>`)
+	k.text("name", name)
+	for _, s := range styles {
+		fmt.Fprintf(k.w, `<Style id="%s"><IconStyle><color>%s</color></IconStyle><LineStyle><color>%s</color><width>2</width></LineStyle></Style>`,
+			escape(s.ID), escape(s.Color), escape(s.Color))
+	}
+	return k, k.flushErr()
+}
+
+func (k *kmlWriter) WriteFeature(f Feature) error {
+	layer, err := findLayer(k.layers, f.Layer)
+	if err != nil {
+		return err
+	}
+
+	k.w.WriteString("<Placemark>")
+	k.text("name", f.Name)
+	if f.Style != "" {
+		k.text("styleUrl", "#"+f.Style)
+	}
+	k.w.WriteString("<ExtendedData>")
+	k.data("layer", layer.Name)
+	for _, field := range layer.Fields {
+		if v := f.Properties[field.Name]; v != nil {
+			k.data(field.Name, formatValue(v))
+		}
+	}
+	k.w.WriteString("</ExtendedData>")
+
+	if f.Geometry.Type == GeometryPoint {
+		k.w.WriteString("<Point><coordinates>")
+	} else {
+		k.w.WriteString("<LineString><tessellate>1</tessellate><coordinates>")
+	}
+	for i, c := range f.Geometry.Coords {
+		if i > 0 {
+			k.w.WriteByte(' ')
+		}
+		fmt.Fprintf(k.w, "%s,%s", strconv.FormatFloat(c[0], 'f', -1, 64), strconv.FormatFloat(c[1], 'f', -1, 64))
+	}
+	if f.Geometry.Type == GeometryPoint {
+		k.w.WriteString("</coordinates></Point>")
+	} else {
+		k.w.WriteString("</coordinates></LineString>")
+	}
+	k.w.WriteString("</Placemark>")
+	return k.flushErr()
+}
+
+func (k *kmlWriter) Close() error {
+	k.w.WriteString("</Document></kml>\n")
+	return k.w.Flush()
+}
+
+func (k *kmlWriter) text(tag, value string) {
+	fmt.Fprintf(k.w, "<%s>%s</%s>", tag, escape(value), tag)
+}
+
+func (k *kmlWriter) data(name, value string) {
+	fmt.Fprintf(k.w, `<Data name="%s"><value>%s</value></Data>`, escape(name), escape(value))
+}
+
+// flushErr surfaces the sticky error bufio.Writer keeps after a failed
+// write, so a dropped connection stops the export early.
+func (k *kmlWriter) flushErr() error {
+	_, err := k.w.Write(nil)
+	return err
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func formatValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,249 @@
+// Package spreadsheet reads tabular uploads (CSV or XLSX) row by row so large
+// files never have to be materialised as a single slice.
+package spreadsheet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// sniffSize is how much of the file is inspected for format, encoding and delimiter.
+const sniffSize = 64 << 10
+
+// Encodings reported by Reader.Encoding.
+const (
+	EncodingUTF8    = "utf-8"
+	EncodingUTF16LE = "utf-16le"
+	EncodingUTF16BE = "utf-16be"
+	EncodingWin1252 = "windows-1252"
+)
+
+// ErrUnsupportedFormat is returned for files that are neither CSV nor XLSX
+// (for example legacy .xls).
+var ErrUnsupportedFormat = errors.New("unsupported spreadsheet format")
+
+// Options tweaks detection. The zero value auto-detects everything.
+type Options struct {
+	Delimiter rune // CSV only; 0 detects among , ; tab and |
+}
+
+// Reader yields rows one at a time. The first row returned is the header.
+type Reader interface {
+	// Read returns the next non-empty row and its 1-based line/row number in
+	// the source file. It returns io.EOF after the last row. A *RowError means
+	// only that row was unreadable and Read may be called again.
+	Read() (row []string, line int, err error)
+	// Format is "csv" or "xlsx".
+	Format() string
+	// Delimiter is the CSV delimiter in use, or 0 for XLSX.
+	Delimiter() rune
+	// Encoding is the detected text encoding (always utf-8 for XLSX).
+	Encoding() string
+	Close() error
+}
+
+// RowError reports a single unparseable row.
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string { return fmt.Sprintf("line %d: %v", e.Line, e.Err) }
+func (e *RowError) Unwrap() error { return e.Err }
+
+// Open detects the format of r from its content (falling back to the file
+// name) and returns a streaming row reader.
+func Open(r io.Reader, filename string, opts Options) (Reader, error) {
+	br := bufio.NewReaderSize(r, sniffSize)
+	head, _ := br.Peek(sniffSize)
+
+	lower := strings.ToLower(filename)
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")) || strings.HasSuffix(lower, ".xlsx"):
+		return openXLSX(br)
+	case bytes.HasPrefix(head, []byte{0xD0, 0xCF, 0x11, 0xE0}) || strings.HasSuffix(lower, ".xls"):
+		return nil, fmt.Errorf("%w: legacy .xls, save as .xlsx or .csv", ErrUnsupportedFormat)
+	}
+	return openCSV(br, head, opts)
+}
+
+type csvReader struct {
+	r         *csv.Reader
+	delimiter rune
+	encoding  string
+}
+
+func openCSV(br *bufio.Reader, head []byte, opts Options) (Reader, error) {
+	var src io.Reader = br
+	encoding := EncodingUTF8
+
+	switch {
+	case bytes.HasPrefix(head, []byte{0xEF, 0xBB, 0xBF}):
+		br.Discard(3)
+		head = head[3:]
+	case bytes.HasPrefix(head, []byte{0xFF, 0xFE}):
+		encoding = EncodingUTF16LE
+		src = unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder().Reader(br)
+	case bytes.HasPrefix(head, []byte{0xFE, 0xFF}):
+		encoding = EncodingUTF16BE
+		src = unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM).NewDecoder().Reader(br)
+	case !looksUTF8(head):
+		// Excel on Windows saves "CSV" in the ANSI code page.
+		encoding = EncodingWin1252
+		src = charmap.Windows1252.NewDecoder().Reader(br)
+	}
+
+	delimiter := opts.Delimiter
+	if delimiter == 0 {
+		sample := head
+		if encoding != EncodingUTF8 {
+			// Detection only needs the first line, which is ASCII in practice
+			// once the zero bytes of UTF-16 are dropped.
+			sample = bytes.ReplaceAll(head, []byte{0}, nil)
+		}
+		delimiter = DetectDelimiter(sample)
+	}
+
+	r := csv.NewReader(src)
+	r.Comma = delimiter
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	return &csvReader{r: r, delimiter: delimiter, encoding: encoding}, nil
+}
+
+func (c *csvReader) Read() ([]string, int, error) {
+	for {
+		row, err := c.r.Read()
+		if err != nil {
+			var pe *csv.ParseError
+			if errors.As(err, &pe) {
+				return nil, pe.StartLine, &RowError{Line: pe.StartLine, Err: pe.Err}
+			}
+			return nil, 0, err
+		}
+		if isEmptyRow(row) {
+			continue
+		}
+		line, _ := c.r.FieldPos(0)
+		return row, line, nil
+	}
+}
+
+func (c *csvReader) Format() string   { return "csv" }
+func (c *csvReader) Delimiter() rune  { return c.delimiter }
+func (c *csvReader) Encoding() string { return c.encoding }
+func (c *csvReader) Close() error     { return nil }
+
+type xlsxReader struct {
+	f    *excelize.File
+	rows *excelize.Rows
+	line int
+}
+
+// openXLSX reads the first sheet. The zip container needs random access, so
+// the file is buffered, but rows are still decoded one at a time.
+func openXLSX(r io.Reader) (Reader, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		f.Close()
+		return nil, fmt.Errorf("%w: workbook has no sheets", ErrUnsupportedFormat)
+	}
+	rows, err := f.Rows(sheets[0])
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &xlsxReader{f: f, rows: rows}, nil
+}
+
+func (x *xlsxReader) Read() ([]string, int, error) {
+	for x.rows.Next() {
+		x.line++
+		row, err := x.rows.Columns()
+		if err != nil {
+			return nil, x.line, &RowError{Line: x.line, Err: err}
+		}
+		if isEmptyRow(row) {
+			continue
+		}
+		return row, x.line, nil
+	}
+	if err := x.rows.Error(); err != nil {
+		return nil, 0, err
+	}
+	return nil, 0, io.EOF
+}
+
+func (x *xlsxReader) Format() string   { return "xlsx" }
+func (x *xlsxReader) Delimiter() rune  { return 0 }
+func (x *xlsxReader) Encoding() string { return EncodingUTF8 }
+
+func (x *xlsxReader) Close() error {
+	x.rows.Close()
+	return x.f.Close()
+}
+
+// DetectDelimiter picks the candidate that occurs most often, outside quotes,
+// on the first line of sample. Comma wins ties and empty input.
+func DetectDelimiter(sample []byte) rune {
+	candidates := []rune{',', ';', '\t', '|'}
+	counts := make(map[rune]int, len(candidates))
+
+	inQuotes := false
+	for _, r := range string(sample) {
+		if r == '"' {
+			inQuotes = !inQuotes
+			continue
+		}
+		if !inQuotes && (r == '\n' || r == '\r') {
+			break
+		}
+		if !inQuotes {
+			counts[r]++
+		}
+	}
+
+	best := ','
+	for _, c := range candidates {
+		if counts[c] > counts[best] {
+			best = c
+		}
+	}
+	return best
+}
+
+// looksUTF8 reports whether b is valid UTF-8, ignoring a rune cut off by the
+// end of the sniffed sample.
+func looksUTF8(b []byte) bool {
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		if r == utf8.RuneError && size <= 1 {
+			return !utf8.FullRune(b)
+		}
+		b = b[size:]
+	}
+	return true
+}
+
+func isEmptyRow(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
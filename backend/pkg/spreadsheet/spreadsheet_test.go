@@ -0,0 +1,124 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func readAll(t *testing.T, rd Reader) [][]string {
+	t.Helper()
+	var rows [][]string
+	for {
+		row, _, err := rd.Read()
+		if err == io.EOF {
+			return rows
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+	}
+}
+
+func TestDetectDelimiter(t *testing.T) {
+	assert.Equal(t, ',', DetectDelimiter([]byte("a,b,c\n1;2;3")))
+	assert.Equal(t, ';', DetectDelimiter([]byte("connote;alamat;\"kota, provinsi\"\n")))
+	assert.Equal(t, '\t', DetectDelimiter([]byte("a\tb\tc")))
+	assert.Equal(t, '|', DetectDelimiter([]byte("a|b|c")))
+	assert.Equal(t, ',', DetectDelimiter(nil))
+}
+
+func TestOpen_CSVEncodings(t *testing.T) {
+	text := "connote;alamat\nA1;Jl. Cempaka Putih\n"
+
+	win1252, err := charmap.Windows1252.NewEncoder().String("connote;alamat\nA1;Café Ñusa\n")
+	require.NoError(t, err)
+	utf16, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().String(text)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		data     string
+		encoding string
+		want     string
+	}{
+		{"utf-8", text, EncodingUTF8, "Jl. Cempaka Putih"},
+		{"utf-8 bom", "\xEF\xBB\xBF" + text, EncodingUTF8, "Jl. Cempaka Putih"},
+		{"utf-16le", utf16, EncodingUTF16LE, "Jl. Cempaka Putih"},
+		{"windows-1252", win1252, EncodingWin1252, "Café Ñusa"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rd, err := Open(bytes.NewReader([]byte(tt.data)), "upload.csv", Options{})
+			require.NoError(t, err)
+			defer rd.Close()
+
+			assert.Equal(t, "csv", rd.Format())
+			assert.Equal(t, tt.encoding, rd.Encoding())
+			assert.Equal(t, ';', rd.Delimiter())
+
+			rows := readAll(t, rd)
+			require.Len(t, rows, 2)
+			assert.Equal(t, []string{"connote", "alamat"}, rows[0])
+			assert.Equal(t, tt.want, rows[1][1])
+		})
+	}
+}
+
+func TestOpen_CSVSkipsEmptyRowsAndReportsLines(t *testing.T) {
+	rd, err := Open(bytes.NewReader([]byte("a,b\n\n,\n1,2\n")), "x.csv", Options{})
+	require.NoError(t, err)
+
+	_, line, err := rd.Read()
+	require.NoError(t, err)
+	assert.Equal(t, 1, line)
+
+	row, line, err := rd.Read()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, row)
+	assert.Equal(t, 4, line)
+
+	_, _, err = rd.Read()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestOpen_XLSX(t *testing.T) {
+	f := excelize.NewFile()
+	require.NoError(t, f.SetSheetRow("Sheet1", "A1", &[]interface{}{"connote", "lat", "lng"}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A2", &[]interface{}{"A1", -6.2, 106.8}))
+	require.NoError(t, f.SetSheetRow("Sheet1", "A4", &[]interface{}{"A2", -7.25, 112.75}))
+	var buf bytes.Buffer
+	require.NoError(t, f.Write(&buf))
+
+	rd, err := Open(&buf, "upload.bin", Options{})
+	require.NoError(t, err)
+	defer rd.Close()
+
+	assert.Equal(t, "xlsx", rd.Format())
+
+	var lines []int
+	var rows [][]string
+	for {
+		row, line, err := rd.Read()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+		lines = append(lines, line)
+	}
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"A2", "-7.25", "112.75"}, rows[2])
+	assert.Equal(t, []int{1, 2, 4}, lines)
+}
+
+func TestOpen_RejectsLegacyXLS(t *testing.T) {
+	_, err := Open(bytes.NewReader([]byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1}), "old.xls", Options{})
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
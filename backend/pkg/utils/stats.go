@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"math"
+	"sort"
+)
+
+// Percentile returns the p-th percentile (0..1) of values using linear
+// interpolation between closest ranks. values is not modified; an empty slice
+// yields 0.
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	pos := p * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	if lo == hi {
+		return sorted[lo]
+	}
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+}
@@ -460,6 +460,12 @@ export interface BatchItem {
     updated_at: string;
 }
 
+export interface BatchItemPage {
+    items: BatchItem[];
+    next_cursor?: string;
+    has_more: boolean;
+}
+
 export interface SystemRecord {
     connote: string;
     recipient_name: string;
@@ -490,7 +496,16 @@ export const batchApi = {
     processBatch: async (batchId: string): Promise<void> => {
         return request<void>('POST', `/api/batches/${batchId}/process`);
     },
+    // Results are paged; follow the cursor to collect the whole batch.
     getBatchResults: async (batchId: string): Promise<BatchItem[]> => {
-        return request<BatchItem[]>('GET', `/api/batches/${batchId}/results`);
+        const items: BatchItem[] = [];
+        let cursor = '';
+        for (;;) {
+            const query = `limit=1000${cursor ? `&cursor=${encodeURIComponent(cursor)}` : ''}`;
+            const page = await request<BatchItemPage>('GET', `/api/batches/${batchId}/results?${query}`);
+            items.push(...page.items);
+            if (!page.has_more || !page.next_cursor) return items;
+            cursor = page.next_cursor;
+        }
     },
 };